@@ -10,8 +10,7 @@ import (
 // Environment provides an isolated Bloblang environment where the available
 // features, functions and methods can be modified.
 type Environment struct {
-	pCtx            parser.Context
-	maxMapRecursion int
+	pCtx parser.Context
 }
 
 // GlobalEnvironment returns the global default environment. Modifying this
@@ -71,12 +70,23 @@ func (e *Environment) NewMapping(blobl string) (*mapping.Executor, error) {
 	if err != nil {
 		return nil, err
 	}
-	if e.maxMapRecursion > 0 {
-		exec.SetMaxMapRecursion(e.maxMapRecursion)
-	}
 	return exec, nil
 }
 
+// LintMapping parses a Bloblang mapping using a deactivated version of the
+// Environment and returns any problems found by a static analysis of it, such
+// as methods called on values of an unsupported type, or variables that are
+// declared and never used.
+//
+// When a parsing error occurs the error will be the type *parser.Error.
+func (e *Environment) LintMapping(blobl string) ([]mapping.Lint, error) {
+	exec, err := parser.ParseMapping(e.pCtx.Deactivated(), blobl)
+	if err != nil {
+		return nil, err
+	}
+	return exec.Lint(), nil
+}
+
 // Deactivated returns a version of the environment where constructors are
 // disabled for all functions and methods, allowing mappings to be parsed and
 // validated but not executed.
@@ -174,7 +184,16 @@ func (e *Environment) WithoutFunctions(names ...string) *Environment {
 // mapping will error out.
 func (e *Environment) WithMaxMapRecursion(n int) *Environment {
 	env := *e
-	env.maxMapRecursion = n
+	env.pCtx = env.pCtx.WithMaxMapRecursion(n)
+	return &env
+}
+
+// WithCoverage returns a copy of the environment where the execution of the
+// statements and branches of mappings parsed from it are recorded by a coverage
+// recorder.
+func (e *Environment) WithCoverage(c *mapping.Coverage) *Environment {
+	env := *e
+	env.pCtx = env.pCtx.WithCoverage(c, "")
 	return &env
 }
 
@@ -0,0 +1,255 @@
+package mapping
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+)
+
+// CoverageKind describes the type of a point within a mapping that execution
+// coverage is recorded for.
+type CoverageKind string
+
+// The kinds of coverage points recorded within a mapping.
+const (
+	CoverageStatement CoverageKind = "statement"
+	CoverageIf        CoverageKind = "if"
+	CoverageElseIf    CoverageKind = "else if"
+	CoverageElse      CoverageKind = "else"
+	CoverageMatchCase CoverageKind = "match case"
+	CoverageCatch     CoverageKind = "catch"
+)
+
+// IsBranch returns true if the coverage kind is a branch of a statement rather
+// than a statement.
+func (k CoverageKind) IsBranch() bool {
+	return k != CoverageStatement
+}
+
+// CoveragePoint is a statement or branch of a mapping, along with the number of
+// times it has been executed.
+type CoveragePoint struct {
+	Kind   CoverageKind
+	Line   int
+	Column int
+
+	hits int64
+}
+
+// Hit records an execution of the coverage point.
+func (p *CoveragePoint) Hit() {
+	atomic.AddInt64(&p.hits, 1)
+}
+
+// Hits returns the number of times the coverage point has been executed.
+func (p *CoveragePoint) Hits() int64 {
+	return atomic.LoadInt64(&p.hits)
+}
+
+// Cover returns a query function that records an execution of a coverage point
+// each time it is executed before executing the wrapped function.
+func (p *CoveragePoint) Cover(fn query.Function) query.Function {
+	return query.ClosureFunction(fn.Annotation(), func(ctx query.FunctionContext) (interface{}, error) {
+		p.Hit()
+		return fn.Exec(ctx)
+	}, fn.QueryTargets)
+}
+
+// CoverOnError returns a query function that records an execution of a
+// coverage point each time the wrapped function returns an error, which is
+// used for recording the execution of fallback branches.
+func (p *CoveragePoint) CoverOnError(fn query.Function) query.Function {
+	return query.ClosureFunction(fn.Annotation(), func(ctx query.FunctionContext) (interface{}, error) {
+		v, err := fn.Exec(ctx)
+		if err != nil {
+			p.Hit()
+		}
+		return v, err
+	}, fn.QueryTargets)
+}
+
+//------------------------------------------------------------------------------
+
+// SourceCoverage records the coverage of the statements and branches of a
+// single mapping source.
+type SourceCoverage struct {
+	name  string
+	input []rune
+
+	mut    sync.Mutex
+	points map[coveragePointKey]*CoveragePoint
+}
+
+type coveragePointKey struct {
+	kind   CoverageKind
+	offset int
+}
+
+// Name returns the name of the mapping source, which is the path of the file
+// it was read from, or an empty string if the mapping was not read from a
+// file.
+func (s *SourceCoverage) Name() string {
+	return s.name
+}
+
+// Mapping returns the mapping source.
+func (s *SourceCoverage) Mapping() string {
+	return string(s.input)
+}
+
+// Point returns a coverage point of a given kind at the beginning of a tailing
+// clip of the mapping source. Repeated calls for the same kind and position
+// return the same point, which allows parsers to backtrack freely.
+func (s *SourceCoverage) Point(kind CoverageKind, clip []rune) *CoveragePoint {
+	key := coveragePointKey{kind: kind, offset: len(s.input) - len(clip)}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if p, exists := s.points[key]; exists {
+		return p
+	}
+	line, col := LineAndColOf(s.input, clip)
+	p := &CoveragePoint{Kind: kind, Line: line, Column: col}
+	s.points[key] = p
+	return p
+}
+
+// Points returns all coverage points of the mapping source sorted by their
+// position.
+func (s *SourceCoverage) Points() []*CoveragePoint {
+	s.mut.Lock()
+	points := make([]*CoveragePoint, 0, len(s.points))
+	for _, p := range s.points {
+		points = append(points, p)
+	}
+	s.mut.Unlock()
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Line != points[j].Line {
+			return points[i].Line < points[j].Line
+		}
+		if points[i].Column != points[j].Column {
+			return points[i].Column < points[j].Column
+		}
+		return points[i].Kind < points[j].Kind
+	})
+	return points
+}
+
+// CoverageSummary describes the proportion of statements and branches of one
+// or more mappings that were executed.
+type CoverageSummary struct {
+	Statements        int
+	CoveredStatements int
+	Branches          int
+	CoveredBranches   int
+}
+
+// Add returns the sum of two coverage summaries.
+func (c CoverageSummary) Add(o CoverageSummary) CoverageSummary {
+	return CoverageSummary{
+		Statements:        c.Statements + o.Statements,
+		CoveredStatements: c.CoveredStatements + o.CoveredStatements,
+		Branches:          c.Branches + o.Branches,
+		CoveredBranches:   c.CoveredBranches + o.CoveredBranches,
+	}
+}
+
+// StatementsPercent returns the percentage of statements executed.
+func (c CoverageSummary) StatementsPercent() float64 {
+	if c.Statements == 0 {
+		return 100
+	}
+	return float64(c.CoveredStatements) / float64(c.Statements) * 100
+}
+
+// BranchesPercent returns the percentage of branches executed.
+func (c CoverageSummary) BranchesPercent() float64 {
+	if c.Branches == 0 {
+		return 100
+	}
+	return float64(c.CoveredBranches) / float64(c.Branches) * 100
+}
+
+// Summary returns a summary of the coverage of the mapping source.
+func (s *SourceCoverage) Summary() (sum CoverageSummary) {
+	for _, p := range s.Points() {
+		if p.Kind.IsBranch() {
+			sum.Branches++
+			if p.Hits() > 0 {
+				sum.CoveredBranches++
+			}
+		} else {
+			sum.Statements++
+			if p.Hits() > 0 {
+				sum.CoveredStatements++
+			}
+		}
+	}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+// Coverage records which statements and branches of parsed mappings have been
+// executed. Mappings that are parsed multiple times from the same source share
+// their coverage.
+type Coverage struct {
+	mut     sync.Mutex
+	sources []*SourceCoverage
+	byKey   map[string]*SourceCoverage
+}
+
+// NewCoverage returns an empty coverage recorder.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		byKey: map[string]*SourceCoverage{},
+	}
+}
+
+// Source returns the coverage of a mapping source, which is created if it does
+// not already exist.
+func (c *Coverage) Source(name string, input []rune) *SourceCoverage {
+	var key strings.Builder
+	key.WriteString(name)
+	key.WriteByte(0)
+	key.WriteString(string(input))
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if s, exists := c.byKey[key.String()]; exists {
+		return s
+	}
+	s := &SourceCoverage{
+		name:   name,
+		input:  input,
+		points: map[coveragePointKey]*CoveragePoint{},
+	}
+	c.byKey[key.String()] = s
+	c.sources = append(c.sources, s)
+	return s
+}
+
+// Sources returns the coverage of all mapping sources in the order they were
+// first parsed.
+func (c *Coverage) Sources() []*SourceCoverage {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	sources := make([]*SourceCoverage, len(c.sources))
+	copy(sources, c.sources)
+	return sources
+}
+
+// Summary returns a summary of the coverage of all mapping sources.
+func (c *Coverage) Summary() (sum CoverageSummary) {
+	for _, s := range c.Sources() {
+		sum = sum.Add(s.Summary())
+	}
+	return
+}
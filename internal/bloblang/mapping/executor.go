@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
 	"github.com/benthosdev/benthos/v4/internal/message"
@@ -219,6 +220,8 @@ func (e *Executor) mapPart(appendTo *message.Part, index int, reference Message)
 			newPart.Set([]byte(t))
 		case []byte:
 			newPart.Set(t)
+		case time.Time:
+			newPart.Set(query.IToBytes(t))
 		default:
 			newPart.SetJSON(newValue)
 		}
@@ -285,17 +288,52 @@ func (e *Executor) Exec(ctx query.FunctionContext) (interface{}, error) {
 
 // ExecOnto a provided assignment context.
 func (e *Executor) ExecOnto(ctx query.FunctionContext, onto AssignmentContext) error {
+	return e.ExecOntoSteps(ctx, onto, nil)
+}
+
+// Step describes a single statement of a mapping that has been executed.
+type Step struct {
+	// The line and column of the statement, these are zero when the position
+	// is unknown.
+	Line   int
+	Column int
+
+	// The target of the assignment.
+	Target TargetPath
+
+	// The result of the statement query, which is query.Nothing when the
+	// assignment was skipped.
+	Value interface{}
+}
+
+// ExecOntoSteps executes the mapping onto a provided assignment context, and
+// calls a function after each statement with a description of the step that
+// was executed, at which point the assignment context reflects the state of
+// the mapping after the statement. If the function returns false then the
+// execution of the mapping is halted. A nil function is ignored.
+func (e *Executor) ExecOntoSteps(ctx query.FunctionContext, onto AssignmentContext, fn func(s Step) bool) error {
 	for _, stmt := range e.statements {
 		res, err := stmt.query.Exec(ctx)
 		if err != nil {
 			return formatExecErr(err, true, e.input, stmt.input)
 		}
-		if _, isNothing := res.(query.Nothing); isNothing {
-			// Skip assignment entirely
+		if _, isNothing := res.(query.Nothing); !isNothing {
+			if err = stmt.assignment.Apply(res, onto); err != nil {
+				return formatExecErr(err, false, e.input, stmt.input)
+			}
+		}
+		if fn == nil {
 			continue
 		}
-		if err = stmt.assignment.Apply(res, onto); err != nil {
-			return formatExecErr(err, false, e.input, stmt.input)
+		step := Step{
+			Target: stmt.assignment.Target(),
+			Value:  res,
+		}
+		if len(e.input) > 0 && len(stmt.input) > 0 {
+			step.Line, step.Column = LineAndColOf(e.input, stmt.input)
+		}
+		if !fn(step) {
+			return nil
 		}
 	}
 	return nil
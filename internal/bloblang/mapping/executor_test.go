@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,6 +53,13 @@ func TestAssignments(t *testing.T) {
 			input:  []part{{Content: `{"bar":"test1","zed":"gone"}`}},
 			output: &part{Content: `bar`},
 		},
+		"map timestamp to root": {
+			mapping: NewExecutor("", nil, nil,
+				NewStatement(nil, NewJSONAssignment(), query.NewLiteralFunction("", time.Unix(1597405526, 123000000).UTC())),
+			),
+			input:  []part{{Content: `{"bar":"test1"}`}},
+			output: &part{Content: `2020-08-14T11:45:26.123Z`},
+		},
 		"append array at root": {
 			mapping: NewExecutor("", nil, nil,
 				NewStatement(nil, NewJSONAssignment(), query.NewLiteralFunction("", []interface{}{})),
@@ -533,3 +541,52 @@ func TestQueries(t *testing.T) {
 		})
 	}
 }
+
+func TestExecOntoSteps(t *testing.T) {
+	input := []rune("root.foo = \"a\"\nlet bar = \"b\"\nroot.baz = nothing")
+	exec := NewExecutor("", input, nil,
+		NewStatement(input, NewJSONAssignment("foo"), query.NewLiteralFunction("", "a")),
+		NewStatement(input[15:], NewVarAssignment("bar"), query.NewLiteralFunction("", "b")),
+		NewStatement(input[29:], NewJSONAssignment("baz"), query.NewLiteralFunction("", query.Nothing(nil))),
+	)
+
+	execSteps := func(stopAfter int) ([]Step, []interface{}, error) {
+		var steps []Step
+		var roots []interface{}
+
+		vars := map[string]interface{}{}
+		var result interface{} = query.Nothing(nil)
+		err := exec.ExecOntoSteps(query.FunctionContext{
+			Vars:     vars,
+			NewValue: &result,
+		}, AssignmentContext{
+			Vars:  vars,
+			Value: &result,
+		}, func(s Step) bool {
+			steps = append(steps, s)
+			roots = append(roots, query.IClone(result))
+			return len(steps) < stopAfter
+		})
+		return steps, roots, err
+	}
+
+	steps, roots, err := execSteps(3)
+	require.NoError(t, err)
+	assert.Equal(t, []Step{
+		{Line: 1, Column: 1, Target: NewTargetPath(TargetValue, "foo"), Value: "a"},
+		{Line: 2, Column: 1, Target: NewTargetPath(TargetVariable, "bar"), Value: "b"},
+		{Line: 3, Column: 1, Target: NewTargetPath(TargetValue, "baz"), Value: query.Nothing(nil)},
+	}, steps)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"foo": "a"},
+		map[string]interface{}{"foo": "a"},
+		map[string]interface{}{"foo": "a"},
+	}, roots)
+	assert.Equal(t, "root.foo", steps[0].Target.String())
+	assert.Equal(t, "$bar", steps[1].Target.String())
+
+	steps, _, err = execSteps(2)
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Equal(t, 2, steps[1].Line)
+}
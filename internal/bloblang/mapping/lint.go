@@ -0,0 +1,136 @@
+package mapping
+
+import (
+	"sort"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+)
+
+// Lint describes a problem found by a static analysis of a mapping.
+type Lint struct {
+	// The line and column of the statement that the problem was found within,
+	// these are zero when the position is unknown.
+	Line   int
+	Column int
+
+	What string
+}
+
+// Lint performs a static analysis of the mapping and returns any problems
+// found that would otherwise only surface at runtime, such as calling methods
+// on values of an unsupported type, variables that are declared but never
+// used, unreachable match cases and metadata keys that are likely misspelt.
+//
+// Function and method calls are only analysed when the mapping was parsed
+// from a deactivated environment.
+func (e *Executor) Lint() []Lint {
+	ctx := query.NewAnalysisContext(e.maps)
+	_ = e.Analyse(ctx)
+
+	// Metadata keys can only be assigned at the root of a mapping, and are
+	// checked against those referenced anywhere in the mapping.
+	keys := map[string][]rune{}
+	for _, stmt := range e.statements {
+		if m, ok := stmt.assignment.(*MetaAssignment); ok && m.key != nil {
+			if _, exists := keys[*m.key]; !exists {
+				keys[*m.key] = stmt.input
+			}
+		}
+	}
+	for k, v := range ctx.MetaKeys {
+		if _, exists := keys[k]; !exists {
+			keys[k] = v
+		}
+	}
+	keyNames := sortedKeys(keys)
+	for i, a := range keyNames {
+		for _, b := range keyNames[i+1:] {
+			if query.SimilarKeys(a, b) {
+				ctx.Input = keys[b]
+				ctx.Report("metadata keys %v and %v are similar, one of them might be misspelt", a, b)
+			}
+		}
+	}
+
+	var lints []Lint
+	for _, l := range ctx.Lints() {
+		lint := Lint{What: l.What}
+		if isTailOf(e.input, l.Input) {
+			lint.Line, lint.Column = LineAndColOf(e.input, l.Input)
+		}
+		lints = append(lints, lint)
+	}
+	sort.SliceStable(lints, func(i, j int) bool {
+		if lints[i].Line == lints[j].Line {
+			return lints[i].Column < lints[j].Column
+		}
+		return lints[i].Line < lints[j].Line
+	})
+	return lints
+}
+
+// Analyse the maps and statements of the mapping, this is also called when the
+// mapping is referenced by a query, such as when a map is applied.
+func (e *Executor) Analyse(ctx *query.AnalysisContext) query.ValueType {
+	if !ctx.FirstVisit(e) {
+		return query.ValueUnknown
+	}
+
+	// Variables are isolated to each mapping, and so are the maps available.
+	prevInput, prevMaps, prevVars := ctx.Input, ctx.Maps, ctx.Vars
+	ctx.Maps, ctx.Vars = e.maps, map[string][]rune{}
+	defer func() {
+		ctx.Input, ctx.Maps, ctx.Vars = prevInput, prevMaps, prevVars
+	}()
+
+	mapNames := make([]string, 0, len(e.maps))
+	for k := range e.maps {
+		mapNames = append(mapNames, k)
+	}
+	sort.Strings(mapNames)
+	for _, k := range mapNames {
+		_ = query.Analyse(ctx, e.maps[k])
+	}
+
+	declared := map[string][]rune{}
+	for _, stmt := range e.statements {
+		ctx.Input = stmt.input
+		_ = query.Analyse(ctx, stmt.query)
+		if v, ok := stmt.assignment.(*VarAssignment); ok {
+			if _, exists := declared[v.name]; !exists {
+				declared[v.name] = stmt.input
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(declared) {
+		if _, used := ctx.Vars[name]; !used {
+			ctx.Input = declared[name]
+			ctx.Report("variable %v is declared but never used", name)
+		}
+	}
+	for _, name := range sortedKeys(ctx.Vars) {
+		if _, exists := declared[name]; !exists {
+			ctx.Input = ctx.Vars[name]
+			ctx.Report("variable %v is referenced but never declared", name)
+		}
+	}
+	return query.ValueUnknown
+}
+
+func sortedKeys(m map[string][]rune) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isTailOf returns true if a clip points to the tail of an input.
+func isTailOf(input, clip []rune) bool {
+	if len(clip) == 0 || len(clip) > len(input) {
+		return false
+	}
+	return &input[len(input)-len(clip)] == &clip[0]
+}
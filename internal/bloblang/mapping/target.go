@@ -1,5 +1,7 @@
 package mapping
 
+import "strings"
+
 // TargetType represents a mapping target type, which is a destination for a
 // query result to be mapped into a message.
 type TargetType int
@@ -26,3 +28,22 @@ func NewTargetPath(t TargetType, path ...string) TargetPath {
 		Path: path,
 	}
 }
+
+// String returns a representation of the target path as it would be written
+// on the left hand side of an assignment, such as `root.foo`, `meta foo` or
+// `$foo`.
+func (t TargetPath) String() string {
+	switch t.Type {
+	case TargetMetadata:
+		if len(t.Path) == 0 {
+			return "meta"
+		}
+		return "meta " + strings.Join(t.Path, ".")
+	case TargetVariable:
+		return "$" + strings.Join(t.Path, ".")
+	}
+	if len(t.Path) == 0 {
+		return "root"
+	}
+	return "root." + strings.Join(t.Path, ".")
+}
@@ -224,3 +224,40 @@ func TestMappingParallelExecution(t *testing.T) {
 		})
 	}
 }
+
+func TestMappingMaxRecursion(t *testing.T) {
+	tests := map[string]string{
+		"map": `map countdown {
+  root = if this > 0 { (this - 1).apply("countdown") } else { "done" }
+}
+root = this.apply("countdown")`,
+		"function": `fn countdown(n) {
+  root = if n > 0 { countdown(n - 1) } else { "done" }
+}
+root = countdown(this)`,
+	}
+
+	for name, blobl := range tests {
+		blobl := blobl
+		t.Run(name, func(t *testing.T) {
+			m, err := GlobalEnvironment().WithMaxMapRecursion(10).NewMapping(blobl)
+			require.NoError(t, err)
+
+			res, err := m.Exec(query.FunctionContext{
+				Maps:     m.Maps(),
+				MsgBatch: message.QuickBatch(nil),
+				Vars:     map[string]interface{}{},
+			}.WithValue(5))
+			require.NoError(t, err)
+			assert.Equal(t, "done", res)
+
+			_, err = m.Exec(query.FunctionContext{
+				Maps:     m.Maps(),
+				MsgBatch: message.QuickBatch(nil),
+				Vars:     map[string]interface{}{},
+			}.WithValue(20))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "exceeded maximum allowed stacks of 10")
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
 )
 
@@ -16,6 +17,14 @@ type Context struct {
 	Methods      *query.MethodSet
 	namedContext *namedContext
 	importer     Importer
+
+	userFunctions map[string]*userFunction
+
+	maxMapRecursion int
+
+	coverage       *mapping.Coverage
+	coverageName   string
+	sourceCoverage *mapping.SourceCoverage
 }
 
 // EmptyContext returns a parser context with no functions, methods or import
@@ -62,6 +71,13 @@ func (pCtx Context) HasNamedContext(name string) bool {
 	return false
 }
 
+// withUserFunctions returns a Context where functions declared within a
+// mapping are added to and resolved from the provided map.
+func (pCtx Context) withUserFunctions(fns map[string]*userFunction) Context {
+	pCtx.userFunctions = fns
+	return pCtx
+}
+
 // InitFunction attempts to initialise a function from the available
 // constructors of the parser context.
 func (pCtx Context) InitFunction(name string, args *query.ParsedParams) (query.Function, error) {
@@ -89,6 +105,59 @@ func (pCtx Context) WithImporterRelativeToFile(pathStr string) Context {
 	return pCtx
 }
 
+// WithCoverage returns a Context where the execution of statements and
+// branches of parsed mappings are recorded by a coverage recorder. The name is
+// used to identify the source of parsed mappings, and should be the path of the
+// file mappings are read from, or empty otherwise.
+func (pCtx Context) WithCoverage(c *mapping.Coverage, name string) Context {
+	pCtx.coverage = c
+	pCtx.coverageName = name
+	return pCtx
+}
+
+// WithMaxMapRecursion returns a Context where the maximum recursion allowed for
+// the maps and functions of parsed mappings is set to a given value.
+func (pCtx Context) WithMaxMapRecursion(n int) Context {
+	pCtx.maxMapRecursion = n
+	return pCtx
+}
+
+// newExecutor creates a mapping executor that respects the maximum recursion
+// of the Context.
+func (pCtx Context) newExecutor(annotation string, input []rune, maps map[string]query.Function, statements ...mapping.Statement) *mapping.Executor {
+	exec := mapping.NewExecutor(annotation, input, maps, statements...)
+	if pCtx.maxMapRecursion > 0 {
+		exec.SetMaxMapRecursion(pCtx.maxMapRecursion)
+	}
+	return exec
+}
+
+func (pCtx Context) withCoverageSource(name string, input []rune) Context {
+	if pCtx.coverage != nil {
+		pCtx.sourceCoverage = pCtx.coverage.Source(name, input)
+	}
+	return pCtx
+}
+
+// cover wraps a query function in order to record its execution as a
+// statement or branch beginning at a tailing clip of the mapping source.
+func (pCtx Context) cover(kind mapping.CoverageKind, clip []rune, fn query.Function) query.Function {
+	if pCtx.sourceCoverage == nil {
+		return fn
+	}
+	return pCtx.sourceCoverage.Point(kind, clip).Cover(fn)
+}
+
+// coverOnError wraps a query function in order to record a branch beginning at
+// a tailing clip of the mapping source as executed whenever the function
+// returns an error.
+func (pCtx Context) coverOnError(kind mapping.CoverageKind, clip []rune, fn query.Function) query.Function {
+	if pCtx.sourceCoverage == nil {
+		return fn
+	}
+	return pCtx.sourceCoverage.Point(kind, clip).CoverOnError(fn)
+}
+
 // Deactivated returns a version of the parser context where all functions and
 // methods exist but can no longer be instantiated. This means it's possible to
 // parse and validate mappings but not execute them. If the context also has an
@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func coverageStrs(s *mapping.SourceCoverage) (strs []string) {
+	for _, p := range s.Points() {
+		strs = append(strs, fmt.Sprintf("%v %v:%v %v", p.Kind, p.Line, p.Column, p.Hits()))
+	}
+	return
+}
+
+func TestMappingCoverage(t *testing.T) {
+	mappingStr := `let x = this.a.number().catch(0)
+root.b = if $x > 10 {
+  "big"
+} else if $x > 5 {
+  "medium"
+} else {
+  "small"
+}
+root.c = match this.kind {
+  "foo" => 1
+  _ => 2
+}`
+
+	cov := mapping.NewCoverage()
+	pCtx := GlobalContext().WithCoverage(cov, "")
+
+	// Parsing the same mapping twice should share coverage.
+	for i := 0; i < 2; i++ {
+		exec, perr := ParseMapping(pCtx, mappingStr)
+		require.Nil(t, perr)
+
+		_, err := exec.MapPart(0, message.QuickBatch([][]byte{
+			[]byte(`{"a":20,"kind":"foo"}`),
+		}))
+		require.NoError(t, err)
+	}
+
+	sources := cov.Sources()
+	require.Len(t, sources, 1)
+	assert.Equal(t, "", sources[0].Name())
+	assert.Equal(t, mappingStr, sources[0].Mapping())
+
+	assert.Equal(t, []string{
+		"statement 1:1 2",
+		"catch 1:25 0",
+		"statement 2:1 2",
+		"if 2:10 2",
+		"else if 4:3 0",
+		"else 6:3 0",
+		"statement 9:1 2",
+		"match case 10:3 2",
+		"match case 11:3 0",
+	}, coverageStrs(sources[0]))
+
+	assert.Equal(t, mapping.CoverageSummary{
+		Statements:        3,
+		CoveredStatements: 3,
+		Branches:          6,
+		CoveredBranches:   2,
+	}, cov.Summary())
+
+	exec, perr := ParseMapping(pCtx, mappingStr)
+	require.Nil(t, perr)
+
+	_, err := exec.MapPart(0, message.QuickBatch([][]byte{
+		[]byte(`{"a":"nope","kind":"bar"}`),
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"statement 1:1 3",
+		"catch 1:25 1",
+		"statement 2:1 3",
+		"if 2:10 2",
+		"else if 4:3 0",
+		"else 6:3 1",
+		"statement 9:1 3",
+		"match case 10:3 2",
+		"match case 11:3 1",
+	}, coverageStrs(sources[0]))
+}
+
+func TestMappingCoverageShorthand(t *testing.T) {
+	cov := mapping.NewCoverage()
+
+	exec, perr := ParseMapping(GlobalContext().WithCoverage(cov, "foo.blobl"), `this.foo.catch("nope")`)
+	require.Nil(t, perr)
+
+	_, err := exec.MapPart(0, message.QuickBatch([][]byte{[]byte(`{"foo":"bar"}`)}))
+	require.NoError(t, err)
+
+	sources := cov.Sources()
+	require.Len(t, sources, 1)
+	assert.Equal(t, "foo.blobl", sources[0].Name())
+	assert.Equal(t, []string{
+		"statement 1:1 1",
+		"catch 1:10 0",
+	}, coverageStrs(sources[0]))
+}
+
+func TestMappingCoverageImports(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	importPath := filepath.Join(tmpDir, "foo.blobl")
+	require.NoError(t, os.WriteFile(importPath, []byte(`map foo {
+  root = if this.a { "yes" } else { "no" }
+}`), 0o644))
+
+	cov := mapping.NewCoverage()
+
+	exec, perr := ParseMapping(GlobalContext().WithCoverage(cov, ""), fmt.Sprintf(`import "%v"
+root = this.apply("foo")`, importPath))
+	require.Nil(t, perr)
+
+	_, err := exec.MapPart(0, message.QuickBatch([][]byte{[]byte(`{"a":true}`)}))
+	require.NoError(t, err)
+
+	sources := cov.Sources()
+	require.Len(t, sources, 2)
+
+	assert.Equal(t, "", sources[0].Name())
+	assert.Equal(t, []string{
+		"statement 2:1 1",
+	}, coverageStrs(sources[0]))
+
+	assert.Equal(t, importPath, sources[1].Name())
+	assert.Equal(t, []string{
+		"statement 2:3 1",
+		"if 2:10 1",
+		"else 2:30 0",
+	}, coverageStrs(sources[1]))
+}
+
+func TestMappingCoverageDisabled(t *testing.T) {
+	exec, perr := ParseMapping(GlobalContext(), `root = this.foo.catch("nope")`)
+	require.Nil(t, perr)
+
+	part, err := exec.MapPart(0, message.QuickBatch([][]byte{[]byte(`{"foo":"bar"}`)}))
+	require.NoError(t, err)
+	assert.Equal(t, `bar`, string(part.Get()))
+}
@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+)
+
+func TestMappingLints(t *testing.T) {
+	tests := map[string]struct {
+		mapping string
+		lints   []mapping.Lint
+	}{
+		"no problems": {
+			mapping: `map thing {
+  root.inner = this.first
+}
+let foo = this.foo.uppercase()
+root.foo = $foo
+root.bar = meta("bar").or("nope").uppercase()
+root.baz = this.baz.apply("thing")`,
+		},
+		"method on wrong type": {
+			mapping: `root.a = this.a
+root.b = count("a").uppercase()`,
+			lints: []mapping.Lint{
+				{Line: 2, Column: 1, What: "method uppercase cannot be called on a number value, expected string or bytes"},
+			},
+		},
+		"method on wrong inferred type": {
+			mapping: `root = "foo".length().uppercase()`,
+			lints: []mapping.Lint{
+				{Line: 1, Column: 1, What: "method uppercase cannot be called on a number value, expected string or bytes"},
+			},
+		},
+		"cannot add": {
+			mapping: `root = count("a") + "foo"`,
+			lints: []mapping.Lint{
+				{Line: 1, Column: 1, What: "cannot add number and string values"},
+			},
+		},
+		"bad if condition": {
+			mapping: `root = if count("a") { "a" } else { "b" }`,
+			lints: []mapping.Lint{
+				{Line: 1, Column: 1, What: "expected bool value for if condition, got number"},
+			},
+		},
+		"unused variable": {
+			mapping: `let foo = "a"
+let bar = "b"
+root = $bar`,
+			lints: []mapping.Lint{
+				{Line: 1, Column: 1, What: "variable foo is declared but never used"},
+			},
+		},
+		"undeclared variable": {
+			mapping: `let foo = "a"
+root = $foo + $bar`,
+			lints: []mapping.Lint{
+				{Line: 2, Column: 1, What: "variable bar is referenced but never declared"},
+			},
+		},
+		"variables within maps": {
+			mapping: `map thing {
+  let foo = "a"
+  root = this
+}
+let foo = "b"
+root = this.apply("thing")
+root.foo = $foo`,
+			lints: []mapping.Lint{
+				{Line: 2, Column: 3, What: "variable foo is declared but never used"},
+			},
+		},
+		"variables within user function args": {
+			mapping: `fn upper(v) {
+  root = v.uppercase()
+}
+let foo = "a"
+root = upper($foo)`,
+		},
+		"unreachable match cases": {
+			mapping: `root = match this.type {
+  "a" => 1
+  "b" => 2
+  "a" => 3
+  _ => 4
+  "c" => 5
+}`,
+			lints: []mapping.Lint{
+				{Line: 1, Column: 1, What: "match case 3 is unreachable as case 1 matches the same value"},
+				{Line: 1, Column: 1, What: "match case 5 is unreachable as case 4 always matches"},
+			},
+		},
+		"undeclared map": {
+			mapping: `map foo {
+  root = this
+}
+root.a = this.apply("foo")
+root.b = this.apply("bar")`,
+			lints: []mapping.Lint{
+				{Line: 5, Column: 1, What: "map bar is not declared"},
+			},
+		},
+		"similar metadata keys": {
+			mapping: `meta foo_bar = "a"
+meta tag_1 = "b"
+meta tag_2 = "c"
+root = meta("foo_baz")`,
+			lints: []mapping.Lint{
+				{Line: 4, Column: 1, What: "metadata keys foo_bar and foo_baz are similar, one of them might be misspelt"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			exec, perr := ParseMapping(GlobalContext().Deactivated(), test.mapping)
+			require.Nil(t, perr)
+			assert.Equal(t, test.lints, exec.Lint())
+		})
+	}
+}
+
+func TestMappingLintShadowedMaps(t *testing.T) {
+	dir := t.TempDir()
+
+	importFile := filepath.Join(dir, "foo_map.blobl")
+	require.NoError(t, os.WriteFile(importFile, []byte(`map foo { root = this }`), 0o777))
+
+	// Maps that shadow another map are rejected by the parser, and therefore
+	// never reach the analysis.
+	tests := map[string]struct {
+		mapping     string
+		errContains string
+	}{
+		"shadows declared map": {
+			mapping: `map foo {
+  root = this
+}
+map foo {
+  root = this.bar
+}
+root = this.apply("foo")`,
+			errContains: "line 4 char 1: map name collision: foo",
+		},
+		"shadows imported map": {
+			mapping: fmt.Sprintf(`import "%v"
+map foo {
+  root = this.bar
+}
+root = this.apply("foo")`, importFile),
+			errContains: "line 2 char 1: map name collision: foo",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			_, perr := ParseMapping(GlobalContext().Deactivated(), test.mapping)
+			require.NotNil(t, perr)
+			assert.Contains(t, perr.ErrorAtPosition([]rune(test.mapping)), test.errContains)
+		})
+	}
+}
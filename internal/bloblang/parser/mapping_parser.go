@@ -3,6 +3,7 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Jeffail/gabs/v2"
@@ -18,6 +19,7 @@ import (
 // messages.
 func ParseMapping(pCtx Context, expr string) (*mapping.Executor, *Error) {
 	in := []rune(expr)
+	pCtx = pCtx.withCoverageSource(pCtx.coverageName, in)
 
 	resDirectImport := singleRootImport(pCtx)(in)
 	if resDirectImport.Err != nil && resDirectImport.Err.IsFatal() {
@@ -43,10 +45,18 @@ func ParseMapping(pCtx Context, expr string) (*mapping.Executor, *Error) {
 //------------------------------------------------------------------------------'
 
 func parseExecutor(pCtx Context) Func {
+	return parseExecutorWithFunctions(pCtx, map[string]*userFunction{})
+}
+
+// parseExecutorWithFunctions parses a mapping where any functions declared or
+// imported by the mapping are added to a provided map.
+func parseExecutorWithFunctions(pCtx Context, fns map[string]*userFunction) Func {
 	newline := NewlineAllowComment()
 	whitespace := SpacesAndTabs()
 	allWhitespace := DiscardAll(OneOf(whitespace, newline))
 
+	pCtx = pCtx.withUserFunctions(fns)
+
 	return func(input []rune) Result {
 		maps := map[string]query.Function{}
 		statements := []mapping.Statement{}
@@ -54,6 +64,7 @@ func parseExecutor(pCtx Context) Func {
 		statement := OneOf(
 			importParser(maps, pCtx),
 			mapParser(maps, pCtx),
+			fnParser(maps, pCtx),
 			letStatementParser(pCtx),
 			metaStatementParser(false, pCtx),
 			plainMappingStatementParser(pCtx),
@@ -92,7 +103,7 @@ func parseExecutor(pCtx Context) Func {
 				statements = append(statements, mStmt)
 			}
 		}
-		return Success(mapping.NewExecutor("", input, maps, statements...), res.Remaining)
+		return Success(pCtx.newExecutor("", input, maps, statements...), res.Remaining)
 	}
 }
 
@@ -123,6 +134,7 @@ func singleRootImport(pCtx Context) Func {
 		nextCtx := pCtx.WithImporterRelativeToFile(fpath)
 
 		importContent := []rune(string(contents))
+		nextCtx = nextCtx.withCoverageSource(fpath, importContent)
 		execRes := parseExecutor(nextCtx)(importContent)
 		if execRes.Err != nil {
 			return Fail(NewFatalError(input, NewImportError(fpath, importContent, execRes.Err)), input)
@@ -161,8 +173,9 @@ func singleRootMapping(pCtx Context) Func {
 			return Fail(NewError(res.Remaining, expStr), input)
 		}
 
+		fn = pCtx.cover(mapping.CoverageStatement, allWhitespace(input).Remaining, fn)
 		stmt := mapping.NewStatement(input, mapping.NewJSONAssignment(), fn)
-		return Success(mapping.NewExecutor("", input, map[string]query.Function{}, stmt), nil)
+		return Success(pCtx.newExecutor("", input, map[string]query.Function{}, stmt), nil)
 	}
 }
 
@@ -208,14 +221,17 @@ func importParser(maps map[string]query.Function, pCtx Context) Func {
 		nextCtx := pCtx.WithImporterRelativeToFile(fpath)
 
 		importContent := []rune(string(contents))
-		execRes := parseExecutor(nextCtx)(importContent)
+		nextCtx = nextCtx.withCoverageSource(fpath, importContent)
+
+		importFns := map[string]*userFunction{}
+		execRes := parseExecutorWithFunctions(nextCtx, importFns)(importContent)
 		if execRes.Err != nil {
 			return Fail(NewFatalError(input, NewImportError(fpath, importContent, execRes.Err)), input)
 		}
 
 		exec := execRes.Payload.(*mapping.Executor)
-		if len(exec.Maps()) == 0 {
-			err := fmt.Errorf("no maps to import from '%v'", fpath)
+		if len(exec.Maps()) == 0 && len(importFns) == 0 {
+			err := fmt.Errorf("no maps or functions to import from '%v'", fpath)
 			return Fail(NewFatalError(input, err), input)
 		}
 
@@ -232,6 +248,19 @@ func importParser(maps map[string]query.Function, pCtx Context) Func {
 			return Fail(NewFatalError(input, err), input)
 		}
 
+		for k, v := range importFns {
+			if _, exists := pCtx.userFunctions[k]; exists {
+				collisions = append(collisions, k)
+			} else {
+				pCtx.userFunctions[k] = v
+			}
+		}
+		if len(collisions) > 0 {
+			sort.Strings(collisions)
+			err := fmt.Errorf("function name collisions from import '%v': %v", fpath, collisions)
+			return Fail(NewFatalError(input, err), input)
+		}
+
 		return Success(fpath, res.Remaining)
 	}
 }
@@ -297,7 +326,104 @@ func mapParser(maps map[string]query.Function, pCtx Context) Func {
 			statements[i] = v.(mapping.Statement)
 		}
 
-		maps[ident] = mapping.NewExecutor("map "+ident, input, maps, statements...)
+		maps[ident] = pCtx.newExecutor("map "+ident, input, maps, statements...)
+
+		return Success(ident, res.Remaining)
+	}
+}
+
+func fnParser(maps map[string]query.Function, pCtx Context) Func {
+	newline := NewlineAllowComment()
+	whitespace := SpacesAndTabs()
+	allWhitespace := DiscardAll(OneOf(whitespace, newline))
+
+	header := Sequence(
+		Term("fn"),
+		whitespace,
+		Expect(SnakeCase(), "function name"),
+		Char('('),
+	)
+
+	params := DelimitedPattern(
+		allWhitespace,
+		Expect(SnakeCase(), "parameter name"),
+		Sequence(Discard(whitespace), Char(','), allWhitespace),
+		Sequence(allWhitespace, Char(')')),
+		false,
+	)
+
+	return func(input []rune) Result {
+		res := header(input)
+		if res.Err != nil {
+			return res
+		}
+		ident := res.Payload.([]interface{})[2].(string)
+
+		// Once the header is parsed any errors are fatal.
+		if res = MustBe(params)(res.Remaining); res.Err != nil {
+			return Fail(res.Err, input)
+		}
+		paramSlice := res.Payload.([]interface{})
+
+		if _, exists := pCtx.userFunctions[ident]; exists {
+			return Fail(NewFatalError(input, fmt.Errorf("function name collision: %v", ident)), input)
+		}
+		if _, err := pCtx.Functions.Params(ident); err == nil {
+			return Fail(NewFatalError(input, fmt.Errorf("function name collides with a built in function: %v", ident)), input)
+		}
+
+		udf := &userFunction{name: ident}
+		bodyCtx := pCtx
+		for _, p := range paramSlice {
+			param := p.(string)
+			for _, existing := range udf.params {
+				if existing == param {
+					return Fail(NewFatalError(input, fmt.Errorf("duplicate parameter name: %v", param)), input)
+				}
+			}
+			udf.params = append(udf.params, param)
+			bodyCtx = bodyCtx.WithNamedContext(param)
+		}
+
+		// The function is registered before its body is parsed so that it can
+		// call itself recursively.
+		pCtx.userFunctions[ident] = udf
+
+		body := MustBe(Sequence(
+			Discard(SpacesAndTabs()),
+			DelimitedPattern(
+				Expect(Sequence(
+					Char('{'),
+					allWhitespace,
+				), "function body"),
+				OneOf(
+					letStatementParser(bodyCtx),
+					metaStatementParser(true, bodyCtx),
+					plainMappingStatementParser(bodyCtx),
+				),
+				Sequence(
+					Discard(whitespace),
+					newline,
+					allWhitespace,
+				),
+				Sequence(
+					allWhitespace,
+					Char('}'),
+				),
+				true,
+			),
+		))
+		if res = body(res.Remaining); res.Err != nil {
+			delete(pCtx.userFunctions, ident)
+			return Fail(res.Err, input)
+		}
+
+		stmtSlice := res.Payload.([]interface{})[1].([]interface{})
+		statements := make([]mapping.Statement, len(stmtSlice))
+		for i, v := range stmtSlice {
+			statements[i] = v.(mapping.Statement)
+		}
+		udf.exec = pCtx.newExecutor("fn "+ident, input, maps, statements...)
 
 		return Success(ident, res.Remaining)
 	}
@@ -333,7 +459,7 @@ func letStatementParser(pCtx Context) Func {
 			mapping.NewStatement(
 				input,
 				mapping.NewVarAssignment(resSlice[2].(string)),
-				resSlice[6].(query.Function),
+				pCtx.cover(mapping.CoverageStatement, input, resSlice[6].(query.Function)),
 			),
 			res.Remaining,
 		)
@@ -390,7 +516,7 @@ func metaStatementParser(disabled bool, pCtx Context) Func {
 			mapping.NewStatement(
 				input,
 				mapping.NewMetaAssignment(keyPtr),
-				resSlice[6].(query.Function),
+				pCtx.cover(mapping.CoverageStatement, input, resSlice[6].(query.Function)),
 			),
 			res.Remaining,
 		)
@@ -494,7 +620,7 @@ func plainMappingStatementParser(pCtx Context) Func {
 			mapping.NewStatement(
 				input,
 				mapping.NewJSONAssignment(path...),
-				resSlice[4].(query.Function),
+				pCtx.cover(mapping.CoverageStatement, input, resSlice[4].(query.Function)),
 			),
 			res.Remaining,
 		)
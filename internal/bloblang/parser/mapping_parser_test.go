@@ -37,12 +37,12 @@ func TestMappingErrors(t *testing.T) {
 		},
 		"no mappings": {
 			mapping:     ``,
-			errContains: `line 1 char 1: expected import, map, or assignment`,
+			errContains: `line 1 char 1: expected import, map, fn, or assignment`,
 		},
 		"no mappings 2": {
 			mapping: `
    `,
-			errContains: `line 2 char 4: expected import, map, or assignment`,
+			errContains: `line 2 char 4: expected import, map, fn, or assignment`,
 		},
 		"double mapping": {
 			mapping:     `foo = bar bar = baz`,
@@ -83,13 +83,13 @@ meta foo = "bar"`,
 		"bad char 2": {
 			mapping: `let foo = bar
 !foo = bar`,
-			errContains: `line 2 char 1: expected import, map, or assignment`,
+			errContains: `line 2 char 1: expected import, map, fn, or assignment`,
 		},
 		"bad char 3": {
 			mapping: `let foo = bar
 !foo = bar
 this = that`,
-			errContains: `line 2 char 1: expected import, map, or assignment`,
+			errContains: `line 2 char 1: expected import, map, fn, or assignment`,
 		},
 		"bad query": {
 			mapping:     `foo = blah.`,
@@ -139,7 +139,7 @@ foo = bar.apply("from_import")`, badMapFile),
 			mapping: fmt.Sprintf(`import "%v"
 
 foo = bar.apply("from_import")`, noMapsFile),
-			errContains: fmt.Sprintf(`line 1 char 1: no maps to import from '%v'`, noMapsFile),
+			errContains: fmt.Sprintf(`line 1 char 1: no maps or functions to import from '%v'`, noMapsFile),
 		},
 		"colliding maps file import": {
 			mapping: fmt.Sprintf(`map "foo" { this = that }			
@@ -149,10 +149,50 @@ import "%v"
 foo = bar.apply("foo")`, goodMapFile),
 			errContains: fmt.Sprintf(`line 3 char 1: map name collisions from import '%v': [foo]`, goodMapFile),
 		},
+		"user function wrong argument count": {
+			mapping: `fn add(a, b) {
+  root = a + b
+}
+root = add(1)`,
+			errContains: `line 4 char 8: function add expects 2 arguments, received 1`,
+		},
+		"user function unknown named argument": {
+			mapping: `fn add(a, b) {
+  root = a + b
+}
+root = add(a: 1, c: 2)`,
+			errContains: `line 4 char 8: function add has no parameter c`,
+		},
+		"user function collision": {
+			mapping: `fn add(a, b) {
+  root = a + b
+}
+fn add(a) {
+  root = a
+}`,
+			errContains: `line 4 char 1: function name collision: add`,
+		},
+		"user function collides with built in": {
+			mapping: `fn uuid_v4() {
+  root = "nope"
+}`,
+			errContains: `line 1 char 1: function name collides with a built in function: uuid_v4`,
+		},
+		"user function duplicate parameter": {
+			mapping: `fn add(a, a) {
+  root = a + a
+}`,
+			errContains: `line 1 char 1: duplicate parameter name: a`,
+		},
+		"user function missing body": {
+			mapping: `fn add(a, b)
+root = add(1, 2)`,
+			errContains: `line 1 char 13: required: expected function body`,
+		},
 		"quotes at root": {
 			mapping: `
 "root.something" = 5 + 2`,
-			errContains: "line 2 char 1: expected import, map, or assignment",
+			errContains: "line 2 char 1: expected import, map, fn, or assignment",
 		},
 	}
 
@@ -179,6 +219,11 @@ func TestMappings(t *testing.T) {
 	directMapFile := filepath.Join(dir, "direct_map.blobl")
 	require.NoError(t, os.WriteFile(directMapFile, []byte(`root.nested = this`), 0o777))
 
+	fnFile := filepath.Join(dir, "fns.blobl")
+	require.NoError(t, os.WriteFile(fnFile, []byte(`fn greet(greeting, name) {
+  root = greeting + " " + name
+}`), 0o777))
+
 	type part struct {
 		Content string
 		Meta    map[string]string
@@ -190,6 +235,53 @@ func TestMappings(t *testing.T) {
 		mapping string
 		output  part
 	}{
+		"user function": {
+			mapping: `fn clamp(v, low, high) {
+  root = if v < low { low } else if v > high { high } else { v }
+}
+root.a = clamp(this.a, 0, 10)
+root.b = clamp(this.b, 0, 10)
+root.c = clamp(high: 10, v: this.c, low: 0)`,
+			input:  []part{{Content: `{"a":-5,"b":20,"c":5}`}},
+			output: part{Content: `{"a":0,"b":10,"c":5}`},
+		},
+		"user function variables": {
+			mapping: `fn sum_list(values) {
+  let total = values.sum()
+  root.total = $total
+  root.mean = $total / values.length()
+}
+let total = "outer"
+root.stats = sum_list(this.values)
+root.total = $total`,
+			input:  []part{{Content: `{"values":[1,2,3,6]}`}},
+			output: part{Content: `{"stats":{"mean":3,"total":12},"total":"outer"}`},
+		},
+		"user function recursion": {
+			mapping: `fn factorial(n) {
+  root = if n <= 1 { 1 } else { n * factorial(n - 1) }
+}
+root = factorial(this.n)`,
+			input:  []part{{Content: `{"n":5}`}},
+			output: part{Content: `120`},
+		},
+		"user function calls other functions": {
+			mapping: `fn double(v) {
+  root = v * 2
+}
+fn double_all(values) {
+  root = values.map_each(ele -> double(ele))
+}
+root = double_all(this)`,
+			input:  []part{{Content: `[1,2,3]`}},
+			output: part{Content: `[2,4,6]`},
+		},
+		"user function import": {
+			mapping: fmt.Sprintf(`import "%v"
+root = greet("hello", this.name)`, fnFile),
+			input:  []part{{Content: `{"name":"world"}`}},
+			output: part{Content: `hello world`},
+		},
 		"compressed arithmetic": {
 			mapping: `this.foo+this.bar`,
 			input: []part{
@@ -3,8 +3,7 @@ package parser
 import (
 	"fmt"
 
-	"github.com/google/go-cmp/cmp"
-
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
 )
 
@@ -42,28 +41,20 @@ func matchCaseParser(pCtx Context) Func {
 
 		seqSlice := res.Payload.([]interface{})
 
+		queryFn := pCtx.cover(mapping.CoverageMatchCase, input, seqSlice[2].(query.Function))
+
 		var caseFn query.Function
 		switch t := seqSlice[0].([]interface{})[0].(type) {
 		case query.Function:
 			if lit, isLiteral := t.(*query.Literal); isLiteral {
-				caseFn = query.ClosureFunction("case statement", func(ctx query.FunctionContext) (interface{}, error) {
-					v := ctx.Value()
-					if v == nil {
-						return false, nil
-					}
-					return cmp.Equal(*v, lit.Value), nil
-				}, nil)
-			} else {
-				caseFn = t
+				return Success(query.NewMatchLiteralCase(lit, queryFn), res.Remaining)
 			}
+			caseFn = t
 		case string:
 			caseFn = query.NewLiteralFunction("", true)
 		}
 
-		return Success(
-			query.NewMatchCase(caseFn, seqSlice[2].(query.Function)),
-			res.Remaining,
-		)
+		return Success(query.NewMatchCase(caseFn, queryFn), res.Remaining)
 	}
 }
 
@@ -173,10 +164,11 @@ func ifExpressionParser(pCtx Context) Func {
 
 		seqSlice := res.Payload.([]interface{})
 		queryFn := seqSlice[2].(query.Function)
-		ifFn := seqSlice[6].(query.Function)
+		ifFn := pCtx.cover(mapping.CoverageIf, input, seqSlice[6].(query.Function))
 
 		var elseIfs []query.ElseIf
 		for {
+			clip := optionalWhitespace(res.Remaining).Remaining
 			res = elseIfParser(res.Remaining)
 			if res.Err != nil {
 				return res
@@ -187,18 +179,21 @@ func ifExpressionParser(pCtx Context) Func {
 			seqSlice = res.Payload.([]interface{})
 			elseIfs = append(elseIfs, query.ElseIf{
 				QueryFn: seqSlice[3].(query.Function),
-				MapFn:   seqSlice[7].(query.Function),
+				MapFn:   pCtx.cover(mapping.CoverageElseIf, clip, seqSlice[7].(query.Function)),
 			})
 		}
 
 		var elseFn query.Function
 
+		clip := optionalWhitespace(res.Remaining).Remaining
 		res = elseParser(res.Remaining)
 		if res.Err != nil {
 			return res
 		}
 		if res.Payload != nil {
-			elseFn, _ = res.Payload.([]interface{})[5].(query.Function)
+			if fn, ok := res.Payload.([]interface{})[5].(query.Function); ok {
+				elseFn = pCtx.cover(mapping.CoverageElse, clip, fn)
+			}
 		}
 
 		res.Payload = query.NewIfFunction(queryFn, ifFn, elseIfs, elseFn)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
 )
 
@@ -328,7 +329,12 @@ func methodParser(fn query.Function, pCtx Context) Func {
 			return Fail(NewFatalError(input, err), input)
 		}
 
-		method, err := pCtx.InitMethod(targetMethod, fn, parsedParams)
+		target := fn
+		if targetMethod == "catch" {
+			target = pCtx.coverOnError(mapping.CoverageCatch, input, fn)
+		}
+
+		method, err := pCtx.InitMethod(targetMethod, target, parsedParams)
 		if err != nil {
 			return Fail(NewFatalError(input, err), input)
 		}
@@ -336,6 +342,117 @@ func methodParser(fn query.Function, pCtx Context) Func {
 	}
 }
 
+// userFunction is a function declared within a mapping with the fn keyword.
+type userFunction struct {
+	name   string
+	params []string
+
+	// The body is parsed after the function is registered in order to support
+	// recursion, and therefore might not be set until after calls to the
+	// function are parsed.
+	exec *mapping.Executor
+}
+
+// call returns a query function that executes the user function with the
+// arguments of a function call, which are either all nameless or all named.
+func (u *userFunction) call(args []interface{}) (query.Function, error) {
+	argFns := make([]query.Function, len(u.params))
+
+	var namedCount int
+	for _, arg := range args {
+		if _, isNamed := arg.(namedArg); isNamed {
+			namedCount++
+		}
+	}
+	if namedCount > 0 && namedCount < len(args) {
+		return nil, errors.New("cannot mix named and nameless arguments")
+	}
+
+	toFn := func(v interface{}) query.Function {
+		if fn, ok := v.(query.Function); ok {
+			return fn
+		}
+		return query.NewLiteralFunction("", v)
+	}
+
+	if namedCount == 0 {
+		if len(args) != len(u.params) {
+			return nil, fmt.Errorf("function %v expects %v arguments, received %v", u.name, len(u.params), len(args))
+		}
+		for i, arg := range args {
+			argFns[i] = toFn(arg)
+		}
+	} else {
+	argsLoop:
+		for _, arg := range args {
+			nArg := arg.(namedArg)
+			for i, p := range u.params {
+				if p != nArg.name {
+					continue
+				}
+				if argFns[i] != nil {
+					return nil, fmt.Errorf("duplicate named arg: %v", nArg.name)
+				}
+				argFns[i] = toFn(nArg.value)
+				continue argsLoop
+			}
+			return nil, fmt.Errorf("function %v has no parameter %v", u.name, nArg.name)
+		}
+		for i, fn := range argFns {
+			if fn == nil {
+				return nil, fmt.Errorf("missing argument %v for function %v", u.params[i], u.name)
+			}
+		}
+	}
+
+	fn := query.ClosureFunction("function "+u.name, func(ctx query.FunctionContext) (interface{}, error) {
+		argValues := make([]interface{}, len(argFns))
+		for i, fn := range argFns {
+			v, err := fn.Exec(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract input arg %v: %w", u.params[i], err)
+			}
+			argValues[i] = v
+		}
+
+		// Functions are unable to access the variables of the caller.
+		ctx.Vars = map[string]interface{}{}
+		for i, v := range argValues {
+			ctx = ctx.WithNamedValue(u.params[i], v)
+		}
+		return u.exec.Exec(ctx)
+	}, func(ctx query.TargetsContext) (query.TargetsContext, []query.TargetPath) {
+		// Targets of the function body are not included as the function may
+		// be recursive.
+		var targets []query.TargetPath
+		for _, fn := range argFns {
+			_, tmp := fn.QueryTargets(ctx)
+			targets = append(targets, tmp...)
+		}
+		return ctx, targets
+	})
+	return &userFunctionCall{Function: fn, udf: u, args: argFns}, nil
+}
+
+// userFunctionCall is a call to a user function, which exposes the arguments
+// and body of the function to static analysis.
+type userFunctionCall struct {
+	query.Function
+
+	udf  *userFunction
+	args []query.Function
+}
+
+func (c *userFunctionCall) Analyse(ctx *query.AnalysisContext) query.ValueType {
+	for _, arg := range c.args {
+		_ = query.Analyse(ctx, arg)
+	}
+	if c.udf.exec != nil {
+		_ = query.Analyse(ctx, c.udf.exec)
+	}
+	return query.ValueUnknown
+}
+
 func functionParser(pCtx Context) Func {
 	p := Sequence(
 		Expect(
@@ -354,6 +471,14 @@ func functionParser(pCtx Context) Func {
 		seqSlice := res.Payload.([]interface{})
 
 		targetFunc := seqSlice[0].(string)
+		if udf, exists := pCtx.userFunctions[targetFunc]; exists {
+			fn, err := udf.call(seqSlice[1].([]interface{}))
+			if err != nil {
+				return Fail(NewFatalError(input, err), input)
+			}
+			return Success(fn, res.Remaining)
+		}
+
 		params, err := pCtx.Functions.Params(targetFunc)
 		if err != nil {
 			return Fail(NewFatalError(input, err), input)
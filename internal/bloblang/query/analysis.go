@@ -0,0 +1,196 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lint describes a problem found by a static analysis of a query, which would
+// otherwise only surface as an error (or an unexpected result) at runtime.
+type Lint struct {
+	// Input points to the parsed expression that the problem was found within,
+	// this might be nil when the location of the problem is unknown.
+	Input []rune
+
+	What string
+}
+
+// AnalysisContext is provided to query functions during a static analysis and
+// accumulates the problems that they find, along with the declarations and
+// references required in order to check them.
+type AnalysisContext struct {
+	// Input points to the parsed expression that is currently being analysed.
+	Input []rune
+
+	// Maps are the named maps available to the query being analysed.
+	Maps map[string]Function
+
+	// Vars contains the names of variables referenced within the current
+	// scope of the analysis, along with the parsed expression that first
+	// referenced them.
+	Vars map[string][]rune
+
+	// MetaKeys contains the metadata keys referenced by the query, along with
+	// the parsed expression that first referenced them.
+	MetaKeys map[string][]rune
+
+	visited map[interface{}]struct{}
+	lints   []Lint
+}
+
+// NewAnalysisContext creates a context for the static analysis of a query with
+// a set of named maps.
+func NewAnalysisContext(maps map[string]Function) *AnalysisContext {
+	return &AnalysisContext{
+		Maps:     maps,
+		Vars:     map[string][]rune{},
+		MetaKeys: map[string][]rune{},
+		visited:  map[interface{}]struct{}{},
+	}
+}
+
+// Report a problem found at the current input of the analysis.
+func (a *AnalysisContext) Report(format string, args ...interface{}) {
+	a.lints = append(a.lints, Lint{
+		Input: a.Input,
+		What:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Lints returns all problems reported during the analysis.
+func (a *AnalysisContext) Lints() []Lint {
+	return a.lints
+}
+
+// FirstVisit returns true if the provided key has not yet been visited during
+// the analysis, and marks it as visited. This is useful for preventing mappings
+// that are referenced multiple times, or recursively, from being analysed more
+// than once.
+func (a *AnalysisContext) FirstVisit(key interface{}) bool {
+	if _, exists := a.visited[key]; exists {
+		return false
+	}
+	a.visited[key] = struct{}{}
+	return true
+}
+
+func (a *AnalysisContext) useVar(name string) {
+	if _, exists := a.Vars[name]; !exists {
+		a.Vars[name] = a.Input
+	}
+}
+
+func (a *AnalysisContext) useMetaKey(key string) {
+	if _, exists := a.MetaKeys[key]; !exists {
+		a.MetaKeys[key] = a.Input
+	}
+}
+
+// Analysable is implemented by query functions that expose their structure to
+// a static analysis.
+type Analysable interface {
+	// Analyse the function and any child functions, reporting problems to the
+	// provided context, and returning the type of value that the function
+	// results in, or ValueUnknown if the type cannot be inferred.
+	Analyse(ctx *AnalysisContext) ValueType
+}
+
+// Analyse a query function and returns the type of value that it results in.
+// Functions that do not implement Analysable are skipped and result in
+// ValueUnknown.
+//
+// Function and method calls can only be analysed when the query is parsed from
+// a deactivated environment, as their constructors are otherwise free to
+// return opaque functions.
+func Analyse(ctx *AnalysisContext, fn Function) ValueType {
+	if fn == nil {
+		return ValueUnknown
+	}
+	if a, ok := fn.(Analysable); ok {
+		return a.Analyse(ctx)
+	}
+	return ValueUnknown
+}
+
+func analyseArgs(ctx *AnalysisContext, args *ParsedParams) {
+	if args == nil {
+		return
+	}
+	for _, v := range args.values {
+		if fn, ok := v.(Function); ok {
+			_ = Analyse(ctx, fn)
+		}
+	}
+}
+
+// literalArg returns the value of an argument when it is known statically.
+func literalArg(args *ParsedParams, name string) (interface{}, bool) {
+	if args == nil {
+		return nil, false
+	}
+	v, err := args.Field(name)
+	if err != nil {
+		return nil, false
+	}
+	if _, isDyn := v.(Function); isDyn {
+		return nil, false
+	}
+	return v, true
+}
+
+func isKnownType(t ValueType) bool {
+	return t != ValueUnknown && t != ""
+}
+
+func typesString(types []ValueType) string {
+	strs := make([]string, len(types))
+	for i, t := range types {
+		strs[i] = string(t)
+	}
+	if len(strs) == 1 {
+		return strs[0]
+	}
+	return strings.Join(strs[:len(strs)-1], ", ") + " or " + strs[len(strs)-1]
+}
+
+//------------------------------------------------------------------------------
+
+// SimilarKeys returns true if two keys are distinct but similar enough that one
+// of them is likely to be a misspelling of the other, which means they differ
+// by a single insertion, deletion, substitution or transposition.
+func SimilarKeys(a, b string) bool {
+	if a == b || len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	// Keys that only differ by a numerical suffix are likely to be distinct
+	// on purpose, such as tag_1 and tag_2.
+	if strings.TrimRight(a, "0123456789") == strings.TrimRight(b, "0123456789") {
+		return false
+	}
+
+	ar, br := []rune(a), []rune(b)
+	if len(ar) > len(br) {
+		ar, br = br, ar
+	}
+	if len(br)-len(ar) > 1 {
+		return false
+	}
+
+	// Trim the common prefix and suffix, which leaves the edit itself.
+	for len(ar) > 0 && ar[0] == br[0] {
+		ar, br = ar[1:], br[1:]
+	}
+	for len(ar) > 0 && ar[len(ar)-1] == br[len(br)-1] {
+		ar, br = ar[:len(ar)-1], br[:len(br)-1]
+	}
+
+	switch {
+	case len(ar) == 0 && len(br) == 1:
+		return true
+	case len(ar) == 1 && len(br) == 1:
+		return true
+	case len(ar) == 2 && len(br) == 2:
+		return ar[0] == br[1] && ar[1] == br[0]
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimilarKeys(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		similar bool
+	}{
+		{a: "foo_bar", b: "foo_bar", similar: false},
+		{a: "foo_bar", b: "foo_baz", similar: true},
+		{a: "foo_bar", b: "foobar", similar: true},
+		{a: "foo_bar", b: "foo_bbar", similar: true},
+		{a: "foo_bar", b: "foo_abr", similar: true},
+		{a: "foo_bar", b: "foo_rab", similar: false},
+		{a: "foo_bar", b: "bar_foo", similar: false},
+		{a: "tag_1", b: "tag_2", similar: false},
+		{a: "tag_1", b: "tag_10", similar: false},
+		{a: "abc", b: "abd", similar: false},
+		{a: "kafka_key", b: "kafak_key", similar: true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.similar, SimilarKeys(test.a, test.b), "%v and %v", test.a, test.b)
+		assert.Equal(t, test.similar, SimilarKeys(test.b, test.a), "%v and %v", test.b, test.a)
+	}
+}
+
+func TestAnalyseLiterals(t *testing.T) {
+	ctx := NewAnalysisContext(nil)
+
+	assert.Equal(t, ValueString, Analyse(ctx, NewLiteralFunction("", "foo")))
+	assert.Equal(t, ValueNumber, Analyse(ctx, NewLiteralFunction("", int64(5))))
+	assert.Equal(t, ValueUnknown, Analyse(ctx, NewFieldFunction("foo")))
+	assert.Equal(t, ValueUnknown, Analyse(ctx, nil))
+	assert.Empty(t, ctx.Lints())
+}
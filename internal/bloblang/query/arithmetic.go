@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -58,7 +59,7 @@ func (o ArithmeticOperator) String() string {
 
 type arithmeticOpFunc func(lhs Function, rhs Function, l, r interface{}) (interface{}, error)
 
-func arithmeticFunc(lhs, rhs Function, op arithmeticOpFunc) (Function, error) {
+func arithmeticFunc(lhs, rhs Function, operator ArithmeticOperator, op arithmeticOpFunc) (Function, error) {
 	annotation := rhs.Annotation()
 
 	var litL, litR *Literal
@@ -73,7 +74,7 @@ func arithmeticFunc(lhs, rhs Function, op arithmeticOpFunc) (Function, error) {
 		}
 	}
 
-	return ClosureFunction(annotation, func(ctx FunctionContext) (interface{}, error) {
+	fn := ClosureFunction(annotation, func(ctx FunctionContext) (interface{}, error) {
 		var err error
 		var leftV, rightV interface{}
 		if leftV, err = lhs.Exec(ctx); err == nil {
@@ -83,7 +84,71 @@ func arithmeticFunc(lhs, rhs Function, op arithmeticOpFunc) (Function, error) {
 			return nil, err
 		}
 		return op(lhs, rhs, leftV, rightV)
-	}, aggregateTargetPaths(lhs, rhs)), nil
+	}, aggregateTargetPaths(lhs, rhs))
+	return newArithmeticFunction(fn, operator, lhs, rhs), nil
+}
+
+// arithmeticFunction wraps the function of an arithmetic operation with the
+// operator and operands for static analysis.
+type arithmeticFunction struct {
+	Function
+
+	op       ArithmeticOperator
+	lhs, rhs Function
+}
+
+func newArithmeticFunction(fn Function, op ArithmeticOperator, lhs, rhs Function) Function {
+	return &arithmeticFunction{Function: fn, op: op, lhs: lhs, rhs: rhs}
+}
+
+func (a *arithmeticFunction) Analyse(ctx *AnalysisContext) ValueType {
+	lType, rType := Analyse(ctx, a.lhs), Analyse(ctx, a.rhs)
+
+	expect := func(expected ValueType, types ...ValueType) {
+		for _, t := range types {
+			if isKnownType(t) && t != expected {
+				ctx.Report("expected %v value for %v, got %v", expected, a.op, t)
+				return
+			}
+		}
+	}
+	isStringy := func(t ValueType) bool {
+		return t == ValueString || t == ValueBytes
+	}
+
+	switch a.op {
+	case ArithmeticAdd:
+		switch {
+		case lType == ValueNumber && (rType == ValueNumber || !isKnownType(rType)):
+			return ValueNumber
+		case isStringy(lType) && (isStringy(rType) || !isKnownType(rType)):
+			return ValueString
+		case !isKnownType(lType) && rType == ValueNumber:
+			return ValueNumber
+		case !isKnownType(lType) && isStringy(rType):
+			return ValueString
+		case isKnownType(lType) && isKnownType(rType):
+			ctx.Report("cannot add %v and %v values", lType, rType)
+		case isKnownType(lType):
+			ctx.Report("cannot add %v values", lType)
+		case isKnownType(rType):
+			ctx.Report("cannot add %v values", rType)
+		}
+		return ValueUnknown
+	case ArithmeticSub, ArithmeticMul, ArithmeticDiv, ArithmeticMod:
+		expect(ValueNumber, lType, rType)
+		return ValueNumber
+	case ArithmeticEq, ArithmeticNeq, ArithmeticGt, ArithmeticLt, ArithmeticGte, ArithmeticLte:
+		return ValueBool
+	case ArithmeticAnd, ArithmeticOr:
+		expect(ValueBool, lType, rType)
+		return ValueBool
+	case ArithmeticPipe:
+		if lType == rType {
+			return lType
+		}
+	}
+	return ValueUnknown
 }
 
 //------------------------------------------------------------------------------
@@ -295,6 +360,36 @@ func compareBoolFn(op ArithmeticOperator) func(lhs, rhs bool) bool {
 	return nil
 }
 
+func compareTimeFn(op ArithmeticOperator) func(lhs, rhs time.Time) bool {
+	switch op {
+	case ArithmeticEq:
+		return func(lhs, rhs time.Time) bool {
+			return lhs.Equal(rhs)
+		}
+	case ArithmeticNeq:
+		return func(lhs, rhs time.Time) bool {
+			return !lhs.Equal(rhs)
+		}
+	case ArithmeticGt:
+		return func(lhs, rhs time.Time) bool {
+			return lhs.After(rhs)
+		}
+	case ArithmeticGte:
+		return func(lhs, rhs time.Time) bool {
+			return !lhs.Before(rhs)
+		}
+	case ArithmeticLt:
+		return func(lhs, rhs time.Time) bool {
+			return lhs.Before(rhs)
+		}
+	case ArithmeticLte:
+		return func(lhs, rhs time.Time) bool {
+			return !lhs.After(rhs)
+		}
+	}
+	return nil
+}
+
 func compareGenericFn(op ArithmeticOperator) func(lhs, rhs interface{}) bool {
 	switch op {
 	case ArithmeticEq:
@@ -337,8 +432,24 @@ func compareOp(op ArithmeticOperator) (arithmeticOpFunc, bool) {
 		strOpFn := compareStrFn(op)
 		numOpFn := compareNumFn(op)
 		boolOpFn := compareBoolFn(op)
+		timeOpFn := compareTimeFn(op)
 		genericOpFn := compareGenericFn(op)
 		return func(lFn, rFn Function, left, right interface{}) (interface{}, error) {
+			// Timestamps are compared with any value that can be coerced into a
+			// timestamp, which includes numbers and strings.
+			_, lIsTime := left.(time.Time)
+			_, rIsTime := right.(time.Time)
+			if lIsTime || rIsTime {
+				lhs, lErr := IGetTimestamp(left)
+				rhs, rErr := IGetTimestamp(right)
+				if lErr == nil && rErr == nil {
+					return timeOpFn(lhs, rhs), nil
+				}
+				if genericOpFn == nil {
+					return nil, NewTypeMismatch(op.String(), lFn, rFn, left, right)
+				}
+				return genericOpFn(left, right), nil
+			}
 			switch lhs := restrictForComparison(left).(type) {
 			case string:
 				if strOpFn == nil {
@@ -388,7 +499,7 @@ func compareOp(op ArithmeticOperator) (arithmeticOpFunc, bool) {
 }
 
 func boolOr(lhs, rhs Function) Function {
-	return ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (interface{}, error) {
+	return newArithmeticFunction(ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (interface{}, error) {
 		lhsV, err := lhs.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -408,11 +519,11 @@ func boolOr(lhs, rhs Function) Function {
 			return nil, err
 		}
 		return b, nil
-	}, aggregateTargetPaths(lhs, rhs))
+	}, aggregateTargetPaths(lhs, rhs)), ArithmeticOr, lhs, rhs)
 }
 
 func boolAnd(lhs, rhs Function) Function {
-	return ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (interface{}, error) {
+	return newArithmeticFunction(ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (interface{}, error) {
 		lhsV, err := lhs.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -432,17 +543,17 @@ func boolAnd(lhs, rhs Function) Function {
 			return nil, err
 		}
 		return b, nil
-	}, aggregateTargetPaths(lhs, rhs))
+	}, aggregateTargetPaths(lhs, rhs)), ArithmeticAnd, lhs, rhs)
 }
 
 func coalesce(lhs, rhs Function) Function {
-	return ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (interface{}, error) {
+	return newArithmeticFunction(ClosureFunction(rhs.Annotation(), func(ctx FunctionContext) (interface{}, error) {
 		lhsV, err := lhs.Exec(ctx)
 		if err == nil && !IIsNull(lhsV) {
 			return lhsV, nil
 		}
 		return rhs.Exec(ctx)
-	}, aggregateTargetPaths(lhs, rhs))
+	}, aggregateTargetPaths(lhs, rhs)), ArithmeticPipe, lhs, rhs)
 }
 
 // NewArithmeticExpression creates a single query function from a list of child
@@ -463,7 +574,7 @@ func NewArithmeticExpression(fns []Function, ops []ArithmeticOperator) (Function
 	for i, op := range ops {
 		leftFn, rightFn := fnsNew[len(fnsNew)-1], fns[i+1]
 		if opFunc, isProd := prodOp(op); isProd {
-			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, opFunc); err != nil {
+			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, op, opFunc); err != nil {
 				return nil, err
 			}
 		} else if op == ArithmeticPipe {
@@ -483,7 +594,7 @@ func NewArithmeticExpression(fns []Function, ops []ArithmeticOperator) (Function
 	for i, op := range ops {
 		leftFn, rightFn := fnsNew[len(fnsNew)-1], fns[i+1]
 		if opFunc, isSum := sumOp(op); isSum {
-			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, opFunc); err != nil {
+			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, op, opFunc); err != nil {
 				return nil, err
 			}
 		} else {
@@ -501,7 +612,7 @@ func NewArithmeticExpression(fns []Function, ops []ArithmeticOperator) (Function
 	for i, op := range ops {
 		leftFn, rightFn := fnsNew[len(fnsNew)-1], fns[i+1]
 		if opFunc, isCompare := compareOp(op); isCompare {
-			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, opFunc); err != nil {
+			if fnsNew[len(fnsNew)-1], err = arithmeticFunc(leftFn, rightFn, op, opFunc); err != nil {
 				return nil, err
 			}
 		} else {
@@ -73,6 +73,10 @@ type FunctionSpec struct {
 	// Impure indicates that a function accesses or interacts with the outter
 	// environment, and is therefore unsafe to execute in shared environments.
 	Impure bool `json:"impure"`
+
+	// ReturnType is the type of value returned by the function, which is used
+	// during static analysis of mappings. Empty when the type is not fixed.
+	ReturnType ValueType `json:"return_type,omitempty"`
 }
 
 // NewFunctionSpec creates a new function spec.
@@ -106,6 +110,12 @@ func (s FunctionSpec) Param(def ParamDefinition) FunctionSpec {
 	return s
 }
 
+// Returns sets the type of value that the function always returns.
+func (s FunctionSpec) Returns(t ValueType) FunctionSpec {
+	s.ReturnType = t
+	return s
+}
+
 // NewDeprecatedFunctionSpec creates a new function spec that is deprecated.
 func NewDeprecatedFunctionSpec(name, description string, examples ...ExampleSpec) FunctionSpec {
 	return FunctionSpec{
@@ -178,6 +188,15 @@ type MethodSpec struct {
 	// Impure indicates that a method accesses or interacts with the outter
 	// environment, and is therefore unsafe to execute in shared environments.
 	Impure bool `json:"impure"`
+
+	// InputTypes are the types of value that the method can be called upon,
+	// which is used during static analysis of mappings. Empty when the method
+	// supports any type.
+	InputTypes []ValueType `json:"input_types,omitempty"`
+
+	// ReturnType is the type of value returned by the method, which is used
+	// during static analysis of mappings. Empty when the type is not fixed.
+	ReturnType ValueType `json:"return_type,omitempty"`
 }
 
 // NewMethodSpec creates a new method spec.
@@ -231,6 +250,18 @@ func (m MethodSpec) Param(def ParamDefinition) MethodSpec {
 	return m
 }
 
+// OnTypes restricts the types of value that the method can be called upon.
+func (m MethodSpec) OnTypes(types ...ValueType) MethodSpec {
+	m.InputTypes = types
+	return m
+}
+
+// Returns sets the type of value that the method always returns.
+func (m MethodSpec) Returns(t ValueType) MethodSpec {
+	m.ReturnType = t
+	return m
+}
+
 // VariadicParams configures the method spec to allow variadic parameters.
 func (m MethodSpec) VariadicParams() MethodSpec {
 	m.Params = VariadicParams()
@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 // MatchCase represents a single match case of a match expression, where a case
@@ -9,13 +11,35 @@ import (
 type MatchCase struct {
 	caseFn  Function
 	queryFn Function
+
+	// Set when the case matches a literal value.
+	literal *Literal
 }
 
 // NewMatchCase creates a single match case of a match expression, where a case
 // query is checked and, if true, the underlying query is executed and returned.
 func NewMatchCase(caseFn, queryFn Function) MatchCase {
 	return MatchCase{
-		caseFn, queryFn,
+		caseFn:  caseFn,
+		queryFn: queryFn,
+	}
+}
+
+// NewMatchLiteralCase creates a single match case of a match expression, where
+// the context of the expression is compared with a literal value and, if equal,
+// the underlying query is executed and returned.
+func NewMatchLiteralCase(lit *Literal, queryFn Function) MatchCase {
+	caseFn := ClosureFunction("case statement", func(ctx FunctionContext) (interface{}, error) {
+		v := ctx.Value()
+		if v == nil {
+			return false, nil
+		}
+		return cmp.Equal(*v, lit.Value), nil
+	}, nil)
+	return MatchCase{
+		caseFn:  caseFn,
+		queryFn: queryFn,
+		literal: lit,
 	}
 }
 
@@ -31,7 +55,7 @@ func NewMatchFunction(contextFn Function, cases ...MatchCase) Function {
 			return value, nil
 		}, nil)
 	}
-	return ClosureFunction("match expression", func(ctx FunctionContext) (interface{}, error) {
+	fn := ClosureFunction("match expression", func(ctx FunctionContext) (interface{}, error) {
 		ctxVal, err := contextFn.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -64,6 +88,50 @@ func NewMatchFunction(contextFn Function, cases ...MatchCase) Function {
 		targets = append(targets, contextTargets...)
 		return ctx, targets
 	})
+	return &matchFunction{Function: fn, contextFn: contextFn, cases: cases}
+}
+
+type matchFunction struct {
+	Function
+
+	contextFn Function
+	cases     []MatchCase
+}
+
+func (m *matchFunction) Analyse(ctx *AnalysisContext) ValueType {
+	_ = Analyse(ctx, m.contextFn)
+
+	resultType := ValueUnknown
+	catchAll := -1
+	for i, c := range m.cases {
+		if catchAll >= 0 {
+			ctx.Report("match case %v is unreachable as case %v always matches", i+1, catchAll+1)
+		} else if lit, isLit := c.caseFn.(*Literal); isLit {
+			if b, _ := lit.Value.(bool); b {
+				catchAll = i
+			}
+		} else if c.literal != nil {
+			for j, prev := range m.cases[:i] {
+				if prev.literal != nil && cmp.Equal(prev.literal.Value, c.literal.Value) {
+					ctx.Report("match case %v is unreachable as case %v matches the same value", i+1, j+1)
+					break
+				}
+			}
+		}
+
+		_ = Analyse(ctx, c.caseFn)
+		queryType := Analyse(ctx, c.queryFn)
+		if i == 0 {
+			resultType = queryType
+		} else if resultType != queryType {
+			resultType = ValueUnknown
+		}
+	}
+	if catchAll < 0 {
+		// Without a catch all case the expression might result in nothing.
+		return ValueUnknown
+	}
+	return resultType
 }
 
 // ElseIf represents an else-if block in an if expression.
@@ -83,7 +151,7 @@ func NewIfFunction(queryFn, ifFn Function, elseIfs []ElseIf, elseFn Function) Fu
 		allFns = append(allFns, eIf.QueryFn, eIf.MapFn)
 	}
 
-	return ClosureFunction("if expression", func(ctx FunctionContext) (interface{}, error) {
+	fn := ClosureFunction("if expression", func(ctx FunctionContext) (interface{}, error) {
 		queryVal, err := queryFn.Exec(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check if condition: %w", err)
@@ -107,6 +175,41 @@ func NewIfFunction(queryFn, ifFn Function, elseIfs []ElseIf, elseFn Function) Fu
 		}
 		return Nothing(nil), nil
 	}, aggregateTargetPaths(allFns...))
+	return &ifFunction{Function: fn, queryFn: queryFn, ifFn: ifFn, elseIfs: elseIfs, elseFn: elseFn}
+}
+
+type ifFunction struct {
+	Function
+
+	queryFn Function
+	ifFn    Function
+	elseIfs []ElseIf
+	elseFn  Function
+}
+
+func (f *ifFunction) Analyse(ctx *AnalysisContext) ValueType {
+	checkCondition := func(fn Function) {
+		if t := Analyse(ctx, fn); isKnownType(t) && t != ValueBool {
+			ctx.Report("expected bool value for if condition, got %v", t)
+		}
+	}
+
+	checkCondition(f.queryFn)
+	resultType := Analyse(ctx, f.ifFn)
+	for _, eIf := range f.elseIfs {
+		checkCondition(eIf.QueryFn)
+		if Analyse(ctx, eIf.MapFn) != resultType {
+			resultType = ValueUnknown
+		}
+	}
+	if f.elseFn == nil {
+		// Without an else block the expression might result in nothing.
+		return ValueUnknown
+	}
+	if Analyse(ctx, f.elseFn) != resultType {
+		resultType = ValueUnknown
+	}
+	return resultType
 }
 
 // NewNamedContextFunction wraps a function and ensures that when the function
@@ -146,6 +249,11 @@ func (n *NamedContextFunction) Exec(ctx FunctionContext) (interface{}, error) {
 	return n.fn.Exec(nextCtx)
 }
 
+// Analyse the underlying query function.
+func (n *NamedContextFunction) Analyse(ctx *AnalysisContext) ValueType {
+	return Analyse(ctx, n.fn)
+}
+
 // QueryTargets provides a summary of which fields the underlying query function
 // targets.
 func (n *NamedContextFunction) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
@@ -70,7 +70,7 @@ func (f *FunctionSet) Init(name string, args *ParsedParams) (Function, error) {
 		return nil, badFunctionErr(name)
 	}
 	if f.disableCtors {
-		return disabledFunction(name), nil
+		return disabledFunction(f.specs[name], args), nil
 	}
 	return wrapCtorWithDynamicArgs(name, args, ctor)
 }
@@ -181,10 +181,45 @@ func FunctionDocs() []FunctionSpec {
 
 //------------------------------------------------------------------------------
 
-func disabledFunction(name string) Function {
-	return ClosureFunction("function "+name, func(ctx FunctionContext) (interface{}, error) {
-		return nil, errors.New("this function has been disabled")
-	}, func(ctx TargetsContext) (TargetsContext, []TargetPath) { return ctx, nil })
+// disabledFunctionCall is a function that cannot be executed, but retains the
+// spec and arguments of the call for static analysis.
+type disabledFunctionCall struct {
+	Function
+
+	spec FunctionSpec
+	args *ParsedParams
+}
+
+func disabledFunction(spec FunctionSpec, args *ParsedParams) Function {
+	return &disabledFunctionCall{
+		Function: ClosureFunction("function "+spec.Name, func(ctx FunctionContext) (interface{}, error) {
+			return nil, errors.New("this function has been disabled")
+		}, func(ctx TargetsContext) (TargetsContext, []TargetPath) { return ctx, nil }),
+		spec: spec,
+		args: args,
+	}
+}
+
+func (d *disabledFunctionCall) Analyse(ctx *AnalysisContext) ValueType {
+	analyseArgs(ctx, d.args)
+
+	switch d.spec.Name {
+	case "var":
+		if name, ok := literalArg(d.args, "name"); ok {
+			ctx.useVar(IToString(name))
+		}
+	case "meta", "root_meta":
+		if key, ok := literalArg(d.args, "key"); ok {
+			if keyStr := IToString(key); keyStr != "" {
+				ctx.useMetaKey(keyStr)
+			}
+		}
+	}
+
+	if d.spec.ReturnType == "" {
+		return ValueUnknown
+	}
+	return d.spec.ReturnType
 }
 
 func wrapCtorWithDynamicArgs(name string, args *ParsedParams, fn FunctionCtor) (Function, error) {
@@ -170,6 +170,11 @@ func (l *Literal) Close(ctx context.Context) error {
 	return nil
 }
 
+// Analyse returns the type of the literal value.
+func (l *Literal) Analyse(ctx *AnalysisContext) ValueType {
+	return ITypeOf(l.Value)
+}
+
 // String returns a string representation of the literal function.
 func (l *Literal) String() string {
 	return fmt.Sprintf("%v", l.Value)
@@ -190,7 +195,7 @@ var _ = registerSimpleFunction(
 		NewExampleSpec("",
 			`root = if batch_index() > 0 { deleted() }`,
 		),
-	),
+	).Returns(ValueNumber),
 	func(ctx FunctionContext) (interface{}, error) {
 		return int64(ctx.Index), nil
 	},
@@ -205,7 +210,7 @@ var _ = registerSimpleFunction(
 		NewExampleSpec("",
 			`root.foo = batch_size()`,
 		),
-	),
+	).Returns(ValueNumber),
 	func(ctx FunctionContext) (interface{}, error) {
 		return int64(ctx.MsgBatch.Len()), nil
 	},
@@ -222,7 +227,7 @@ var _ = registerSimpleFunction(
 			`{"foo":"bar"}`,
 			`{"doc":"{\"foo\":\"bar\"}"}`,
 		),
-	),
+	).Returns(ValueBytes),
 	func(ctx FunctionContext) (interface{}, error) {
 		return ctx.MsgBatch.Get(ctx.Index).Get(), nil
 	},
@@ -242,7 +247,7 @@ root.id = count("bloblang_function_example")`,
 			`{"message":"bar"}`,
 			`{"id":2,"message":"bar"}`,
 		),
-	).Param(ParamString("name", "An identifier for the counter.")).MarkImpure(),
+	).Returns(ValueNumber).Param(ParamString("name", "An identifier for the counter.")).MarkImpure(),
 	countFunction,
 )
 
@@ -446,7 +451,7 @@ var _ = registerSimpleFunction(
 		NewExampleSpec("",
 			`root.thing.host = hostname()`,
 		),
-	).MarkImpure(),
+	).Returns(ValueString).MarkImpure(),
 	func(_ FunctionContext) (interface{}, error) {
 		hn, err := os.Hostname()
 		if err != nil {
@@ -513,10 +518,30 @@ func jsonFunction(args *ParsedParams) (Function, error) {
 
 //------------------------------------------------------------------------------
 
+// metaValueSanitize converts a typed metadata value into a value that can be
+// used within a mapping, where empty strings are treated as a missing key.
+func metaValueSanitize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if t == "" {
+			return nil
+		}
+		return t
+	}
+	if s := ISanitize(v); s != nil {
+		return s
+	}
+	// Types that are not natively supported by Bloblang are presented in the
+	// same string form that would otherwise be given to outputs.
+	return IToString(v)
+}
+
 var _ = registerFunction(
 	NewFunctionSpec(
 		FunctionCategoryMessage, "meta",
-		"Returns the value of a metadata key from the input message, or `null` if the key does not exist. Since values are extracted from the read-only input message they do NOT reflect changes made from within the map. In order to query metadata mutations made within a mapping use the [`root_meta` function](#root_meta). This function supports extracting metadata from other messages of a batch with the `from` method.",
+		"Returns the value of a metadata key from the input message, or `null` if the key does not exist. Values retain the type they were given when set, which means a metadata value set by an input as a number, boolean or object will be returned as such. Since values are extracted from the read-only input message they do NOT reflect changes made from within the map. In order to query metadata mutations made within a mapping use the [`root_meta` function](#root_meta). This function supports extracting metadata from other messages of a batch with the `from` method.",
 		NewExampleSpec("",
 			`root.topic = meta("kafka_topic")`,
 			`root.topic = meta("nope") | meta("also nope") | "default"`,
@@ -533,11 +558,8 @@ var _ = registerFunction(
 		}
 		if len(key) > 0 {
 			return ClosureFunction("meta field "+key, func(ctx FunctionContext) (interface{}, error) {
-				v := ctx.MsgBatch.Get(ctx.Index).MetaGet(key)
-				if v == "" {
-					return nil, nil
-				}
-				return v, nil
+				v, _ := ctx.MsgBatch.Get(ctx.Index).MetaGetMut(key)
+				return metaValueSanitize(v), nil
 			}, func(ctx TargetsContext) (TargetsContext, []TargetPath) {
 				paths := []TargetPath{
 					NewTargetPath(TargetMetadata, key),
@@ -548,8 +570,8 @@ var _ = registerFunction(
 		}
 		return ClosureFunction("meta object", func(ctx FunctionContext) (interface{}, error) {
 			kvs := map[string]interface{}{}
-			_ = ctx.MsgBatch.Get(ctx.Index).MetaIter(func(k, v string) error {
-				if len(v) > 0 {
+			_ = ctx.MsgBatch.Get(ctx.Index).MetaIterMut(func(k string, v interface{}) error {
+				if v = metaValueSanitize(v); v != nil {
 					kvs[k] = v
 				}
 				return nil
@@ -590,11 +612,8 @@ var _ = registerFunction(
 				if ctx.NewMeta == nil {
 					return nil, errors.New("root metadata cannot be queried in this context")
 				}
-				v := ctx.NewMeta.MetaGet(key)
-				if v == "" {
-					return nil, nil
-				}
-				return v, nil
+				v, _ := ctx.NewMeta.MetaGetMut(key)
+				return metaValueSanitize(v), nil
 			}, func(ctx TargetsContext) (TargetsContext, []TargetPath) {
 				paths := []TargetPath{
 					NewTargetPath(TargetMetadata, key),
@@ -608,8 +627,8 @@ var _ = registerFunction(
 				return nil, errors.New("root metadata cannot be queried in this context")
 			}
 			kvs := map[string]interface{}{}
-			_ = ctx.NewMeta.MetaIter(func(k, v string) error {
-				if len(v) > 0 {
+			_ = ctx.NewMeta.MetaIterMut(func(k string, v interface{}) error {
+				if v = metaValueSanitize(v); v != nil {
 					kvs[k] = v
 				}
 				return nil
@@ -647,7 +666,7 @@ root.second = random_int(1)`,
 		NewExampleSpec("It is possible to specify a dynamic seed argument, in which case the argument will only be resolved once during the lifetime of the mapping.",
 			`root.first = random_int(timestamp_unix_nano())`,
 		),
-	).
+	).Returns(ValueNumber).
 		Param(ParamQuery(
 			"seed",
 			"A seed to use, if a query is provided it will only be resolved once during the lifetime of the mapping.",
@@ -700,7 +719,7 @@ var _ = registerFunction(
 		NewExampleSpec("",
 			`root.received_at = now().format_timestamp("Mon Jan 2 15:04:05 -0700 MST 2006", "UTC")`,
 		),
-	),
+	).Returns(ValueString),
 	func(args *ParsedParams) (Function, error) {
 		return ClosureFunction("function now", func(_ FunctionContext) (interface{}, error) {
 			return time.Now().Format(time.RFC3339Nano), nil
@@ -715,7 +734,7 @@ var _ = registerSimpleFunction(
 		NewExampleSpec("",
 			`root.received_at = timestamp_unix()`,
 		),
-	),
+	).Returns(ValueNumber),
 	func(_ FunctionContext) (interface{}, error) {
 		return time.Now().Unix(), nil
 	},
@@ -728,7 +747,7 @@ var _ = registerSimpleFunction(
 		NewExampleSpec("",
 			`root.received_at = timestamp_unix_nano()`,
 		),
-	),
+	).Returns(ValueNumber),
 	func(_ FunctionContext) (interface{}, error) {
 		return time.Now().UnixNano(), nil
 	},
@@ -771,7 +790,7 @@ var _ = registerSimpleFunction(
 		FunctionCategoryGeneral, "uuid_v4",
 		"Generates a new RFC-4122 UUID each time it is invoked and prints a string representation.",
 		NewExampleSpec("", `root.id = uuid_v4()`),
-	),
+	).Returns(ValueString),
 	func(_ FunctionContext) (interface{}, error) {
 		u4, err := uuid.NewV4()
 		if err != nil {
@@ -790,7 +809,7 @@ var _ = registerFunction(
 		NewExampleSpec("", `root.id = nanoid()`),
 		NewExampleSpec("It is possible to specify an optional length parameter.", `root.id = nanoid(54)`),
 		NewExampleSpec("It is also possible to specify an optional custom alphabet after the length parameter.", `root.id = nanoid(54, "abcde")`),
-	).
+	).Returns(ValueString).
 		Param(ParamInt64("length", "An optional length.").Optional()).
 		Param(ParamString("alphabet", "An optional custom alphabet to use for generating IDs. When specified the field `length` must also be present.").Optional()),
 	nanoidFunction,
@@ -826,7 +845,7 @@ var _ = registerSimpleFunction(
 		FunctionCategoryGeneral, "ksuid",
 		"Generates a new ksuid each time it is invoked and prints a string representation.",
 		NewExampleSpec("", `root.id = ksuid()`),
-	),
+	).Returns(ValueString),
 	func(_ FunctionContext) (interface{}, error) {
 		return ksuid.New().String(), nil
 	},
@@ -847,19 +866,36 @@ var _ = registerFunction(
 
 // NewVarFunction creates a new variable function.
 func NewVarFunction(name string) Function {
-	return ClosureFunction("variable "+name, func(ctx FunctionContext) (interface{}, error) {
-		if ctx.Vars == nil {
-			return nil, errors.New("variables were undefined")
-		}
-		if res, ok := ctx.Vars[name]; ok {
-			return res, nil
-		}
-		return nil, fmt.Errorf("variable '%v' undefined", name)
-	}, func(ctx TargetsContext) (TargetsContext, []TargetPath) {
-		paths := []TargetPath{
-			NewTargetPath(TargetVariable, name),
-		}
-		ctx = ctx.WithValues(paths)
-		return ctx, paths
-	})
+	return &varFunction{name: name}
+}
+
+type varFunction struct {
+	name string
+}
+
+func (v *varFunction) Annotation() string {
+	return "variable " + v.name
+}
+
+func (v *varFunction) Exec(ctx FunctionContext) (interface{}, error) {
+	if ctx.Vars == nil {
+		return nil, errors.New("variables were undefined")
+	}
+	if res, ok := ctx.Vars[v.name]; ok {
+		return res, nil
+	}
+	return nil, fmt.Errorf("variable '%v' undefined", v.name)
+}
+
+func (v *varFunction) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPath) {
+	paths := []TargetPath{
+		NewTargetPath(TargetVariable, v.name),
+	}
+	ctx = ctx.WithValues(paths)
+	return ctx, paths
+}
+
+func (v *varFunction) Analyse(ctx *AnalysisContext) ValueType {
+	ctx.useVar(v.name)
+	return ValueUnknown
 }
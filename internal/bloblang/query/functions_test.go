@@ -241,6 +241,50 @@ func TestFunctionTargets(t *testing.T) {
 	}
 }
 
+func TestMetaFunctionTyped(t *testing.T) {
+	part := message.NewPart([]byte(`{}`))
+	part.MetaSetMut("num", int64(5))
+	part.MetaSetMut("bool", true)
+	part.MetaSetMut("obj", map[string]interface{}{"foo": "bar"})
+	part.MetaSetMut("empty", "")
+	msg := message.QuickBatch(nil)
+	msg.Append(part)
+
+	for _, fnName := range []string{"meta", "root_meta"} {
+		for key, exp := range map[string]interface{}{
+			"num":   int64(5),
+			"bool":  true,
+			"obj":   map[string]interface{}{"foo": "bar"},
+			"empty": nil,
+			"nope":  nil,
+		} {
+			fn, err := InitFunctionHelper(fnName, key)
+			require.NoError(t, err)
+
+			res, err := fn.Exec(FunctionContext{
+				MsgBatch: msg,
+				NewMeta:  part,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, exp, res, "%v(%v)", fnName, key)
+		}
+
+		fn, err := InitFunctionHelper(fnName)
+		require.NoError(t, err)
+
+		res, err := fn.Exec(FunctionContext{
+			MsgBatch: msg,
+			NewMeta:  part,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"num":  int64(5),
+			"bool": true,
+			"obj":  map[string]interface{}{"foo": "bar"},
+		}, res, fnName)
+	}
+}
+
 func TestNanoidFunction(t *testing.T) {
 	e, err := InitFunctionHelper("nanoid")
 	require.Nil(t, err)
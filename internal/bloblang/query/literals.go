@@ -124,6 +124,20 @@ func (m *mapLiteral) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetP
 	return ctx, targetPaths
 }
 
+func (m *mapLiteral) Analyse(ctx *AnalysisContext) ValueType {
+	for _, kv := range m.keyValues {
+		if fn, ok := kv[0].(Function); ok {
+			if keyType := Analyse(ctx, fn); isKnownType(keyType) && keyType != ValueString && keyType != ValueBytes {
+				ctx.Report("object keys must be strings, got %v", keyType)
+			}
+		}
+		if fn, ok := kv[1].(Function); ok {
+			_ = Analyse(ctx, fn)
+		}
+	}
+	return ValueObject
+}
+
 //------------------------------------------------------------------------------
 
 var _ Function = &arrayLiteral{}
@@ -193,3 +207,12 @@ func (a *arrayLiteral) QueryTargets(ctx TargetsContext) (TargetsContext, []Targe
 	// TODO: Mark next context with aliases?
 	return ctx, targetPaths
 }
+
+func (a *arrayLiteral) Analyse(ctx *AnalysisContext) ValueType {
+	for _, v := range a.values {
+		if fn, ok := v.(Function); ok {
+			_ = Analyse(ctx, fn)
+		}
+	}
+	return ValueArray
+}
@@ -66,7 +66,7 @@ func (m *MethodSet) Init(name string, target Function, args *ParsedParams) (Func
 		return nil, badMethodErr(name)
 	}
 	if m.disableCtors {
-		return disabledMethod(name), nil
+		return disabledMethod(m.specs[name], target, args), nil
 	}
 	return wrapMethodCtorWithDynamicArgs(name, target, args, ctor)
 }
@@ -156,10 +156,56 @@ func MethodDocs() []MethodSpec {
 
 //------------------------------------------------------------------------------
 
-func disabledMethod(name string) Function {
-	return ClosureFunction("method "+name, func(ctx FunctionContext) (interface{}, error) {
-		return nil, errors.New("this method has been disabled")
-	}, func(ctx TargetsContext) (TargetsContext, []TargetPath) { return ctx, nil })
+// disabledMethodCall is a method that cannot be executed, but retains the
+// spec, target and arguments of the call for static analysis.
+type disabledMethodCall struct {
+	Function
+
+	spec   MethodSpec
+	target Function
+	args   *ParsedParams
+}
+
+func disabledMethod(spec MethodSpec, target Function, args *ParsedParams) Function {
+	return &disabledMethodCall{
+		Function: ClosureFunction("method "+spec.Name, func(ctx FunctionContext) (interface{}, error) {
+			return nil, errors.New("this method has been disabled")
+		}, func(ctx TargetsContext) (TargetsContext, []TargetPath) { return ctx, nil }),
+		spec:   spec,
+		target: target,
+		args:   args,
+	}
+}
+
+func (d *disabledMethodCall) Analyse(ctx *AnalysisContext) ValueType {
+	targetType := Analyse(ctx, d.target)
+	analyseArgs(ctx, d.args)
+
+	if len(d.spec.InputTypes) > 0 && isKnownType(targetType) {
+		var supported bool
+		for _, t := range d.spec.InputTypes {
+			if t == targetType {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			ctx.Report("method %v cannot be called on a %v value, expected %v", d.spec.Name, targetType, typesString(d.spec.InputTypes))
+		}
+	}
+
+	if d.spec.Name == "apply" {
+		if name, ok := literalArg(d.args, "mapping"); ok {
+			if _, exists := ctx.Maps[IToString(name)]; !exists {
+				ctx.Report("map %v is not declared", name)
+			}
+		}
+	}
+
+	if d.spec.ReturnType == "" {
+		return ValueUnknown
+	}
+	return d.spec.ReturnType
 }
 
 func wrapMethodCtorWithDynamicArgs(name string, target Function, args *ParsedParams, fn MethodCtor) (Function, error) {
@@ -77,7 +77,7 @@ func applyMethod(target Function, args *ParsedParams) (Function, error) {
 //------------------------------------------------------------------------------
 
 var _ = registerMethod(
-	NewMethodSpec("bool", "").InCategory(
+	NewMethodSpec("bool", "").Returns(ValueBool).InCategory(
 		MethodCategoryCoercion,
 		"Attempt to parse a value into a boolean. An optional argument can be provided, in which case if the value cannot be parsed the argument will be returned instead. If the value is a number then any non-zero value will resolve to `true`, if the value is a string then any of the following values are considered valid: `1, t, T, TRUE, true, True, 0, f, F, FALSE`.",
 		NewExampleSpec("",
@@ -279,6 +279,11 @@ func (g *getMethod) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPa
 	return ctx, append(fnPaths, paths...)
 }
 
+func (g *getMethod) Analyse(ctx *AnalysisContext) ValueType {
+	_ = Analyse(ctx, g.fn)
+	return ValueUnknown
+}
+
 // NewGetMethod creates a new get method.
 func NewGetMethod(target Function, pathStr string) (Function, error) {
 	path := gabs.DotPathToSlice(pathStr)
@@ -317,7 +322,7 @@ var _ = registerMethod(
 
 // NewMapMethod attempts to create a map method.
 func NewMapMethod(target, mapFn Function) (Function, error) {
-	return ClosureFunction(mapFn.Annotation(), func(ctx FunctionContext) (interface{}, error) {
+	fn := ClosureFunction(mapFn.Annotation(), func(ctx FunctionContext) (interface{}, error) {
 		res, err := target.Exec(ctx)
 		if err != nil {
 			return nil, err
@@ -329,7 +334,20 @@ func NewMapMethod(target, mapFn Function) (Function, error) {
 
 		returnCtx, mapTargets := mapFn.QueryTargets(mapCtx)
 		return returnCtx, append(targets, mapTargets...)
-	}), nil
+	})
+	return &mapMethodFunction{Function: fn, target: target, mapFn: mapFn}, nil
+}
+
+type mapMethodFunction struct {
+	Function
+
+	target Function
+	mapFn  Function
+}
+
+func (m *mapMethodFunction) Analyse(ctx *AnalysisContext) ValueType {
+	_ = Analyse(ctx, m.target)
+	return Analyse(ctx, m.mapFn)
 }
 
 func mapMethod(target Function, args *ParsedParams) (Function, error) {
@@ -342,7 +360,7 @@ func mapMethod(target Function, args *ParsedParams) (Function, error) {
 
 //------------------------------------------------------------------------------
 
-var _ = registerMethod(NewHiddenMethodSpec("not"), notMethodCtor)
+var _ = registerMethod(NewHiddenMethodSpec("not").Returns(ValueBool), notMethodCtor)
 
 type notMethod struct {
 	fn Function
@@ -375,6 +393,13 @@ func (n *notMethod) QueryTargets(ctx TargetsContext) (TargetsContext, []TargetPa
 	return n.fn.QueryTargets(ctx)
 }
 
+func (n *notMethod) Analyse(ctx *AnalysisContext) ValueType {
+	if t := Analyse(ctx, n.fn); isKnownType(t) && t != ValueBool {
+		ctx.Report("expected bool value for not, got %v", t)
+	}
+	return ValueBool
+}
+
 func notMethodCtor(target Function, _ *ParsedParams) (Function, error) {
 	return &notMethod{fn: target}, nil
 }
@@ -410,7 +435,7 @@ var _ = registerSimpleMethod(
 var _ = registerMethod(
 	NewMethodSpec(
 		"number", "",
-	).InCategory(
+	).Returns(ValueNumber).InCategory(
 		MethodCategoryCoercion,
 		"Attempt to parse a value into a number. An optional argument can be provided, in which case if the value cannot be parsed into a number the argument will be returned instead.",
 		NewExampleSpec("",
@@ -474,9 +499,9 @@ func orMethod(fn Function, args *ParsedParams) (Function, error) {
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"type", "",
-	).InCategory(
+	).Returns(ValueString).InCategory(
 		MethodCategoryCoercion,
-		"Returns the type of a value as a string, providing one of the following values: `string`, `bytes`, `number`, `bool`, `array`, `object` or `null`.",
+		"Returns the type of a value as a string, providing one of the following values: `string`, `bytes`, `number`, `bool`, `timestamp`, `array`, `object` or `null`.",
 		NewExampleSpec("",
 			`root.bar_type = this.bar.type()
 root.foo_type = this.foo.type()`,
@@ -7,7 +7,7 @@ import (
 )
 
 var _ = registerSimpleMethod(
-	NewMethodSpec("abs", "Returns the absolute value of a number.").InCategory(
+	NewMethodSpec("abs", "Returns the absolute value of a number.").OnTypes(ValueNumber).Returns(ValueNumber).InCategory(
 		MethodCategoryNumbers, "",
 		NewExampleSpec("",
 			`root.new_value = this.value.abs()`,
@@ -33,7 +33,7 @@ var _ = registerSimpleMethod(
 )
 
 var _ = registerSimpleMethod(
-	NewMethodSpec("ceil", "Returns the least integer value greater than or equal to a number.").InCategory(
+	NewMethodSpec("ceil", "Returns the least integer value greater than or equal to a number.").OnTypes(ValueNumber).Returns(ValueNumber).InCategory(
 		MethodCategoryNumbers, "",
 		NewExampleSpec("",
 			`root.new_value = this.value.ceil()`,
@@ -59,7 +59,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"floor", "Returns the greatest integer value less than or equal to the target number.",
-	).InCategory(
+	).OnTypes(ValueNumber).Returns(ValueNumber).InCategory(
 		MethodCategoryNumbers,
 		"",
 		NewExampleSpec("",
@@ -82,7 +82,7 @@ var _ = registerSimpleMethod(
 )
 
 var _ = registerSimpleMethod(
-	NewMethodSpec("log", "Returns the natural logarithm of a number.").InCategory(
+	NewMethodSpec("log", "Returns the natural logarithm of a number.").OnTypes(ValueNumber).Returns(ValueNumber).InCategory(
 		MethodCategoryNumbers, "",
 		NewExampleSpec("",
 			`root.new_value = this.value.log().round()`,
@@ -108,7 +108,7 @@ var _ = registerSimpleMethod(
 )
 
 var _ = registerSimpleMethod(
-	NewMethodSpec("log10", "Returns the decimal logarithm of a number.").InCategory(
+	NewMethodSpec("log10", "Returns the decimal logarithm of a number.").OnTypes(ValueNumber).Returns(ValueNumber).InCategory(
 		MethodCategoryNumbers, "",
 		NewExampleSpec("",
 			`root.new_value = this.value.log10()`,
@@ -222,7 +222,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"round", "Rounds numbers to the nearest integer, rounding half away from zero.",
-	).InCategory(
+	).OnTypes(ValueNumber).Returns(ValueNumber).InCategory(
 		MethodCategoryNumbers,
 		"",
 		NewExampleSpec("",
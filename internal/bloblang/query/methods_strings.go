@@ -40,7 +40,7 @@ import (
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"bytes", "",
-	).InCategory(
+	).Returns(ValueBytes).InCategory(
 		MethodCategoryCoercion,
 		"Marshal a value into a byte array. If the value is already a byte array it is unchanged.",
 		NewExampleSpec("",
@@ -61,7 +61,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"capitalize", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryStrings,
 		"Takes a string value and returns a copy with all Unicode letters that begin words mapped to their Unicode title case.",
 		NewExampleSpec("",
@@ -439,7 +439,7 @@ root.decrypted = this.value.decode("hex").decrypt_aes("ctr", $key, $vector).stri
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"escape_html", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueString).InCategory(
 		MethodCategoryStrings,
 		"Escapes a string so that special characters like `<` to become `&lt;`. It escapes only five such characters: `<`, `>`, `&`, `'` and `\"` so that it can be safely placed within an HTML entity.",
 		NewExampleSpec("",
@@ -460,7 +460,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"index_of", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueNumber).InCategory(
 		MethodCategoryStrings,
 		"Returns the starting index of the argument substring in a string target, or `-1` if the target doesn't contain the argument.",
 		NewExampleSpec("",
@@ -496,7 +496,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"unescape_html", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueString).InCategory(
 		MethodCategoryStrings,
 		"Unescapes a string so that entities like `&lt;` become `<`. It unescapes a larger range of entities than `escape_html` escapes. For example, `&aacute;` unescapes to `á`, as does `&#225;` and `&xE1;`.",
 		NewExampleSpec("",
@@ -517,7 +517,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"escape_url_query", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueString).InCategory(
 		MethodCategoryStrings,
 		"Escapes a string so that it can be safely placed within a URL query.",
 		NewExampleSpec("",
@@ -538,7 +538,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"unescape_url_query", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueString).InCategory(
 		MethodCategoryStrings,
 		"Expands escape sequences from a URL query string.",
 		NewExampleSpec("",
@@ -635,7 +635,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"has_prefix", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueBool).InCategory(
 		MethodCategoryStrings,
 		"Checks whether a string has a prefix argument and returns a bool.",
 		NewExampleSpec("",
@@ -668,7 +668,7 @@ root.t2 = this.v2.has_prefix("foo")`,
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"has_suffix", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueBool).InCategory(
 		MethodCategoryStrings,
 		"Checks whether a string has a suffix argument and returns a bool.",
 		NewExampleSpec("",
@@ -814,7 +814,7 @@ root.h2 = this.value.hash("hmac_sha1","static-key").encode("hex")`,
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"join", "",
-	).InCategory(
+	).OnTypes(ValueArray).Returns(ValueString).InCategory(
 		MethodCategoryObjectAndArray,
 		"Join an array of strings with an optional delimiter into a single string.",
 		NewExampleSpec("",
@@ -863,7 +863,7 @@ root.joined_numbers = this.numbers.map_each(this.string()).join(",")`,
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"uppercase", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryStrings,
 		"Convert a string value into uppercase.",
 		NewExampleSpec("",
@@ -891,7 +891,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"lowercase", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryStrings,
 		"Convert a string value into lowercase.",
 		NewExampleSpec("",
@@ -977,7 +977,7 @@ func parseCSVMethod(*ParsedParams) (simpleMethod, error) {
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"parse_json", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryParsing,
 		"Attempts to parse a string as a JSON document and returns the result.",
 		NewExampleSpec("",
@@ -1065,7 +1065,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"parse_yaml", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryParsing,
 		"Attempts to parse a string as a single YAML document and returns the result.",
 		NewExampleSpec("",
@@ -1326,7 +1326,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"reverse", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryStrings,
 		"Returns the target string in reverse order.",
 		NewExampleSpec("",
@@ -1544,7 +1544,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"quote", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueString).InCategory(
 		MethodCategoryStrings,
 		"Quotes a target string using escape sequences (`\\t`, `\\n`, `\\xFF`, `\\u0100`) for control characters and non-printable characters.",
 		NewExampleSpec("",
@@ -1565,7 +1565,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"unquote", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueString).InCategory(
 		MethodCategoryStrings,
 		"Unquotes a target string, expanding any escape sequences (`\\t`, `\\n`, `\\xFF`, `\\u0100`) for control characters and non-printable characters.",
 		NewExampleSpec("",
@@ -1593,7 +1593,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"replace_all", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryStrings,
 		"Replaces all occurrences of the first argument in a target string with the second argument.",
 		NewExampleSpec("",
@@ -1927,7 +1927,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"re_match", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueBool).InCategory(
 		MethodCategoryRegexp,
 		"Checks whether a regular expression matches against any part of a string and returns a boolean.",
 		NewExampleSpec("",
@@ -1974,7 +1974,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"re_replace_all", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryRegexp,
 		"Replaces all occurrences of the argument regular expression in a string with a value. Inside the value $ signs are interpreted as submatch expansions, e.g. `$1` represents the text of the first submatch.",
 		NewExampleSpec("",
@@ -2021,7 +2021,7 @@ func reReplaceAllImpl(args *ParsedParams) (simpleMethod, error) {
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"split", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).Returns(ValueArray).InCategory(
 		MethodCategoryStrings,
 		"Split a string value into an array of strings by splitting it on a string separator.",
 		NewExampleSpec("",
@@ -2063,7 +2063,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"string", "",
-	).InCategory(
+	).Returns(ValueString).InCategory(
 		MethodCategoryCoercion,
 		"Marshal a value into a string. If the value is already a string it is unchanged.",
 		NewExampleSpec("",
@@ -2136,7 +2136,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"trim", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes).InCategory(
 		MethodCategoryStrings,
 		"Remove all leading and trailing characters from a string that are contained within an argument cutset. If no arguments are provided then whitespace is removed.",
 		NewExampleSpec("",
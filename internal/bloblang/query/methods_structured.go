@@ -176,7 +176,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"contains", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes, ValueArray, ValueObject).Returns(ValueBool).InCategory(
 		MethodCategoryObjectAndArray,
 		"Checks whether an array contains an element matching the argument, or an object contains a value matching the argument, and returns a boolean result. Numerical comparisons are made irrespective of the representation type (float versus integer).",
 		NewExampleSpec("",
@@ -254,7 +254,7 @@ var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"enumerated",
 		"Converts an array into a new array of objects, where each object has a field index containing the `index` of the element and a field `value` containing the original value of the element.",
-	).InCategory(
+	).OnTypes(ValueArray).Returns(ValueArray).InCategory(
 		MethodCategoryObjectAndArray, "",
 		NewExampleSpec("",
 			`root.foo = this.foo.enumerated()`,
@@ -295,7 +295,7 @@ var _ = registerSimpleMethod(
 			`{"foo":{}}`,
 			`{"result":false}`,
 		),
-	).Param(ParamString("path", "A [dot path][field_paths] to a field.")),
+	).Returns(ValueBool).Param(ParamString("path", "A [dot path][field_paths] to a field.")),
 	func(args *ParsedParams) (simpleMethod, error) {
 		pathStr, err := args.FieldString("path")
 		if err != nil {
@@ -435,7 +435,7 @@ var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"flatten",
 		"Iterates an array and any element that is itself an array is removed and has its elements inserted directly in the resulting array.",
-	).InCategory(
+	).OnTypes(ValueArray).Returns(ValueArray).InCategory(
 		MethodCategoryObjectAndArray, "",
 		NewExampleSpec(``,
 			`root.result = this.flatten()`,
@@ -638,7 +638,7 @@ var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"keys",
 		"Returns the keys of an object as an array.",
-	).InCategory(
+	).OnTypes(ValueObject).Returns(ValueArray).InCategory(
 		MethodCategoryObjectAndArray, "",
 		NewExampleSpec("",
 			`root.foo_keys = this.foo.keys()`,
@@ -667,7 +667,7 @@ var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"key_values",
 		"Returns the key/value pairs of an object as an array, where each element is an object with a `key` field and a `value` field. The order of the resulting array will be random.",
-	).InCategory(
+	).OnTypes(ValueObject).Returns(ValueArray).InCategory(
 		MethodCategoryObjectAndArray, "",
 		NewExampleSpec("",
 			`root.foo_key_values = this.foo.key_values().sort_by(pair -> pair.key)`,
@@ -698,7 +698,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"length", "",
-	).InCategory(
+	).OnTypes(ValueString, ValueBytes, ValueArray, ValueObject).Returns(ValueNumber).InCategory(
 		MethodCategoryStrings, "Returns the length of a string.",
 		NewExampleSpec("",
 			`root.foo_len = this.foo.length()`,
@@ -1475,7 +1475,7 @@ func uniqueMethod(args *ParsedParams) (simpleMethod, error) {
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"values", "",
-	).InCategory(
+	).OnTypes(ValueObject).Returns(ValueArray).InCategory(
 		MethodCategoryObjectAndArray,
 		"Returns the values of an object as an array. The order of the resulting array will be random.",
 		NewExampleSpec("",
@@ -1503,7 +1503,7 @@ var _ = registerSimpleMethod(
 var _ = registerSimpleMethod(
 	NewMethodSpec(
 		"without", "",
-	).InCategory(
+	).OnTypes(ValueObject).Returns(ValueObject).InCategory(
 		MethodCategoryObjectAndArray,
 		`Returns an object where one or more [field path][field_paths] arguments are removed. Each path specifies a specific field to be deleted from the input object, allowing for nested fields.
 
@@ -0,0 +1,218 @@
+package query
+
+import (
+	"fmt"
+	"time"
+)
+
+// IGetDuration takes a boxed value and attempts to parse it as a duration
+// string such as `1h30m`. Numbers are rejected as they would be ambiguous
+// between the seconds of numerical timestamps and the nanoseconds of durations
+// such as those returned by `ts_diff`.
+func IGetDuration(v interface{}) (time.Duration, error) {
+	switch t := ISanitize(v).(type) {
+	case string:
+		return time.ParseDuration(t)
+	case []byte:
+		return time.ParseDuration(string(t))
+	}
+	return 0, NewTypeError(v, ValueString)
+}
+
+// durationMethod creates a simple method from a function that is applied to a
+// timestamp and a duration, where the duration is provided as a parameter.
+func durationMethod(args *ParsedParams, fn func(t time.Time, d time.Duration) time.Time) (simpleMethod, error) {
+	durationV, err := args.Field("duration")
+	if err != nil {
+		return nil, err
+	}
+	d, err := IGetDuration(durationV)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duration: %w", err)
+	}
+	return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+		target, err := IGetTimestamp(v)
+		if err != nil {
+			return nil, err
+		}
+		return fn(target, d), nil
+	}, nil
+}
+
+// inLocalDays applies a rounding function to a timestamp. Rounding to durations
+// shorter than a day is relative to the zero time in UTC, whereas durations of
+// a day or longer are relative to the wall clock of the timestamp's location so
+// that days begin at midnight in that location.
+func inLocalDays(t time.Time, d time.Duration, fn func(t time.Time) time.Time) time.Time {
+	if d < 24*time.Hour {
+		return fn(t)
+	}
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	r := fn(wall)
+	return time.Date(r.Year(), r.Month(), r.Day(), r.Hour(), r.Minute(), r.Second(), r.Nanosecond(), t.Location())
+}
+
+func durationParam() ParamDefinition {
+	return ParamAny("duration", "A duration string such as `1h30m`. Numbers are not accepted, as they would be ambiguous between seconds and nanoseconds.")
+}
+
+//------------------------------------------------------------------------------
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"ts_add", "",
+	).Returns(ValueTimestamp).InCategory(
+		MethodCategoryTime,
+		"Adds a duration to a timestamp value and returns the result as a timestamp. Timestamp values can either be a numerical unix time in seconds (with up to nanosecond precision via decimals), a string in ISO 8601 format, or a timestamp value.",
+		NewExampleSpec("",
+			`root.expires_at = this.created_at.ts_add("1h30m")`,
+			`{"created_at":"2021-02-03T16:05:06Z"}`,
+			`{"expires_at":"2021-02-03T17:35:06Z"}`,
+		),
+		NewExampleSpec("Durations can also be taken from the input document.",
+			`root.expires_at = this.created_at.ts_add(this.ttl)`,
+			`{"created_at":"2021-02-03T16:05:06Z","ttl":"45s"}`,
+			`{"expires_at":"2021-02-03T16:05:51Z"}`,
+		),
+	).Beta().Param(durationParam()),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return durationMethod(args, func(t time.Time, d time.Duration) time.Time {
+			return t.Add(d)
+		})
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"ts_sub", "",
+	).Returns(ValueTimestamp).InCategory(
+		MethodCategoryTime,
+		"Subtracts a duration from a timestamp value and returns the result as a timestamp. Timestamp values can either be a numerical unix time in seconds (with up to nanosecond precision via decimals), a string in ISO 8601 format, or a timestamp value. In order to find the duration between two timestamps use [`ts_diff`](#ts_diff).",
+		NewExampleSpec("",
+			`root.window_start = this.created_at.ts_sub("24h")`,
+			`{"created_at":"2021-02-03T16:05:06Z"}`,
+			`{"window_start":"2021-02-02T16:05:06Z"}`,
+		),
+	).Beta().Param(durationParam()),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return durationMethod(args, func(t time.Time, d time.Duration) time.Time {
+			return t.Add(-d)
+		})
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"ts_round", "",
+	).Returns(ValueTimestamp).InCategory(
+		MethodCategoryTime,
+		"Rounds a timestamp value to the nearest multiple of a duration since the zero time, where halfway values are rounded up, and returns the result as a timestamp. Rounding to durations of a day or longer is relative to the timezone of the timestamp, so that days begin at midnight in that timezone. Timestamp values can either be a numerical unix time in seconds (with up to nanosecond precision via decimals), a string in ISO 8601 format, or a timestamp value.",
+		NewExampleSpec("",
+			`root.nearest_hour = this.created_at.ts_round("1h")`,
+			`{"created_at":"2021-02-03T16:35:06Z"}`,
+			`{"nearest_hour":"2021-02-03T17:00:00Z"}`,
+		),
+	).Beta().Param(durationParam()),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return durationMethod(args, func(t time.Time, d time.Duration) time.Time {
+			return inLocalDays(t, d, func(t time.Time) time.Time {
+				return t.Round(d)
+			})
+		})
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"ts_truncate", "",
+	).Returns(ValueTimestamp).InCategory(
+		MethodCategoryTime,
+		"Rounds a timestamp value down to a multiple of a duration since the zero time and returns the result as a timestamp, which is useful for grouping timestamps into windows. Truncating to durations of a day or longer is relative to the timezone of the timestamp, so that days begin at midnight in that timezone. Timestamp values can either be a numerical unix time in seconds (with up to nanosecond precision via decimals), a string in ISO 8601 format, or a timestamp value.",
+		NewExampleSpec("",
+			`root.hour = this.created_at.ts_truncate("1h")
+root.day = this.created_at.ts_truncate("24h")`,
+			`{"created_at":"2021-02-03T16:35:06Z"}`,
+			`{"day":"2021-02-03T00:00:00Z","hour":"2021-02-03T16:00:00Z"}`,
+		),
+		NewExampleSpec("Days begin at midnight in the timezone of the timestamp.",
+			`root.day = this.created_at.ts_truncate("24h")`,
+			`{"created_at":"2021-02-03T01:35:06-05:00"}`,
+			`{"day":"2021-02-03T00:00:00-05:00"}`,
+		),
+	).Beta().Param(durationParam()),
+	func(args *ParsedParams) (simpleMethod, error) {
+		return durationMethod(args, func(t time.Time, d time.Duration) time.Time {
+			return inLocalDays(t, d, func(t time.Time) time.Time {
+				return t.Truncate(d)
+			})
+		})
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"ts_tz", "",
+	).Returns(ValueTimestamp).InCategory(
+		MethodCategoryTime,
+		"Converts a timestamp value to a timezone and returns the result as a timestamp, the instant in time that the timestamp represents is not changed. Timestamp values can either be a numerical unix time in seconds (with up to nanosecond precision via decimals), a string in ISO 8601 format, or a timestamp value.",
+		NewExampleSpec("",
+			`root.created_at_utc = this.created_at.ts_tz("UTC")`,
+			`{"created_at":"2021-02-03T16:05:06+01:00"}`,
+			`{"created_at_utc":"2021-02-03T15:05:06Z"}`,
+		),
+		NewExampleSpec("",
+			`root.created_at_ny = this.created_at.ts_tz("America/New_York")`,
+			`{"created_at":"2021-02-03T16:05:06+01:00"}`,
+			`{"created_at_ny":"2021-02-03T10:05:06-05:00"}`,
+		),
+	).Beta().Param(ParamString("tz", "The name of an IANA timezone, such as `America/New_York`, or `UTC`.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		tzStr, err := args.FieldString("tz")
+		if err != nil {
+			return nil, err
+		}
+		timezone, err := time.LoadLocation(tzStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timezone location name: %w", err)
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			target, err := IGetTimestamp(v)
+			if err != nil {
+				return nil, err
+			}
+			return target.In(timezone), nil
+		}, nil
+	},
+)
+
+var _ = registerSimpleMethod(
+	NewMethodSpec(
+		"ts_diff", "",
+	).Returns(ValueNumber).InCategory(
+		MethodCategoryTime,
+		"Returns the duration between a timestamp value and another as an integer of nanoseconds, which is negative when the other timestamp is after the target. Timestamp values can either be a numerical unix time in seconds (with up to nanosecond precision via decimals), a string in ISO 8601 format, or a timestamp value.",
+		NewExampleSpec("",
+			`root.took_ns = this.ended_at.ts_diff(this.started_at)
+root.took_seconds = this.ended_at.ts_diff(this.started_at) / 1000000000`,
+			`{"started_at":"2021-02-03T16:05:06Z","ended_at":"2021-02-03T16:07:36Z"}`,
+			`{"took_ns":150000000000,"took_seconds":150}`,
+		),
+	).Beta().Param(ParamAny("other", "The timestamp to subtract from the target.")),
+	func(args *ParsedParams) (simpleMethod, error) {
+		otherV, err := args.Field("other")
+		if err != nil {
+			return nil, err
+		}
+		other, err := IGetTimestamp(otherV)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse other timestamp: %w", err)
+		}
+		return func(v interface{}, ctx FunctionContext) (interface{}, error) {
+			target, err := IGetTimestamp(v)
+			if err != nil {
+				return nil, err
+			}
+			return target.Sub(other).Nanoseconds(), nil
+		}, nil
+	},
+)
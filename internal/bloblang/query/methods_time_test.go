@@ -0,0 +1,146 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIGetDuration(t *testing.T) {
+	for _, test := range []struct {
+		input    interface{}
+		expected time.Duration
+	}{
+		{input: "1h30m", expected: time.Hour + time.Minute*30},
+		{input: []byte("5s"), expected: time.Second * 5},
+		{input: "1.5s", expected: time.Millisecond * 1500},
+	} {
+		d, err := IGetDuration(test.input)
+		require.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, d, test.input)
+	}
+
+	_, err := IGetDuration("nope")
+	require.Error(t, err)
+
+	_, err = IGetDuration(true)
+	require.EqualError(t, err, "expected string value, got bool (true)")
+
+	_, err = IGetDuration(int64(3600))
+	require.EqualError(t, err, "expected string value, got number (3600)")
+}
+
+func TestTimestampRounding(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		method   string
+		target   interface{}
+		duration interface{}
+		expected string
+	}{
+		{
+			name:     "truncate hour",
+			method:   "ts_truncate",
+			target:   "2021-02-03T16:35:06Z",
+			duration: "1h",
+			expected: "2021-02-03T16:00:00Z",
+		},
+		{
+			name:     "round hour",
+			method:   "ts_round",
+			target:   "2021-02-03T16:35:06Z",
+			duration: "1h",
+			expected: "2021-02-03T17:00:00Z",
+		},
+		{
+			name:     "truncate day utc",
+			method:   "ts_truncate",
+			target:   "2021-02-03T16:35:06Z",
+			duration: "24h",
+			expected: "2021-02-03T00:00:00Z",
+		},
+		{
+			name:     "truncate day behind utc",
+			method:   "ts_truncate",
+			target:   "2021-02-03T01:35:06-05:00",
+			duration: "24h",
+			expected: "2021-02-03T00:00:00-05:00",
+		},
+		{
+			name:     "truncate day ahead of utc",
+			method:   "ts_truncate",
+			target:   "2021-02-03T05:00:00+09:00",
+			duration: "24h",
+			expected: "2021-02-03T00:00:00+09:00",
+		},
+		{
+			name:     "round day behind utc",
+			method:   "ts_round",
+			target:   "2021-02-03T13:00:00-05:00",
+			duration: "24h",
+			expected: "2021-02-04T00:00:00-05:00",
+		},
+		{
+			name:     "round day down behind utc",
+			method:   "ts_round",
+			target:   "2021-02-03T11:00:00-05:00",
+			duration: "24h",
+			expected: "2021-02-03T00:00:00-05:00",
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			fn, err := InitMethodHelper(test.method, NewLiteralFunction("", test.target), test.duration)
+			require.NoError(t, err)
+
+			res, err := fn.Exec(FunctionContext{})
+			require.NoError(t, err)
+
+			ts, ok := res.(time.Time)
+			require.True(t, ok, "%T", res)
+			assert.Equal(t, test.expected, ts.Format(time.RFC3339Nano))
+		})
+	}
+}
+
+func TestTimestampRoundingDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Clocks move forward at 2am on this day, and so the offset of midnight
+	// differs from the offset of the timestamp.
+	target := time.Date(2021, time.March, 14, 12, 0, 0, 0, loc)
+
+	fn, err := InitMethodHelper("ts_truncate", NewLiteralFunction("", target), "24h")
+	require.NoError(t, err)
+
+	res, err := fn.Exec(FunctionContext{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, time.March, 14, 0, 0, 0, 0, loc), res)
+}
+
+func TestTimestampMethodErrors(t *testing.T) {
+	_, err := InitMethodHelper("ts_round", NewLiteralFunction("", "2021-02-03T16:35:06Z"), "nope")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse duration")
+
+	_, err = InitMethodHelper("ts_truncate", NewLiteralFunction("", "2021-02-03T16:35:06Z"), true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse duration")
+
+	_, err = InitMethodHelper("ts_tz", NewLiteralFunction("", "2021-02-03T16:35:06Z"), "Not/A_Zone")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse timezone location name")
+
+	for _, method := range []string{"ts_round", "ts_truncate"} {
+		for _, target := range []interface{}{true, "not a timestamp", map[string]interface{}{}} {
+			fn, err := InitMethodHelper(method, NewLiteralFunction("", target), "1h")
+			require.NoError(t, err)
+
+			_, err = fn.Exec(FunctionContext{})
+			require.Error(t, err, "%v %v", method, target)
+		}
+	}
+}
@@ -55,6 +55,10 @@ type MetaMsg interface {
 	MetaGet(key string) string
 	MetaDelete(key string)
 	MetaIter(f func(k, v string) error) error
+
+	MetaSetMut(key string, value interface{})
+	MetaGetMut(key string) (interface{}, bool)
+	MetaIterMut(f func(k string, v interface{}) error) error
 }
 
 // FunctionContext provides access to a range of query targets for functions to
@@ -157,6 +157,19 @@ func TestMappings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "timestamp values",
+			mapping: `root.before = this.a.ts_tz("UTC") < this.b.ts_tz("UTC")
+root.equal = this.a.ts_tz("UTC") == this.c
+root.diff = this.b.ts_add("1h").ts_diff(this.a)
+root.type = this.a.ts_tz("UTC").type()`,
+			inputOutputs: [][2]string{
+				{
+					`{"a":"2021-02-03T16:05:06Z","b":"2021-02-03T17:05:07+01:00","c":"2021-02-03T17:05:06+01:00"}`,
+					`{"before":true,"diff":3601000000000,"equal":true,"type":"timestamp"}`,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -30,17 +30,18 @@ type ValueType string
 
 // ValueType variants.
 var (
-	ValueString  ValueType = "string"
-	ValueBytes   ValueType = "bytes"
-	ValueNumber  ValueType = "number"
-	ValueBool    ValueType = "bool"
-	ValueArray   ValueType = "array"
-	ValueObject  ValueType = "object"
-	ValueNull    ValueType = "null"
-	ValueDelete  ValueType = "delete"
-	ValueNothing ValueType = "nothing"
-	ValueQuery   ValueType = "query expression"
-	ValueUnknown ValueType = "unknown"
+	ValueString    ValueType = "string"
+	ValueBytes     ValueType = "bytes"
+	ValueNumber    ValueType = "number"
+	ValueBool      ValueType = "bool"
+	ValueArray     ValueType = "array"
+	ValueObject    ValueType = "object"
+	ValueNull      ValueType = "null"
+	ValueTimestamp ValueType = "timestamp"
+	ValueDelete    ValueType = "delete"
+	ValueNothing   ValueType = "nothing"
+	ValueQuery     ValueType = "query expression"
+	ValueUnknown   ValueType = "unknown"
 
 	// Specialised and not generally known over ValueNumber.
 	ValueInt   ValueType = "integer"
@@ -63,6 +64,8 @@ func ITypeOf(i interface{}) ValueType {
 		return ValueArray
 	case map[string]interface{}:
 		return ValueObject
+	case time.Time:
+		return ValueTimestamp
 	case Delete:
 		return ValueDelete
 	case Nothing:
@@ -197,6 +200,9 @@ func IGetBytes(v interface{}) ([]byte, error) {
 // either by interpretting a numerical value as a unix timestamp, or by parsing
 // a string value as RFC3339Nano.
 func IGetTimestamp(v interface{}) (time.Time, error) {
+	if t, ok := v.(time.Time); ok {
+		return t, nil
+	}
 	switch t := ISanitize(v).(type) {
 	case int64:
 		return time.Unix(t, 0), nil
@@ -221,7 +227,7 @@ func IGetTimestamp(v interface{}) (time.Time, error) {
 	case string:
 		return time.Parse(time.RFC3339Nano, t)
 	}
-	return time.Time{}, NewTypeError(v, ValueNumber, ValueString)
+	return time.Time{}, NewTypeError(v, ValueNumber, ValueString, ValueTimestamp)
 }
 
 // IIsNull returns whether a bloblang type is null, this includes Delete and
@@ -281,6 +287,8 @@ func IToBytes(i interface{}) []byte {
 		return t
 	case json.Number:
 		return []byte(t.String())
+	case time.Time:
+		return []byte(t.Format(time.RFC3339Nano))
 	case int64, uint64, float64:
 		return []byte(fmt.Sprintf("%v", t)) // TODO
 	case bool:
@@ -307,6 +315,8 @@ func IToString(i interface{}) string {
 		return fmt.Sprintf("%v", t) // TODO
 	case json.Number:
 		return t.String()
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
 	case bool:
 		if t {
 			return "true"
@@ -13,6 +13,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/component/ratelimit"
 	"github.com/benthosdev/benthos/v4/internal/interop"
+	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/manager/mock"
 	linput "github.com/benthosdev/benthos/v4/internal/old/input"
 	loutput "github.com/benthosdev/benthos/v4/internal/old/output"
@@ -41,6 +42,9 @@ func (m *Manager) IntoPath(segments ...string) interop.Manager { return m }
 // WithAddedMetrics returns the same mock manager.
 func (m *Manager) WithAddedMetrics(m2 metrics.Type) interop.Manager { return m }
 
+// WithLogger returns the same mock manager.
+func (m *Manager) WithLogger(l log.Modular) interop.Manager { return m }
+
 // NewBuffer always errors on invalid type.
 func (m *Manager) NewBuffer(conf buffer.Config) (buffer.Streamed, error) {
 	return nil, component.ErrInvalidType("buffer", conf.Type)
@@ -0,0 +1,158 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+)
+
+// Cache is the subset of cache resource functionality required in order to
+// persist checkpoints. A missing key must be reported by Get with an error
+// matching component.ErrKeyNotFound.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheAccessFunc provides access to a cache for the duration of a closure.
+type CacheAccessFunc func(ctx context.Context, fn func(c Cache)) error
+
+// CacheManager is a component manager that provides access to cache resources.
+type CacheManager interface {
+	AccessCache(ctx context.Context, name string, fn func(cache.V1)) error
+}
+
+// ManagerCacheAccess returns a CacheAccessFunc for a named cache resource of a
+// manager.
+func ManagerCacheAccess(mgr CacheManager, name string) CacheAccessFunc {
+	return func(ctx context.Context, fn func(c Cache)) error {
+		return mgr.AccessCache(ctx, name, func(c cache.V1) {
+			fn(c)
+		})
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// CacheStore tracks offsets with a Capped checkpointer and persists the highest
+// resolved offset into a cache under a given key, which allows an input to
+// resume from the latest delivered offset after a restart.
+//
+// Offsets are strings in order to support any form of cursor, but must be
+// tracked in the order that they are consumed.
+//
+// This component is safe to use concurrently across goroutines.
+type CacheStore struct {
+	access CacheAccessFunc
+	key    string
+	capped *Capped
+
+	mut    sync.Mutex
+	stored string
+}
+
+// NewCacheStore returns a new cache backed checkpoint store, where capacity is
+// the maximum number of unresolved offsets that may be tracked at once.
+func NewCacheStore(access CacheAccessFunc, key string, capacity int64) *CacheStore {
+	return &CacheStore{
+		access: access,
+		key:    key,
+		capped: NewCapped(capacity),
+	}
+}
+
+// Load returns the offset persisted within the cache, and false if it does not
+// exist.
+func (s *CacheStore) Load(ctx context.Context) (string, bool, error) {
+	var offset []byte
+	var cErr error
+	if err := s.access(ctx, func(c Cache) {
+		offset, cErr = c.Get(ctx, s.key)
+	}); err != nil {
+		return "", false, err
+	}
+	if errors.Is(cErr, component.ErrKeyNotFound) {
+		return "", false, nil
+	}
+	if cErr != nil {
+		return "", false, cErr
+	}
+
+	s.mut.Lock()
+	s.stored = string(offset)
+	s.mut.Unlock()
+	return string(offset), true, nil
+}
+
+// Track a new unresolved offset, blocking when the number of unresolved
+// offsets meets the capacity of the store. The returned function resolves the
+// offset, and persists the highest offset able to be committed when it has
+// changed since the last time it was persisted.
+func (s *CacheStore) Track(ctx context.Context, offset string, batchSize int64) (func(context.Context) error, error) {
+	resolveFn, err := s.capped.Track(ctx, offset, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		// Resolving and persisting is performed under a lock in order to
+		// prevent a lower offset from overwriting a higher one.
+		s.mut.Lock()
+		defer s.mut.Unlock()
+
+		highest, _ := resolveFn().(string)
+		return s.persist(ctx, highest)
+	}, nil
+}
+
+// Set persists an offset that has been resolved without being tracked by the
+// store, which is useful for inputs that already track their own offsets. The
+// offset is only written to the cache when it has changed since the last time
+// it was persisted.
+func (s *CacheStore) Set(ctx context.Context, offset string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.persist(ctx, offset)
+}
+
+// Delete removes the offset persisted within the cache.
+func (s *CacheStore) Delete(ctx context.Context) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	var cErr error
+	if err := s.access(ctx, func(c Cache) {
+		cErr = c.Delete(ctx, s.key)
+	}); err != nil {
+		return err
+	}
+	if cErr != nil && !errors.Is(cErr, component.ErrKeyNotFound) {
+		return cErr
+	}
+	s.stored = ""
+	return nil
+}
+
+// persist writes an offset to the cache, and must be called with the mutex
+// held.
+func (s *CacheStore) persist(ctx context.Context, offset string) error {
+	if offset == "" || offset == s.stored {
+		return nil
+	}
+
+	var cErr error
+	if err := s.access(ctx, func(c Cache) {
+		cErr = c.Set(ctx, s.key, []byte(offset), nil)
+	}); err != nil {
+		return err
+	}
+	if cErr != nil {
+		return cErr
+	}
+	s.stored = offset
+	return nil
+}
@@ -0,0 +1,136 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+)
+
+type mapCache map[string][]byte
+
+func (m mapCache) Get(ctx context.Context, key string) ([]byte, error) {
+	v, exists := m[key]
+	if !exists {
+		return nil, component.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func (m mapCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	m[key] = value
+	return nil
+}
+
+func (m mapCache) Delete(ctx context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+func mapCacheAccess(m mapCache) CacheAccessFunc {
+	return func(ctx context.Context, fn func(c Cache)) error {
+		fn(m)
+		return nil
+	}
+}
+
+func TestCacheStoreLoad(t *testing.T) {
+	ctx := context.Background()
+	c := mapCache{}
+	s := NewCacheStore(mapCacheAccess(c), "foo", 10)
+
+	_, exists, err := s.Load(ctx)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	c["foo"] = []byte("bar")
+
+	offset, exists, err := s.Load(ctx)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "bar", offset)
+
+	require.NoError(t, s.Delete(ctx))
+	_, exists = c["foo"]
+	assert.False(t, exists)
+
+	errAccess := errors.New("nope")
+	s = NewCacheStore(func(ctx context.Context, fn func(c Cache)) error {
+		return errAccess
+	}, "foo", 10)
+	_, _, err = s.Load(ctx)
+	assert.Equal(t, errAccess, err)
+}
+
+func TestCacheStoreTrack(t *testing.T) {
+	ctx := context.Background()
+	c := mapCache{}
+	s := NewCacheStore(mapCacheAccess(c), "foo", 10)
+
+	resolveA, err := s.Track(ctx, "a", 1)
+	require.NoError(t, err)
+	resolveB, err := s.Track(ctx, "b", 1)
+	require.NoError(t, err)
+	resolveC, err := s.Track(ctx, "c", 1)
+	require.NoError(t, err)
+
+	// Resolving out of order must not persist until prior offsets resolve.
+	require.NoError(t, resolveB(ctx))
+	_, exists := c["foo"]
+	assert.False(t, exists)
+
+	require.NoError(t, resolveA(ctx))
+	assert.Equal(t, "b", string(c["foo"]))
+
+	require.NoError(t, resolveC(ctx))
+	assert.Equal(t, "c", string(c["foo"]))
+}
+
+func TestCacheStoreTrackCapped(t *testing.T) {
+	ctx := context.Background()
+	s := NewCacheStore(mapCacheAccess(mapCache{}), "foo", 1)
+
+	resolveA, err := s.Track(ctx, "a", 1)
+	require.NoError(t, err)
+
+	tCtx, done := context.WithTimeout(ctx, time.Millisecond*50)
+	defer done()
+	_, err = s.Track(tCtx, "b", 1)
+	require.Error(t, err)
+
+	require.NoError(t, resolveA(ctx))
+	_, err = s.Track(ctx, "b", 1)
+	require.NoError(t, err)
+}
+
+func TestCacheStoreSet(t *testing.T) {
+	ctx := context.Background()
+
+	var sets int
+	c := mapCache{}
+	s := NewCacheStore(func(ctx context.Context, fn func(c Cache)) error {
+		sets++
+		fn(c)
+		return nil
+	}, "foo", 10)
+
+	require.NoError(t, s.Set(ctx, "a"))
+	assert.Equal(t, "a", string(c["foo"]))
+	assert.Equal(t, 1, sets)
+
+	// Setting the same offset again does not write to the cache.
+	require.NoError(t, s.Set(ctx, "a"))
+	assert.Equal(t, 1, sets)
+
+	require.NoError(t, s.Set(ctx, ""))
+	assert.Equal(t, "a", string(c["foo"]))
+
+	require.NoError(t, s.Set(ctx, "b"))
+	assert.Equal(t, "b", string(c["foo"]))
+	assert.Equal(t, 2, sets)
+}
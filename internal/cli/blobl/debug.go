@@ -0,0 +1,171 @@
+package blobl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// debugBranch describes a branch taken during a step, or a statement executed
+// during a step within a mapping imported by the debugged mapping. The source
+// is the path of the imported mapping, and is empty for the debugged mapping.
+type debugBranch struct {
+	Source string `json:"source,omitempty"`
+	Kind   string `json:"kind"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// debugStep describes the state of a mapping after the execution of a single
+// assignment statement.
+type debugStep struct {
+	Line       int               `json:"line"`
+	Column     int               `json:"column"`
+	Target     string            `json:"target"`
+	Value      string            `json:"value"`
+	Skipped    bool              `json:"skipped"`
+	Root       string            `json:"root"`
+	Vars       map[string]string `json:"vars"`
+	Meta       map[string]string `json:"meta"`
+	Branches   []debugBranch     `json:"branches"`
+	Breakpoint bool              `json:"breakpoint"`
+}
+
+// debugResult describes the execution of a mapping by the debugger, where the
+// value of this is the same for each step as only the root level statements of
+// the mapping are stepped through.
+type debugResult struct {
+	This  string      `json:"this"`
+	Steps []debugStep `json:"steps"`
+}
+
+// debugValue returns a representation of a value for displaying within the
+// debugger, where structured values are shown as JSON.
+func debugValue(v interface{}) string {
+	switch t := v.(type) {
+	case query.Nothing:
+		return ""
+	case query.Delete:
+		return "deleted()"
+	case []byte:
+		return string(t)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// debugMapping executes a mapping on an input document and records the state
+// of the mapping after each assignment statement. The mapping must have been
+// parsed with a coverage recorder in order to record which branches were taken
+// by each statement. Breakpoints are lines keyed by the path of the mapping
+// source they belong to, where the debugged mapping itself has an empty path.
+// Execution halts after a statement beginning at a breakpoint line of the
+// debugged mapping, or after a statement during which a breakpoint line of an
+// imported mapping was executed.
+func debugMapping(exec *mapping.Executor, cov *mapping.Coverage, breakpoints map[string][]int, input []byte) (res debugResult, err error) {
+	msg := message.QuickBatch([][]byte{input})
+	part := msg.Get(0)
+
+	var valuePtr *interface{}
+	var parseErr error
+	if jObj, jErr := part.JSON(); jErr == nil {
+		valuePtr = &jObj
+		res.This = debugValue(jObj)
+	} else {
+		parseErr = fmt.Errorf("parse as json: %w", jErr)
+	}
+
+	// Branches are recorded as coverage points of the mapping sources, and the
+	// branches taken by a statement are those with hits added during it.
+	hits := map[*mapping.CoveragePoint]int64{}
+
+	type breakLine struct {
+		source string
+		line   int
+	}
+	breakLines := map[breakLine]struct{}{}
+	for source, lines := range breakpoints {
+		for _, l := range lines {
+			breakLines[breakLine{source, l}] = struct{}{}
+		}
+	}
+
+	vars := map[string]interface{}{}
+	var result interface{} = query.Nothing(nil)
+
+	err = exec.ExecOntoSteps(query.FunctionContext{
+		Maps:     exec.Maps(),
+		Vars:     vars,
+		MsgBatch: msg,
+		NewMeta:  part,
+		NewValue: &result,
+	}.WithValueFunc(func() *interface{} { return valuePtr }), mapping.AssignmentContext{
+		Vars:  vars,
+		Meta:  part,
+		Value: &result,
+	}, func(s mapping.Step) bool {
+		_, skipped := s.Value.(query.Nothing)
+		step := debugStep{
+			Line:    s.Line,
+			Column:  s.Column,
+			Target:  s.Target.String(),
+			Value:   debugValue(s.Value),
+			Skipped: skipped,
+			Root:    debugValue(result),
+			Vars:    make(map[string]string, len(vars)),
+			Meta:    map[string]string{},
+		}
+		for k, v := range vars {
+			step.Vars[k] = debugValue(v)
+		}
+		_ = part.MetaIterMut(func(k string, v interface{}) error {
+			step.Meta[k] = debugValue(v)
+			return nil
+		})
+		_, step.Breakpoint = breakLines[breakLine{"", s.Line}]
+		for _, source := range cov.Sources() {
+			name := source.Name()
+			for _, p := range source.Points() {
+				if name == "" && !p.Kind.IsBranch() {
+					continue
+				}
+				h := p.Hits()
+				if h <= hits[p] {
+					continue
+				}
+				hits[p] = h
+				step.Branches = append(step.Branches, debugBranch{
+					Source: name,
+					Kind:   string(p.Kind),
+					Line:   p.Line,
+					Column: p.Column,
+				})
+				if name != "" {
+					if _, exists := breakLines[breakLine{name, p.Line}]; exists {
+						step.Breakpoint = true
+					}
+				}
+			}
+		}
+		res.Steps = append(res.Steps, step)
+		return !step.Breakpoint
+	})
+	if err != nil {
+		var ctxErr query.ErrNoContext
+		if parseErr != nil && errors.As(err, &ctxErr) {
+			if ctxErr.FieldName != "" {
+				err = fmt.Errorf("unable to reference message as structured (with 'this.%v'): %w", ctxErr.FieldName, parseErr)
+			} else {
+				err = fmt.Errorf("unable to reference message as structured (with 'this'): %w", parseErr)
+			}
+		}
+	}
+	return res, err
+}
@@ -0,0 +1,198 @@
+package blobl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+)
+
+func TestDebugMapping(t *testing.T) {
+	mappingStr := `let x = this.n * 2
+meta foo = "bar"
+root.a = if $x > 5 { "big" } else { "small" }
+root.b = if $x > 20 { "huge" }
+root.c = if $x > 20 { "huge" } else { "small" }`
+
+	steps := []debugStep{
+		{
+			Line: 1, Column: 1, Target: "$x", Value: "10",
+			Vars: map[string]string{"x": "10"},
+			Meta: map[string]string{},
+		},
+		{
+			Line: 2, Column: 1, Target: "meta foo", Value: `"bar"`,
+			Vars: map[string]string{"x": "10"},
+			Meta: map[string]string{"foo": `"bar"`},
+		},
+		{
+			Line: 3, Column: 1, Target: "root.a", Value: `"big"`,
+			Root:     `{"a":"big"}`,
+			Vars:     map[string]string{"x": "10"},
+			Meta:     map[string]string{"foo": `"bar"`},
+			Branches: []debugBranch{{Kind: "if", Line: 3, Column: 10}},
+		},
+		{
+			Line: 4, Column: 1, Target: "root.b", Skipped: true,
+			Root: `{"a":"big"}`,
+			Vars: map[string]string{"x": "10"},
+			Meta: map[string]string{"foo": `"bar"`},
+		},
+		{
+			Line: 5, Column: 1, Target: "root.c", Value: `"small"`,
+			Root:     `{"a":"big","c":"small"}`,
+			Vars:     map[string]string{"x": "10"},
+			Meta:     map[string]string{"foo": `"bar"`},
+			Branches: []debugBranch{{Kind: "else", Line: 5, Column: 32}},
+		},
+	}
+
+	imports := map[string]string{
+		"size.blobl": `map size {
+  root = if this > 20 {
+    "huge"
+  } else {
+    "small"
+  }
+}`,
+	}
+
+	importMappingStr := `import "size.blobl"
+root.a = this.n.apply("size")
+root.b = (this.n * 10).apply("size")`
+
+	importSteps := []debugStep{
+		{
+			Line: 2, Column: 1, Target: "root.a", Value: `"small"`,
+			Root: `{"a":"small"}`,
+			Vars: map[string]string{},
+			Meta: map[string]string{},
+			Branches: []debugBranch{
+				{Source: "size.blobl", Kind: "statement", Line: 2, Column: 3},
+				{Source: "size.blobl", Kind: "else", Line: 4, Column: 5},
+			},
+		},
+		{
+			Line: 3, Column: 1, Target: "root.b", Value: `"huge"`,
+			Root: `{"a":"small","b":"huge"}`,
+			Vars: map[string]string{},
+			Meta: map[string]string{},
+			Branches: []debugBranch{
+				{Source: "size.blobl", Kind: "statement", Line: 2, Column: 3},
+				{Source: "size.blobl", Kind: "if", Line: 2, Column: 10},
+			},
+		},
+	}
+
+	withBreakpoint := func(s debugStep) debugStep {
+		s.Breakpoint = true
+		return s
+	}
+
+	tests := []struct {
+		name        string
+		mapping     string
+		breakpoints map[string][]int
+		input       string
+		output      debugResult
+		errContains string
+	}{
+		{
+			name:    "all steps",
+			mapping: mappingStr,
+			input:   `{"n":5}`,
+			output: debugResult{
+				This:  `{"n":5}`,
+				Steps: steps,
+			},
+		},
+		{
+			name:        "halts at breakpoint",
+			mapping:     mappingStr,
+			breakpoints: map[string][]int{"": {3, 5}},
+			input:       `{"n":5}`,
+			output: debugResult{
+				This:  `{"n":5}`,
+				Steps: []debugStep{steps[0], steps[1], withBreakpoint(steps[2])},
+			},
+		},
+		{
+			name:        "breakpoint without statement",
+			mapping:     mappingStr,
+			breakpoints: map[string][]int{"": {10}},
+			input:       `{"n":5}`,
+			output: debugResult{
+				This:  `{"n":5}`,
+				Steps: steps,
+			},
+		},
+		{
+			name:    "imported branches",
+			mapping: importMappingStr,
+			input:   `{"n":5}`,
+			output: debugResult{
+				This:  `{"n":5}`,
+				Steps: importSteps,
+			},
+		},
+		{
+			name:        "halts at imported breakpoint",
+			mapping:     importMappingStr,
+			breakpoints: map[string][]int{"size.blobl": {2}},
+			input:       `{"n":5}`,
+			output: debugResult{
+				This:  `{"n":5}`,
+				Steps: []debugStep{withBreakpoint(importSteps[0])},
+			},
+		},
+		{
+			name:        "halts at imported branch breakpoint",
+			mapping:     importMappingStr,
+			breakpoints: map[string][]int{"size.blobl": {4}},
+			input:       `{"n":5}`,
+			output: debugResult{
+				This:  `{"n":5}`,
+				Steps: []debugStep{withBreakpoint(importSteps[0])},
+			},
+		},
+		{
+			name:        "imported breakpoint not executed",
+			mapping:     importMappingStr,
+			breakpoints: map[string][]int{"size.blobl": {3}},
+			input:       `{"n":5}`,
+			output: debugResult{
+				This:  `{"n":5}`,
+				Steps: importSteps,
+			},
+		},
+		{
+			name:        "input not structured",
+			mapping:     `root = this.n`,
+			input:       `not json`,
+			errContains: "unable to reference message as structured (with 'this.n')",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cov := mapping.NewCoverage()
+			exec, err := bloblang.GlobalEnvironment().WithCustomImporter(func(name string) ([]byte, error) {
+				return []byte(imports[name]), nil
+			}).WithCoverage(cov).NewMapping(test.mapping)
+			require.NoError(t, err)
+
+			res, err := debugMapping(exec, cov, test.breakpoints, []byte(test.input))
+			if test.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.output, res)
+		})
+	}
+}
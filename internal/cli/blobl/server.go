@@ -20,6 +20,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/parser"
 
 	_ "embed"
@@ -138,10 +139,25 @@ func (f *fileSync) mapping() string {
 	return f.mappingString
 }
 
+// lintMapping returns the problems found by a static analysis of a mapping
+// parsed from an environment, formatted for displaying within the editor.
+func lintMapping(env *bloblang.Environment, m string) (lints []string) {
+	mLints, err := env.LintMapping(m)
+	if err != nil {
+		return nil
+	}
+	for _, l := range mLints {
+		lints = append(lints, fmt.Sprintf("line %v char %v: %v", l.Line, l.Column, l.What))
+	}
+	return
+}
+
 func runServer(c *cli.Context) error {
 	fSync := newFileSync(c.String("input-file"), c.String("mapping-file"), c.Bool("write"))
 	defer fSync.write()
 
+	bEnv := bloblang.NewEnvironment().WithImporterRelativeToFile(c.String("mapping-file"))
+
 	mux := http.NewServeMux()
 	execCache := newExecCache()
 
@@ -159,9 +175,10 @@ func runServer(c *cli.Context) error {
 		fSync.update(req.Input, req.Mapping)
 
 		res := struct {
-			ParseError   string `json:"parse_error"`
-			MappingError string `json:"mapping_error"`
-			Result       string `json:"result"`
+			ParseError   string   `json:"parse_error"`
+			MappingError string   `json:"mapping_error"`
+			Result       string   `json:"result"`
+			Lints        []string `json:"lints"`
 		}{}
 		defer func() {
 			resBytes, err := json.Marshal(res)
@@ -172,7 +189,7 @@ func runServer(c *cli.Context) error {
 			_, _ = w.Write(resBytes)
 		}()
 
-		exec, err := bloblang.GlobalEnvironment().NewMapping(req.Mapping)
+		exec, err := bEnv.NewMapping(req.Mapping)
 		if err != nil {
 			if perr, ok := err.(*parser.Error); ok {
 				res.ParseError = fmt.Sprintf("failed to parse mapping: %v\n", perr.ErrorAtPositionStructured("", []rune(req.Mapping)))
@@ -182,6 +199,8 @@ func runServer(c *cli.Context) error {
 			return
 		}
 
+		res.Lints = lintMapping(bEnv, req.Mapping)
+
 		output, err := execCache.executeMapping(exec, false, true, []byte(req.Input))
 		if err != nil {
 			res.MappingError = err.Error()
@@ -190,6 +209,59 @@ func runServer(c *cli.Context) error {
 		}
 	})
 
+	mux.HandleFunc("/debug", func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Mapping     string `json:"mapping"`
+			Input       string `json:"input"`
+			Breakpoints []int  `json:"breakpoints"`
+
+			ImportBreakpoints map[string][]int `json:"import_breakpoints"`
+		}{}
+		dec := json.NewDecoder(r.Body)
+		if err := dec.Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		res := struct {
+			ParseError   string   `json:"parse_error"`
+			MappingError string   `json:"mapping_error"`
+			Lints        []string `json:"lints"`
+			debugResult
+		}{}
+		defer func() {
+			resBytes, err := json.Marshal(res)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write(resBytes)
+		}()
+
+		cov := mapping.NewCoverage()
+		exec, err := bEnv.WithCoverage(cov).NewMapping(req.Mapping)
+		if err != nil {
+			if perr, ok := err.(*parser.Error); ok {
+				res.ParseError = fmt.Sprintf("failed to parse mapping: %v\n", perr.ErrorAtPositionStructured("", []rune(req.Mapping)))
+			} else {
+				res.ParseError = err.Error()
+			}
+			return
+		}
+
+		res.Lints = lintMapping(bEnv, req.Mapping)
+
+		breakpoints := map[string][]int{"": req.Breakpoints}
+		for source, lines := range req.ImportBreakpoints {
+			if source != "" {
+				breakpoints[source] = lines
+			}
+		}
+		if res.debugResult, err = debugMapping(exec, cov, breakpoints, []byte(req.Input)); err != nil {
+			res.MappingError = err.Error()
+		}
+	})
+
 	indexTemplate := template.Must(template.New("index").Parse(bloblangEditorPage))
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -27,9 +27,9 @@ type pathLint struct {
 	err    string
 }
 
-func lintFile(path string, rejectDeprecated bool) (pathLints []pathLint) {
+func lintFile(path string, lintCtx docs.LintContext) (pathLints []pathLint) {
 	conf := config.New()
-	lints, err := config.ReadFileLinted(path, rejectDeprecated, &conf)
+	lints, err := config.ReadFileLinted(path, lintCtx, &conf)
 	if err != nil {
 		pathLints = append(pathLints, pathLint{
 			source: path,
@@ -46,7 +46,7 @@ func lintFile(path string, rejectDeprecated bool) (pathLints []pathLint) {
 	return
 }
 
-func lintMDSnippets(path string, rejectDeprecated bool) (pathLints []pathLint) {
+func lintMDSnippets(path string, lintCtx docs.LintContext) (pathLints []pathLint) {
 	rawBytes, err := os.ReadFile(path)
 	if err != nil {
 		pathLints = append(pathLints, pathLint{
@@ -85,8 +85,6 @@ func lintMDSnippets(path string, rejectDeprecated bool) (pathLints []pathLint) {
 				err:    err.Error(),
 			})
 		} else {
-			lintCtx := docs.NewLintContext()
-			lintCtx.RejectDeprecated = rejectDeprecated
 			lints, err := config.LintBytes(lintCtx, configBytes)
 			if err != nil {
 				pathLints = append(pathLints, pathLint{
@@ -131,6 +129,11 @@ files with the .yaml or .yml extension.`[1:],
 				Value: false,
 				Usage: "Print linting errors for the presence of deprecated fields.",
 			},
+			&cli.BoolFlag{
+				Name:  "bloblang-analysis",
+				Value: false,
+				Usage: "Print linting errors for problems found by a static analysis of Bloblang mappings, such as methods called on values of the wrong type and unused variables.",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			targets, err := ifilepath.GlobsAndSuperPaths(c.Args().Slice(), "yaml", "yml")
@@ -142,7 +145,9 @@ files with the .yaml or .yml extension.`[1:],
 				targets = append(targets, conf)
 			}
 
-			rejectDeprecated := c.Bool("deprecated")
+			lintCtx := docs.NewLintContext()
+			lintCtx.RejectDeprecated = c.Bool("deprecated")
+			lintCtx.BloblangAnalysis = c.Bool("bloblang-analysis")
 
 			var pathLintMut sync.Mutex
 			var pathLints []pathLint
@@ -161,9 +166,9 @@ files with the .yaml or .yml extension.`[1:],
 						}
 						var lints []pathLint
 						if path.Ext(target) == ".md" {
-							lints = lintMDSnippets(target, rejectDeprecated)
+							lints = lintMDSnippets(target, lintCtx)
 						} else {
-							lints = lintFile(target, rejectDeprecated)
+							lints = lintFile(target, lintCtx)
 						}
 						if len(lints) > 0 {
 							pathLintMut.Lock()
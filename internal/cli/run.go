@@ -237,6 +237,7 @@ Either run Benthos as a stream processor or choose a command:
 				false,
 				false,
 				nil,
+				streamsStoreOpts{},
 			))
 			return nil
 		},
@@ -303,6 +304,16 @@ https://benthos.dev/docs/guides/streams_mode/about`[1:],
 						Value: false,
 						Usage: "Disable the HTTP API for streams mode",
 					},
+					&cli.StringFlag{
+						Name:  "store-dir",
+						Value: "",
+						Usage: "Persist streams and resources created via the HTTP API within a directory, and load them on start up",
+					},
+					&cli.StringFlag{
+						Name:  "store-cache",
+						Value: "",
+						Usage: "Persist streams and resources created via the HTTP API within a cache resource, and load them on start up",
+					},
 				},
 				Action: func(c *cli.Context) error {
 					os.Exit(cmdService(
@@ -315,6 +326,10 @@ https://benthos.dev/docs/guides/streams_mode/about`[1:],
 						!c.Bool("no-api"),
 						true,
 						c.Args().Slice(),
+						streamsStoreOpts{
+							dir:   c.String("store-dir"),
+							cache: c.String("store-cache"),
+						},
 					))
 					return nil
 				},
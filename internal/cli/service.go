@@ -64,14 +64,48 @@ func readConfig(path string, streamsMode bool, resourcesPaths, streamsPaths, ove
 
 //------------------------------------------------------------------------------
 
+// streamsStoreOpts describes where streams and resources created via the
+// streams mode API are persisted, if anywhere.
+type streamsStoreOpts struct {
+	dir   string
+	cache string
+}
+
+func (s streamsStoreOpts) newStore(mgr *manager.Type) (strmmgr.Store, error) {
+	if s.dir != "" && s.cache != "" {
+		return nil, errors.New("a store directory and cache cannot both be specified")
+	}
+	if s.dir != "" {
+		return strmmgr.NewDirectoryStore(s.dir), nil
+	}
+	if s.cache != "" {
+		if !mgr.ProbeCache(s.cache) {
+			return nil, fmt.Errorf("cache resource '%v' was not found", s.cache)
+		}
+		return strmmgr.NewCacheStore(mgr, s.cache, "benthos_streams_"), nil
+	}
+	return nil, nil
+}
+
 func initStreamsMode(
 	strict, watching, enableAPI bool,
+	storeOpts streamsStoreOpts,
 	confReader *config.Reader,
 	manager *manager.Type,
 	logger log.Modular,
 	stats *metrics.Namespaced,
 ) stoppable {
-	streamMgr := strmmgr.New(manager, strmmgr.OptAPIEnabled(enableAPI))
+	mgrOpts := []func(*strmmgr.Type){strmmgr.OptAPIEnabled(enableAPI)}
+
+	store, err := storeOpts.newStore(manager)
+	if err != nil {
+		logger.Errorf("Failed to create streams store: %v\n", err)
+		os.Exit(1)
+	}
+	if store != nil {
+		mgrOpts = append(mgrOpts, strmmgr.OptSetStore(store))
+	}
+	streamMgr := strmmgr.New(manager, mgrOpts...)
 
 	streamConfs := map[string]stream.Config{}
 	lints, err := confReader.ReadStreams(streamConfs)
@@ -96,6 +130,10 @@ func initStreamsMode(
 			os.Exit(1)
 		}
 	}
+	if err := streamMgr.LoadFromStore(context.Background(), time.Second*30); err != nil {
+		logger.Errorf("Failed to load persisted streams: %v\n", err)
+		os.Exit(1)
+	}
 	logger.Infoln("Launching benthos in streams mode, use CTRL+C to close.")
 
 	if err := confReader.SubscribeStreamChanges(func(id string, newStreamConf stream.Config) bool {
@@ -113,6 +151,18 @@ func initStreamsMode(
 		os.Exit(1)
 	}
 
+	if err := confReader.SubscribeStreamRemovals(func(id string) bool {
+		if err := streamMgr.Delete(id, time.Second*30); err != nil && !errors.Is(err, strmmgr.ErrStreamDoesNotExist) {
+			logger.Errorf("Failed to remove stream %v: %v", id, err)
+			return false
+		}
+		logger.Infof("Removed stream %v as its config file was deleted.", id)
+		return true
+	}); err != nil {
+		logger.Errorf("Failed to create stream config watcher: %v", err)
+		os.Exit(1)
+	}
+
 	if watching {
 		if err := confReader.BeginFileWatching(manager, strict); err != nil {
 			logger.Errorf("Failed to create stream config watcher: %v", err)
@@ -227,6 +277,7 @@ func cmdService(
 	strict, watching, enableStreamsAPI bool,
 	streamsMode bool,
 	streamsPaths []string,
+	storeOpts streamsStoreOpts,
 ) int {
 	confReader := readConfig(confPath, streamsMode, resourcesPaths, streamsPaths, confOverrides)
 	conf := config.New()
@@ -317,7 +368,7 @@ func cmdService(
 
 	// Create data streams.
 	if streamsMode {
-		stoppableStream = initStreamsMode(strict, watching, enableStreamsAPI, confReader, manager, logger, stats)
+		stoppableStream = initStreamsMode(strict, watching, enableStreamsAPI, storeOpts, confReader, manager, logger, stats)
 	} else {
 		stoppableStream, dataStreamClosedChan = initNormalMode(conf, strict, watching, confReader, manager, logger, stats)
 	}
@@ -2,8 +2,12 @@ package test
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	yaml "gopkg.in/yaml.v3"
 
@@ -64,13 +68,19 @@ func (i *InputPart) UnmarshalYAML(value *yaml.Node) error {
 
 // Case contains a definition of a single Benthos config test case.
 type Case struct {
-	Name             string               `yaml:"name"`
-	Environment      map[string]string    `yaml:"environment"`
-	TargetProcessors string               `yaml:"target_processors"`
-	TargetMapping    string               `yaml:"target_mapping"`
-	Mocks            map[string]yaml.Node `yaml:"mocks"`
-	InputBatch       []InputPart          `yaml:"input_batch"`
-	OutputBatches    [][]ConditionsMap    `yaml:"output_batches"`
+	Name             string                       `yaml:"name"`
+	Environment      map[string]string            `yaml:"environment"`
+	TargetProcessors string                       `yaml:"target_processors"`
+	TargetMapping    string                       `yaml:"target_mapping"`
+	TargetStream     bool                         `yaml:"target_stream"`
+	Mocks            map[string]yaml.Node         `yaml:"mocks"`
+	MockCaches       map[string]map[string]string `yaml:"mock_caches"`
+	MockRateLimits   []string                     `yaml:"mock_rate_limits"`
+	MockResponses    map[string]InputPart         `yaml:"mock_responses"`
+	InputBatch       []InputPart                  `yaml:"input_batch"`
+	InputBatches     [][]InputPart                `yaml:"input_batches"`
+	OutputBatches    [][]ConditionsMap            `yaml:"output_batches"`
+	Outputs          map[string][][]ConditionsMap `yaml:"outputs"`
 
 	line int
 }
@@ -88,9 +98,15 @@ func NewCase() Case {
 		Environment:      map[string]string{},
 		TargetProcessors: "/pipeline/processors",
 		TargetMapping:    "",
+		TargetStream:     false,
 		Mocks:            map[string]yaml.Node{},
+		MockCaches:       map[string]map[string]string{},
+		MockRateLimits:   []string{},
+		MockResponses:    map[string]InputPart{},
 		InputBatch:       []InputPart{},
+		InputBatches:     [][]InputPart{},
 		OutputBatches:    [][]ConditionsMap{},
+		Outputs:          map[string][][]ConditionsMap{},
 	}
 }
 
@@ -123,38 +139,94 @@ func (c CaseFailure) String() string {
 }
 
 // ProcProvider returns compiled processors extracted from a Benthos config
-// using a JSON Pointer.
+// using a JSON Pointer, or the stream of a Benthos config.
 type ProcProvider interface {
 	Provide(jsonPtr string, environment map[string]string, mocks map[string]yaml.Node) ([]iprocessor.V1, error)
 	ProvideBloblang(path string) ([]iprocessor.V1, error)
+	ProvideStream(environment map[string]string, mocks map[string]yaml.Node, outputLabels []string) (*StreamTarget, error)
 }
 
-func (c *Case) executeFrom(dir string, provider ProcProvider) (failures []CaseFailure, err error) {
-	var procSet []iprocessor.V1
-	if c.TargetMapping != "" {
-		if procSet, err = provider.ProvideBloblang(c.TargetMapping); err != nil {
-			return nil, fmt.Errorf("failed to initialise Bloblang mapping '%v': %v", c.TargetMapping, err)
+// allMocks returns the mocks of a test case combined with the mocked caches,
+// rate limits and canned responses converted into component configs.
+func (c *Case) allMocks(dir string) (map[string]yaml.Node, error) {
+	mocks := make(map[string]yaml.Node, len(c.Mocks))
+	for k, v := range c.Mocks {
+		mocks[k] = v
+	}
+
+	addMock := func(label string, conf interface{}) error {
+		if _, exists := mocks[label]; exists {
+			return fmt.Errorf("mock '%v' is defined more than once", label)
 		}
-	} else {
-		if procSet, err = provider.Provide(c.TargetProcessors, c.Environment, c.Mocks); err != nil {
-			return nil, fmt.Errorf("failed to initialise processors '%v': %v", c.TargetProcessors, err)
+		var node yaml.Node
+		if err := node.Encode(conf); err != nil {
+			return fmt.Errorf("failed to create mock '%v': %w", label, err)
 		}
+		mocks[label] = node
+		return nil
 	}
 
-	reportFailure := func(reason string) {
-		failures = append(failures, CaseFailure{
-			Name:     c.Name,
-			TestLine: c.line,
-			Reason:   reason,
-		})
+	for label, values := range c.MockCaches {
+		if values == nil {
+			values = map[string]string{}
+		}
+		if err := addMock(label, map[string]interface{}{
+			"memory": map[string]interface{}{
+				"init_values": values,
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, label := range c.MockRateLimits {
+		if err := addMock(label, map[string]interface{}{
+			"local": map[string]interface{}{
+				"count":    math.MaxInt32,
+				"interval": "1s",
+			},
+		}); err != nil {
+			return nil, err
+		}
 	}
 
-	parts := make([]*message.Part, len(c.InputBatch))
-	for i, v := range c.InputBatch {
-		var content string
-		if content, err = v.getContent(dir); err != nil {
-			err = fmt.Errorf("failed to create mock input %v: %w", i, err)
-			return
+	for label, res := range c.MockResponses {
+		content, err := res.getContent(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mock response '%v': %w", label, err)
+		}
+		if err := addMock(label, map[string]interface{}{
+			"bloblang": cannedResponseMapping(content, res.Metadata),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return mocks, nil
+}
+
+// cannedResponseMapping returns a Bloblang mapping that replaces the contents
+// and sets the metadata of messages with a canned response.
+func cannedResponseMapping(content string, metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var mapping strings.Builder
+	fmt.Fprintf(&mapping, "root = %v\n", strconv.Quote(content))
+	for _, k := range keys {
+		fmt.Fprintf(&mapping, "meta %v = %v\n", strconv.Quote(k), strconv.Quote(metadata[k]))
+	}
+	return mapping.String()
+}
+
+func inputPartsToBatch(dir string, inputParts []InputPart) (*message.Batch, error) {
+	parts := make([]*message.Part, len(inputParts))
+	for i, v := range inputParts {
+		content, err := v.getContent(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create mock input %v: %w", i, err)
 		}
 		part := message.NewPart([]byte(content))
 		for k, v := range v.Metadata {
@@ -165,38 +237,135 @@ func (c *Case) executeFrom(dir string, provider ProcProvider) (failures []CaseFa
 
 	inputMsg := message.QuickBatch(nil)
 	inputMsg.SetAll(parts)
-	outputBatches, result := processor.ExecuteAll(procSet, inputMsg)
-	if result != nil {
-		reportFailure(fmt.Sprintf("processors resulted in error: %v", result))
-	}
+	return inputMsg, nil
+}
 
-	if lExp, lAct := len(c.OutputBatches), len(outputBatches); lAct < lExp {
-		reportFailure(fmt.Sprintf("wrong batch count, expected %v, got %v", lExp, lAct))
+// checkOutputBatches compares output batches against the expected conditions
+// and returns the reasons for any mismatches. When updateSnapshots is true the
+// snapshot conditions are rewritten with the output batches.
+func checkOutputBatches(dir string, updateSnapshots bool, expected [][]ConditionsMap, outputBatches []*message.Batch) (reasons []string) {
+	if lExp, lAct := len(expected), len(outputBatches); lAct < lExp {
+		reasons = append(reasons, fmt.Sprintf("wrong batch count, expected %v, got %v", lExp, lAct))
 	}
 
 	for i, v := range outputBatches {
-		if len(c.OutputBatches) <= i {
-			reportFailure(fmt.Sprintf("unexpected batch: %s", message.GetAllBytes(v)))
+		if len(expected) <= i {
+			reasons = append(reasons, fmt.Sprintf("unexpected batch: %s", message.GetAllBytes(v)))
 			continue
 		}
-		expectedBatch := c.OutputBatches[i]
+		expectedBatch := expected[i]
 		if lExp, lAct := len(expectedBatch), v.Len(); lExp != lAct {
-			reportFailure(fmt.Sprintf("mismatch of output batch %v message counts, expected %v, got %v", i, lExp, lAct))
+			reasons = append(reasons, fmt.Sprintf("mismatch of output batch %v message counts, expected %v, got %v", i, lExp, lAct))
 		}
 		_ = v.Iter(func(i2 int, part *message.Part) error {
 			if len(expectedBatch) <= i2 {
-				reportFailure(fmt.Sprintf("unexpected message from batch %v: %s", i, part.Get()))
+				reasons = append(reasons, fmt.Sprintf("unexpected message from batch %v: %s", i, part.Get()))
 				return nil
 			}
-			condErrs := expectedBatch[i2].CheckAll(dir, part)
+			condErrs := expectedBatch[i2].checkAll(dir, updateSnapshots, part)
 			for _, condErr := range condErrs {
-				reportFailure(fmt.Sprintf("batch %v message %v: %v", i, i2, condErr))
+				reasons = append(reasons, fmt.Sprintf("batch %v message %v: %v", i, i2, condErr))
 			}
 			if procErr := processor.GetFail(part); len(procErr) > 0 && len(condErrs) > 0 {
-				reportFailure(fmt.Sprintf("batch %v message %v: %v", i, i2, red(procErr)))
+				reasons = append(reasons, fmt.Sprintf("batch %v message %v: %v", i, i2, red(procErr)))
 			}
 			return nil
 		})
 	}
 	return
 }
+
+func (c *Case) executeFrom(dir string, provider ProcProvider, updateSnapshots bool) (failures []CaseFailure, err error) {
+	if c.TargetStream {
+		return c.executeStreamFrom(dir, provider, updateSnapshots)
+	}
+
+	var procSet []iprocessor.V1
+	if c.TargetMapping != "" {
+		if procSet, err = provider.ProvideBloblang(c.TargetMapping); err != nil {
+			return nil, fmt.Errorf("failed to initialise Bloblang mapping '%v': %v", c.TargetMapping, err)
+		}
+	} else {
+		var mocks map[string]yaml.Node
+		if mocks, err = c.allMocks(dir); err != nil {
+			return nil, err
+		}
+		if procSet, err = provider.Provide(c.TargetProcessors, c.Environment, mocks); err != nil {
+			return nil, fmt.Errorf("failed to initialise processors '%v': %v", c.TargetProcessors, err)
+		}
+	}
+
+	reportFailure := func(reason string) {
+		failures = append(failures, CaseFailure{
+			Name:     c.Name,
+			TestLine: c.line,
+			Reason:   reason,
+		})
+	}
+
+	inputMsg, err := inputPartsToBatch(dir, c.InputBatch)
+	if err != nil {
+		return nil, err
+	}
+	outputBatches, result := processor.ExecuteAll(procSet, inputMsg)
+	if result != nil {
+		reportFailure(fmt.Sprintf("processors resulted in error: %v", result))
+	}
+
+	for _, reason := range checkOutputBatches(dir, updateSnapshots, c.OutputBatches, outputBatches) {
+		reportFailure(reason)
+	}
+	return
+}
+
+func (c *Case) executeStreamFrom(dir string, provider ProcProvider, updateSnapshots bool) (failures []CaseFailure, err error) {
+	outputLabels := make([]string, 0, len(c.Outputs))
+	for label := range c.Outputs {
+		outputLabels = append(outputLabels, label)
+	}
+	sort.Strings(outputLabels)
+
+	var mocks map[string]yaml.Node
+	if mocks, err = c.allMocks(dir); err != nil {
+		return nil, err
+	}
+
+	var target *StreamTarget
+	if target, err = provider.ProvideStream(c.Environment, mocks, outputLabels); err != nil {
+		return nil, fmt.Errorf("failed to initialise stream: %v", err)
+	}
+
+	reportFailure := func(reason string) {
+		failures = append(failures, CaseFailure{
+			Name:     c.Name,
+			TestLine: c.line,
+			Reason:   reason,
+		})
+	}
+
+	inputBatches := make([]*message.Batch, len(c.InputBatches))
+	for i, parts := range c.InputBatches {
+		if inputBatches[i], err = inputPartsToBatch(dir, parts); err != nil {
+			return nil, fmt.Errorf("input batch %v: %w", i, err)
+		}
+	}
+
+	outputs, ackErrs, runErr := target.Run(inputBatches, streamTestTimeout)
+	if runErr != nil {
+		reportFailure(fmt.Sprintf("stream resulted in error: %v", runErr))
+		return
+	}
+
+	for i, ackErr := range ackErrs {
+		if ackErr != nil {
+			reportFailure(fmt.Sprintf("input batch %v was not delivered: %v", i, ackErr))
+		}
+	}
+
+	for _, label := range outputLabels {
+		for _, reason := range checkOutputBatches(dir, updateSnapshots, c.Outputs[label], outputs[label]) {
+			reportFailure(fmt.Sprintf("output '%v': %v", label, reason))
+		}
+	}
+	return
+}
@@ -35,6 +35,10 @@ func (m mockProvider) ProvideBloblang(name string) ([]iprocessor.V1, error) {
 	return nil, errors.New("mapping not found")
 }
 
+func (m mockProvider) ProvideStream(env map[string]string, mocks map[string]yaml.Node, outputLabels []string) (*StreamTarget, error) {
+	return nil, errors.New("streams not supported")
+}
+
 func TestCase(t *testing.T) {
 	color.NoColor = true
 
@@ -191,7 +195,7 @@ output_batches:
 			if err = yaml.Unmarshal([]byte(test.conf), &c); err != nil {
 				tt.Fatal(err)
 			}
-			fails, err := c.executeFrom("", provider)
+			fails, err := c.executeFrom("", provider, false)
 			if err != nil {
 				tt.Fatal(err)
 			}
@@ -234,7 +238,7 @@ output_batches:
   - content_equals: hello world FOO BAR BAZ
 `), &c))
 
-	fails, err := c.executeFrom(tmpDir, provider)
+	fails, err := c.executeFrom(tmpDir, provider, false)
 	require.NoError(t, err)
 
 	assert.Equal(t, []CaseFailure(nil), fails)
@@ -249,7 +253,7 @@ output_batches:
   - content_equals: hello world FOO BAR BAZ
 `), &c))
 
-	fails, err = c.executeFrom(tmpDir, provider)
+	fails, err = c.executeFrom(tmpDir, provider, false)
 	require.NoError(t, err)
 
 	assert.Equal(t, []CaseFailure{
@@ -293,7 +297,7 @@ output_batches:
   - file_equals: "./inner/uppercased.txt"
 `), &c))
 
-	fails, err := c.executeFrom(tmpDir, provider)
+	fails, err := c.executeFrom(tmpDir, provider, false)
 	require.NoError(t, err)
 
 	assert.Equal(t, []CaseFailure(nil), fails)
@@ -308,7 +312,7 @@ output_batches:
   - file_equals: "./not_uppercased.txt"
 `), &c))
 
-	fails, err = c.executeFrom(tmpDir, provider)
+	fails, err = c.executeFrom(tmpDir, provider, false)
 	require.NoError(t, err)
 
 	assert.Equal(t, []CaseFailure{
@@ -319,3 +323,34 @@ output_batches:
 		},
 	}, fails)
 }
+
+func TestCaseAllMocks(t *testing.T) {
+	c := NewCase()
+	c.MockCaches["foocache"] = map[string]string{"foo": "bar"}
+	c.MockRateLimits = []string{"foolimit"}
+	c.MockResponses["fooproc"] = InputPart{
+		Content:  "hello \"world\"",
+		Metadata: map[string]string{"b": "2", "a": "1"},
+	}
+
+	mocks, err := c.allMocks("")
+	require.NoError(t, err)
+	require.Len(t, mocks, 3)
+
+	cacheNode := mocks["foocache"]
+	var cacheConf map[string]map[string]map[string]string
+	require.NoError(t, cacheNode.Decode(&cacheConf))
+	assert.Equal(t, map[string]string{"foo": "bar"}, cacheConf["memory"]["init_values"])
+
+	procNode := mocks["fooproc"]
+	var procConf map[string]string
+	require.NoError(t, procNode.Decode(&procConf))
+	assert.Equal(t, `root = "hello \"world\""
+meta "a" = "1"
+meta "b" = "2"
+`, procConf["bloblang"])
+
+	c.Mocks["foolimit"] = yaml.Node{}
+	_, err = c.allMocks("")
+	require.EqualError(t, err, "mock 'foolimit' is defined more than once")
+}
@@ -22,6 +22,8 @@ fail the process will report the errors and exit with a status code 1.
   benthos test ./path/to/configs/...
   benthos test ./foo_configs/*.yaml ./bar_configs/*.yaml
   benthos test ./foo.yaml
+  benthos test --format junit ./path/to/configs/... > report.xml
+  benthos test --update-snapshots ./foo.yaml
 
 For more information check out the docs at:
 https://benthos.dev/docs/configuration/unit_testing`[1:],
@@ -29,7 +31,27 @@ https://benthos.dev/docs/configuration/unit_testing`[1:],
 			&cli.StringFlag{
 				Name:  "log",
 				Value: "",
-				Usage: "allow components to write logs at a provided level to stdout.",
+				Usage: "allow components to write logs at a provided level to stderr.",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: FormatDefault,
+				Usage: "the format of test results written to stdout, one of: default, junit, json, tap.",
+			},
+			&cli.BoolFlag{
+				Name:  "update-snapshots",
+				Value: false,
+				Usage: "rewrite the files of snapshot conditions with the output of tests rather than checking them.",
+			},
+			&cli.BoolFlag{
+				Name:  "coverage",
+				Value: false,
+				Usage: "report which statements and branches of Bloblang mappings were executed by the tests.",
+			},
+			&cli.StringFlag{
+				Name:  "coverage-lcov",
+				Value: "",
+				Usage: "write the coverage of Bloblang mappings in LCOV format to a file at the provided path.",
 			},
 		},
 		Action: func(c *cli.Context) error {
@@ -43,18 +65,26 @@ https://benthos.dev/docs/configuration/unit_testing`[1:],
 				fmt.Printf("Failed to resolve resource glob pattern: %v\n", err)
 				os.Exit(1)
 			}
+			opts := RunOptions{
+				Format:          c.String("format"),
+				UpdateSnapshots: c.Bool("update-snapshots"),
+				Coverage:        c.Bool("coverage"),
+				CoverageLCOV:    c.String("coverage-lcov"),
+			}
 			if logLevel := c.String("log"); len(logLevel) > 0 {
 				logConf := log.NewConfig()
 				logConf.LogLevel = logLevel
-				logger, err := log.NewV2(os.Stdout, logConf)
+				// Logs are written to stderr so that they do not corrupt
+				// results written to stdout in a machine readable format.
+				logger, err := log.NewV2(os.Stderr, logConf)
 				if err != nil {
-					fmt.Printf("Failed to init logger: %v\n", err)
+					fmt.Fprintf(os.Stderr, "Failed to init logger: %v\n", err)
 					os.Exit(1)
 				}
-				if RunAll(c.Args().Slice(), testSuffix, true, logger, resourcesPaths) {
+				if RunAll(c.Args().Slice(), testSuffix, true, logger, resourcesPaths, opts) {
 					os.Exit(0)
 				}
-			} else if RunAll(c.Args().Slice(), testSuffix, true, log.Noop(), resourcesPaths) {
+			} else if RunAll(c.Args().Slice(), testSuffix, true, log.Noop(), resourcesPaths, opts) {
 				os.Exit(0)
 			}
 			os.Exit(1)
@@ -2,15 +2,19 @@ package test
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	yaml "gopkg.in/yaml.v3"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	ifilepath "github.com/benthosdev/benthos/v4/internal/filepath"
 	"github.com/benthosdev/benthos/v4/internal/log"
 )
@@ -90,7 +94,7 @@ func GetTestTargets(targetPaths []string, testSuffix string) (map[string]Definit
 func lintTarget(path, testSuffix string) ([]string, error) {
 	confPath, _ := GetPathPair(path, testSuffix)
 	dummyConf := config.New()
-	lints, err := config.ReadFileLinted(confPath, false, &dummyConf)
+	lints, err := config.ReadFileLinted(confPath, docs.NewLintContext(), &dummyConf)
 	if err != nil {
 		return nil, err
 	}
@@ -99,85 +103,113 @@ func lintTarget(path, testSuffix string) ([]string, error) {
 
 //------------------------------------------------------------------------------
 
+// RunOptions customises the execution of tests and the reporting of results.
+type RunOptions struct {
+	// Format is the format in which results are reported, defaults to a human
+	// readable format.
+	Format string
+
+	// Output is where results are written, defaults to stdout.
+	Output io.Writer
+
+	// UpdateSnapshots causes the files of snapshot conditions to be rewritten
+	// with the output of tests rather than checked.
+	UpdateSnapshots bool
+
+	// Coverage causes the coverage of Bloblang mappings to be reported after
+	// the test results.
+	Coverage bool
+
+	// CoverageLCOV is an optional path where the coverage of Bloblang mappings
+	// is written in LCOV format.
+	CoverageLCOV string
+}
+
 // RunAll executes the test command for a slice of paths. The path can either be
 // a config file, a config files test definition file, a directory, or the
-// wildcard pattern './...'.
-func RunAll(paths []string, testSuffix string, lint bool, logger log.Modular, resourcesPaths []string) bool {
+// wildcard pattern './...'. Results are written according to the provided
+// options, and false is returned if any tests failed or could not be executed.
+func RunAll(paths []string, testSuffix string, lint bool, logger log.Modular, resourcesPaths []string, opts RunOptions) bool {
+	format := opts.Format
+	if format == "" {
+		format = FormatDefault
+	}
+	w := opts.Output
+	if w == nil {
+		w = os.Stdout
+	}
+
+	rep, err := newReporter(format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create test reporter: %v\n", err)
+		return false
+	}
+
 	targets, err := GetTestTargets(paths, testSuffix)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to obtain test targets: %v\n", err)
 		return false
 	}
 	if len(targets) == 0 {
-		fmt.Printf("%v\n", yellow("No tests were found"))
+		if format == FormatDefault {
+			fmt.Fprintf(w, "%v\n", yellow("No tests were found"))
+		} else {
+			fmt.Fprintln(os.Stderr, "No tests were found")
+		}
 		return false
 	}
 
-	type failedTarget struct {
-		target string
-		lints  []string
-		cases  []CaseFailure
-	}
-	fails := []failedTarget{}
-
 	targetPaths := make([]string, 0, len(targets))
 	for k := range targets {
 		targetPaths = append(targetPaths, k)
 	}
 	sort.Strings(targetPaths)
 
+	passed := true
+	results := make([]TargetResult, 0, len(targetPaths))
 	for _, target := range targetPaths {
-		var lints []string
-		var failCases []CaseFailure
+		res := TargetResult{Path: target}
+		execOpts := ExecuteOptions{UpdateSnapshots: opts.UpdateSnapshots}
+		if opts.Coverage || opts.CoverageLCOV != "" {
+			res.Coverage = mapping.NewCoverage()
+			execOpts.Coverage = res.Coverage
+		}
+		start := time.Now()
 		if lint {
-			if lints, err = lintTarget(target, testSuffix); err != nil {
+			if res.Lints, err = lintTarget(target, testSuffix); err != nil {
 				fmt.Fprintf(os.Stderr, "Failed to execute test target '%v': %v\n", target, err)
 				return false
 			}
 		}
-		if failCases, err = targets[target].Execute(target, resourcesPaths, logger); err != nil {
+		if res.Cases, err = targets[target].ExecuteCases(target, resourcesPaths, logger, execOpts); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to execute test target '%v': %v\n", target, err)
 			return false
 		}
-		if len(lints) > 0 || len(failCases) > 0 {
-			fails = append(fails, failedTarget{
-				target: target,
-				lints:  lints,
-				cases:  failCases,
-			})
-			fmt.Printf("Test '%v' %v\n", target, red("failed"))
-		} else {
-			fmt.Printf("Test '%v' %v\n", target, green("succeeded"))
+		res.Duration = time.Since(start)
+		if res.Failed() {
+			passed = false
 		}
+		rep.targetDone(w, res)
+		results = append(results, res)
 	}
-	if len(fails) > 0 {
-		fmt.Printf("\nFailures:\n\n")
-		for i, fail := range fails {
-			if i > 0 {
-				fmt.Println("")
-			}
-			fmt.Printf("--- %v ---\n\n", fail.target)
-			for _, lint := range fail.lints {
-				fmt.Printf("Lint: %v\n", lint)
-			}
-			if len(fail.cases) > 0 {
-				if len(fail.lints) > 0 {
-					fmt.Println("")
-				}
-				var namePrev string
-				for i, fail := range fail.cases {
-					if namePrev != fail.Name {
-						if i > 0 {
-							fmt.Println("")
-						}
-						fmt.Printf("%v [line %v]:\n", fail.Name, fail.TestLine)
-						namePrev = fail.Name
-					}
-					fmt.Println(fail.Reason)
-				}
-			}
-		}
+	if err := rep.done(w, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write test results: %v\n", err)
 		return false
 	}
-	return true
+
+	if opts.Coverage {
+		// Keep structured reports parsable by writing coverage elsewhere.
+		covW := w
+		if format != FormatDefault {
+			covW = os.Stderr
+		}
+		writeCoverageSummary(covW, results)
+	}
+	if opts.CoverageLCOV != "" {
+		if err := writeCoverageLCOVFile(opts.CoverageLCOV, results); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write coverage report: %v\n", err)
+			return false
+		}
+	}
+	return passed
 }
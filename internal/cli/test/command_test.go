@@ -244,15 +244,53 @@ tests:
 	}
 	defer os.RemoveAll(testDir)
 
-	if !test.RunAll([]string{filepath.Join(testDir, "foo.yaml")}, "_benthos_test", false, log.Noop(), nil) {
+	if !test.RunAll([]string{filepath.Join(testDir, "foo.yaml")}, "_benthos_test", false, log.Noop(), nil, test.RunOptions{}) {
 		t.Error("Unexpected result")
 	}
 
-	if test.RunAll([]string{filepath.Join(testDir, "foo.yaml")}, "_benthos_test", true, log.Noop(), nil) {
+	if test.RunAll([]string{filepath.Join(testDir, "foo.yaml")}, "_benthos_test", true, log.Noop(), nil, test.RunOptions{}) {
 		t.Error("Unexpected result")
 	}
 
-	if test.RunAll([]string{testDir}, "_benthos_test", true, log.Noop(), nil) {
+	if test.RunAll([]string{testDir}, "_benthos_test", true, log.Noop(), nil, test.RunOptions{}) {
+		t.Error("Unexpected result")
+	}
+}
+
+func TestCommandRunLintMockCaches(t *testing.T) {
+	testDir, err := initTestFiles(t, map[string]string{
+		"foo.yaml": `
+pipeline:
+  processors:
+  - cache:
+      resource: foocache
+      operator: get
+      key: '${! content() }'
+
+cache_resources:
+  - label: foocache
+    redis:
+      url: tcp://localhost:6379
+
+tests:
+  - name: mocked cache
+    target_processors: '/pipeline/processors'
+    mock_caches:
+      foocache:
+        foo: bar
+        baz: buz
+    input_batch:
+      - content: foo
+    output_batches:
+      -
+        - content_equals: bar`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+
+	if !test.RunAll([]string{filepath.Join(testDir, "foo.yaml")}, "_benthos_test", true, log.Noop(), nil, test.RunOptions{}) {
 		t.Error("Unexpected result")
 	}
 }
@@ -9,8 +9,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/nsf/jsondiff"
+	"github.com/pmezard/go-difflib/difflib"
 	yaml "gopkg.in/yaml.v3"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang"
@@ -74,6 +76,12 @@ func (c *ConditionsMap) UnmarshalYAML(value *yaml.Node) error {
 				return fmt.Errorf("line %v: %v", v.Line, err)
 			}
 			cond = val
+		case "snapshot":
+			val := SnapshotCondition("")
+			if err := v.Decode(&val); err != nil {
+				return fmt.Errorf("line %v: %v", v.Line, err)
+			}
+			cond = val
 		case "metadata_equals":
 			val := MetadataEqualsCondition{}
 			if err := v.Decode(&val); err != nil {
@@ -91,13 +99,26 @@ func (c *ConditionsMap) UnmarshalYAML(value *yaml.Node) error {
 // CheckAll checks all conditions against a message part. Conditions are
 // executed in alphabetical order.
 func (c ConditionsMap) CheckAll(dir string, part *message.Part) (errs []error) {
+	return c.checkAll(dir, false, part)
+}
+
+// checkAll checks all conditions against a message part, and when
+// updateSnapshots is true any snapshot conditions are rewritten rather than
+// checked.
+func (c ConditionsMap) checkAll(dir string, updateSnapshots bool, part *message.Part) (errs []error) {
 	condTypes := []string{}
 	for k := range c {
 		condTypes = append(condTypes, k)
 	}
 	sort.Strings(condTypes)
 	for _, k := range condTypes {
-		if relCheck, ok := c[k].(interface {
+		if snapCheck, ok := c[k].(interface {
+			checkSnapshot(string, bool, *message.Part) error
+		}); ok {
+			if err := snapCheck.checkSnapshot(dir, updateSnapshots, part); err != nil {
+				errs = append(errs, fmt.Errorf("%v: %v", k, err))
+			}
+		} else if relCheck, ok := c[k].(interface {
 			checkFrom(string, *message.Part) error
 		}); ok {
 			if err := relCheck.checkFrom(dir, part); err != nil {
@@ -235,6 +256,131 @@ func (c FileEqualsCondition) checkFrom(dir string, p *message.Part) error {
 
 //------------------------------------------------------------------------------
 
+// SnapshotCondition is a string condition that reads a snapshot file at the
+// string path and compares it against the contents and metadata of a message.
+// Snapshot files can be created and updated by running tests with the
+// --update-snapshots flag.
+type SnapshotCondition string
+
+// Check this condition against a message part.
+func (c SnapshotCondition) Check(p *message.Part) error {
+	return c.checkSnapshot("", false, p)
+}
+
+func (c SnapshotCondition) checkFrom(dir string, p *message.Part) error {
+	return c.checkSnapshot(dir, false, p)
+}
+
+func (c SnapshotCondition) checkSnapshot(dir string, update bool, p *message.Part) error {
+	relPath := filepath.Join(dir, string(c))
+
+	act, err := renderSnapshot(p)
+	if err != nil {
+		return fmt.Errorf("failed to render snapshot: %w", err)
+	}
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(relPath), 0o755); err != nil {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+		if err := os.WriteFile(relPath, act, 0o644); err != nil {
+			return fmt.Errorf("failed to write snapshot file: %w", err)
+		}
+		return nil
+	}
+
+	exp, err := os.ReadFile(relPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot file '%v' does not exist, run the tests with --update-snapshots in order to create it", string(c))
+		}
+		return fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+
+	if !bytes.Equal(exp, act) {
+		return fmt.Errorf("snapshot mismatch\n%v", lineDiff(string(exp), string(act)))
+	}
+	return nil
+}
+
+type snapshot struct {
+	Metadata map[string]interface{} `yaml:"metadata,omitempty"`
+	Content  string                 `yaml:"content"`
+}
+
+// renderSnapshot creates a YAML document of the metadata and contents of a
+// message. Metadata values are rendered in their original types so that changes
+// of type are caught, and contents that are valid JSON are indented so that
+// snapshots of structured documents can be reviewed line by line.
+func renderSnapshot(p *message.Part) ([]byte, error) {
+	snap := snapshot{
+		Content: string(p.Get()),
+	}
+	_ = p.MetaIterMut(func(k string, v interface{}) error {
+		if snap.Metadata == nil {
+			snap.Metadata = map[string]interface{}{}
+		}
+		snap.Metadata[k] = v
+		return nil
+	})
+	if json.Valid(p.Get()) {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, p.Get(), "", "  "); err == nil {
+			snap.Content = buf.String()
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(snap); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// maxDiffLines is the maximum number of differing lines printed by lineDiff,
+// which prevents large mismatches from flooding the output of a test.
+const maxDiffLines = 100
+
+// lineDiff returns the lines that differ between two strings, where removed
+// lines are prefixed with a minus and added lines are prefixed with a plus.
+func lineDiff(exp, act string) string {
+	expLines := strings.Split(exp, "\n")
+	actLines := strings.Split(act, "\n")
+
+	var diff []string
+	omitted := 0
+	add := func(line string) {
+		if len(diff) < maxDiffLines {
+			diff = append(diff, line)
+		} else {
+			omitted++
+		}
+	}
+
+	for _, op := range difflib.NewMatcher(expLines, actLines).GetOpCodes() {
+		if op.Tag == 'e' {
+			continue
+		}
+		for _, l := range expLines[op.I1:op.I2] {
+			add(blue("- " + l))
+		}
+		for _, l := range actLines[op.J1:op.J2] {
+			add(red("+ " + l))
+		}
+	}
+	if omitted > 0 {
+		diff = append(diff, fmt.Sprintf("... and %v more differing lines", omitted))
+	}
+	return strings.Join(diff, "\n")
+}
+
+//------------------------------------------------------------------------------
+
 // MetadataEqualsCondition checks whether a metadata keys contents matches a
 // value.
 type MetadataEqualsCondition map[string]string
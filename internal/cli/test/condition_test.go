@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/fatih/color"
@@ -476,3 +477,88 @@ func TestFileEqualsCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotCondition(t *testing.T) {
+	color.NoColor = true
+
+	tmpDir := t.TempDir()
+
+	part := message.NewPart([]byte(`{"foo":"bar","baz":[1,2]}`))
+	part.MetaSet("b", "2")
+	part.MetaSet("a", "1")
+	part.MetaSetMut("c", int64(3))
+
+	cond := SnapshotCondition("./snapshots/foo.yaml")
+
+	err := cond.checkFrom(tmpDir, part)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "run the tests with --update-snapshots")
+
+	require.NoError(t, cond.checkSnapshot(tmpDir, true, part))
+
+	snapBytes, err := os.ReadFile(filepath.Join(tmpDir, "snapshots", "foo.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, `metadata:
+  a: "1"
+  b: "2"
+  c: 3
+content: |-
+  {
+    "foo": "bar",
+    "baz": [
+      1,
+      2
+    ]
+  }
+`, string(snapBytes))
+
+	require.NoError(t, cond.checkFrom(tmpDir, part))
+
+	part.Set([]byte(`{"foo":"buz","baz":[1,2]}`))
+	err = cond.checkFrom(tmpDir, part)
+	require.Error(t, err)
+	assert.Equal(t, `snapshot mismatch
+-     "foo": "bar",
++     "foo": "buz",`, err.Error())
+
+	part.Set([]byte(`{"foo":"bar","baz":[1,2]}`))
+	part.MetaSet("a", "3")
+	err = cond.checkFrom(tmpDir, part)
+	require.Error(t, err)
+	assert.Equal(t, `snapshot mismatch
+-   a: "1"
++   a: "3"`, err.Error())
+
+	part.MetaSet("a", "1")
+	part.MetaSet("c", "3")
+	err = cond.checkFrom(tmpDir, part)
+	require.Error(t, err)
+	assert.Equal(t, `snapshot mismatch
+-   c: 3
++   c: "3"`, err.Error())
+}
+
+func TestLineDiff(t *testing.T) {
+	color.NoColor = true
+
+	assert.Equal(t, "", lineDiff("a\nb\nc", "a\nb\nc"))
+	assert.Equal(t, "- b", lineDiff("a\nb\nc", "a\nc"))
+	assert.Equal(t, "+ d", lineDiff("a\nb\nc", "a\nb\nc\nd"))
+	assert.Equal(t, "+ b", lineDiff("a", "b\na"))
+	assert.Equal(t, "- b\n+ d", lineDiff("a\nb\nc", "a\nd\nc"))
+}
+
+func TestLineDiffLarge(t *testing.T) {
+	color.NoColor = true
+
+	var exp, act []string
+	for i := 0; i < 10000; i++ {
+		exp = append(exp, fmt.Sprintf("expected %v", i))
+		act = append(act, fmt.Sprintf("actual %v", i))
+	}
+
+	lines := strings.Split(lineDiff(strings.Join(exp, "\n"), strings.Join(act, "\n")), "\n")
+	require.Len(t, lines, maxDiffLines+1)
+	assert.Equal(t, "- expected 0", lines[0])
+	assert.Equal(t, "... and 19900 more differing lines", lines[maxDiffLines])
+}
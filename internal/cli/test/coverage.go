@@ -0,0 +1,209 @@
+package test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+)
+
+// coveredSources returns the mapping sources of a coverage recorder that
+// contain at least one statement.
+func coveredSources(cov *mapping.Coverage) (sources []*mapping.SourceCoverage) {
+	if cov == nil {
+		return nil
+	}
+	for _, s := range cov.Sources() {
+		if len(s.Points()) > 0 {
+			sources = append(sources, s)
+		}
+	}
+	return
+}
+
+// mappingDescription returns a short description of a mapping source, which is
+// the path of its file, or otherwise the beginning of its first line.
+func mappingDescription(s *mapping.SourceCoverage) string {
+	if s.Name() != "" {
+		return s.Name()
+	}
+	desc := strings.TrimSpace(s.Mapping())
+	if i := strings.Index(desc, "\n"); i >= 0 {
+		desc = strings.TrimSpace(desc[:i]) + "..."
+	}
+	if len(desc) > 40 {
+		desc = desc[:40] + "..."
+	}
+	return "mapping `" + desc + "`"
+}
+
+func coverageSummaryStr(sum mapping.CoverageSummary) string {
+	return fmt.Sprintf("%.1f%% of statements (%v/%v), %.1f%% of branches (%v/%v)",
+		sum.StatementsPercent(), sum.CoveredStatements, sum.Statements,
+		sum.BranchesPercent(), sum.CoveredBranches, sum.Branches)
+}
+
+// writeCoverageSummary writes a human readable summary of the coverage of the
+// mappings of each test target, listing any statements and branches that were
+// never executed.
+func writeCoverageSummary(w io.Writer, results []TargetResult) {
+	fmt.Fprintf(w, "\nCoverage:\n")
+
+	var total mapping.CoverageSummary
+	for _, res := range results {
+		sources := coveredSources(res.Coverage)
+		if len(sources) == 0 {
+			continue
+		}
+
+		summary := res.Coverage.Summary()
+		total = total.Add(summary)
+
+		fmt.Fprintf(w, "\n%v: %v\n", res.Path, coverageSummaryStr(summary))
+		for _, s := range sources {
+			fmt.Fprintf(w, "  %v: %v\n", mappingDescription(s), coverageSummaryStr(s.Summary()))
+			for _, p := range s.Points() {
+				if p.Hits() == 0 {
+					fmt.Fprintf(w, "    %v %v at line %v, column %v\n", yellow("not executed:"), p.Kind, p.Line, p.Column)
+				}
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nTotal: %v\n", coverageSummaryStr(total))
+}
+
+//------------------------------------------------------------------------------
+
+type lcovBranch struct {
+	line int
+	hits int64
+}
+
+type lcovFile struct {
+	lines    map[int]int64
+	branches []lcovBranch
+}
+
+// locateMapping attempts to find the line of a config file on which a mapping
+// begins by searching for the first line of the mapping, starting from a given
+// line index. Returns the index of the line and true if found.
+func locateMapping(configLines []string, from int, mappingStr string) (int, bool) {
+	firstLine := strings.TrimSpace(strings.SplitN(strings.TrimSpace(mappingStr), "\n", 2)[0])
+	if firstLine == "" {
+		return 0, false
+	}
+	for _, start := range []int{from, 0} {
+		for i := start; i < len(configLines); i++ {
+			if strings.Contains(configLines[i], firstLine) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// writeCoverageLCOV writes the coverage of the mappings of each test target in
+// LCOV format. Mappings read from files are reported against those files, and
+// mappings embedded within a config are reported against the lines of the
+// config where they can be located.
+func writeCoverageLCOV(w io.Writer, results []TargetResult) error {
+	files := map[string]*lcovFile{}
+	var fileNames []string
+
+	getFile := func(name string) *lcovFile {
+		f, exists := files[name]
+		if !exists {
+			f = &lcovFile{lines: map[int]int64{}}
+			files[name] = f
+			fileNames = append(fileNames, name)
+		}
+		return f
+	}
+
+	for _, res := range results {
+		sources := coveredSources(res.Coverage)
+		if len(sources) == 0 {
+			continue
+		}
+
+		var configLines []string
+		if configBytes, err := os.ReadFile(res.Path); err == nil {
+			configLines = strings.Split(string(configBytes), "\n")
+		}
+
+		searchFrom := 0
+		for _, s := range sources {
+			fileName, lineOffset := s.Name(), 0
+			if fileName == "" {
+				i, found := locateMapping(configLines, searchFrom, s.Mapping())
+				if !found {
+					continue
+				}
+				leading := strings.Count(s.Mapping()[:len(s.Mapping())-len(strings.TrimLeft(s.Mapping(), " \t\r\n"))], "\n")
+				fileName, lineOffset, searchFrom = res.Path, i-leading, i+1
+			}
+
+			f := getFile(fileName)
+			for _, p := range s.Points() {
+				line := p.Line + lineOffset
+				if p.Kind.IsBranch() {
+					f.branches = append(f.branches, lcovBranch{line: line, hits: p.Hits()})
+					continue
+				}
+				if hits, exists := f.lines[line]; !exists || p.Hits() > hits {
+					f.lines[line] = p.Hits()
+				}
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, name := range fileNames {
+		f := files[name]
+
+		fmt.Fprintf(bw, "TN:\nSF:%v\n", name)
+
+		sort.SliceStable(f.branches, func(i, j int) bool {
+			return f.branches[i].line < f.branches[j].line
+		})
+		branchesHit := 0
+		for i, b := range f.branches {
+			fmt.Fprintf(bw, "BRDA:%v,0,%v,%v\n", b.line, i, b.hits)
+			if b.hits > 0 {
+				branchesHit++
+			}
+		}
+		fmt.Fprintf(bw, "BRF:%v\nBRH:%v\n", len(f.branches), branchesHit)
+
+		lines := make([]int, 0, len(f.lines))
+		for l := range f.lines {
+			lines = append(lines, l)
+		}
+		sort.Ints(lines)
+		linesHit := 0
+		for _, l := range lines {
+			fmt.Fprintf(bw, "DA:%v,%v\n", l, f.lines[l])
+			if f.lines[l] > 0 {
+				linesHit++
+			}
+		}
+		fmt.Fprintf(bw, "LF:%v\nLH:%v\nend_of_record\n", len(lines), linesHit)
+	}
+	return bw.Flush()
+}
+
+func writeCoverageLCOVFile(path string, results []TargetResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeCoverageLCOV(f, results); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/parser"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func testCoverageResults(t *testing.T) []TargetResult {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+
+	blobl := `root.a = if this.a > 10 {
+  "big"
+} else {
+  "small"
+}`
+	blobPath := filepath.Join(tmpDir, "foo.blobl")
+	require.NoError(t, os.WriteFile(blobPath, []byte(blobl), 0o644))
+
+	inline := `root.b = this.b.catch("nope")`
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`pipeline:
+  processors:
+    - bloblang: |
+        `+inline+`
+`), 0o644))
+
+	cov := mapping.NewCoverage()
+	for _, m := range []struct {
+		name, mapping string
+	}{
+		{name: blobPath, mapping: blobl},
+		{name: "", mapping: inline},
+	} {
+		exec, perr := parser.ParseMapping(parser.GlobalContext().WithCoverage(cov, m.name), m.mapping)
+		require.Nil(t, perr)
+
+		_, err := exec.MapPart(0, message.QuickBatch([][]byte{[]byte(`{"a":20,"b":"foo"}`)}))
+		require.NoError(t, err)
+	}
+
+	return []TargetResult{
+		{Path: configPath, Coverage: cov},
+		{Path: filepath.Join(tmpDir, "other.yaml")},
+	}
+}
+
+func TestCoverageSummary(t *testing.T) {
+	results := testCoverageResults(t)
+
+	var buf bytes.Buffer
+	writeCoverageSummary(&buf, results)
+
+	out := colorCodesRegexp.ReplaceAllString(buf.String(), "")
+	assert.Contains(t, out, results[0].Path+": 100.0% of statements (2/2), 33.3% of branches (1/3)\n")
+	assert.Contains(t, out, "foo.blobl: 100.0% of statements (1/1), 50.0% of branches (1/2)\n    not executed: else at line 3, column 3\n")
+	assert.Contains(t, out, "  mapping `root.b = this.b.catch(\"nope\")`: 100.0% of statements (1/1), 0.0% of branches (0/1)\n    not executed: catch at line 1, column 17\n")
+	assert.NotContains(t, out, "other.yaml")
+	assert.Contains(t, out, "\nTotal: 100.0% of statements (2/2), 33.3% of branches (1/3)\n")
+}
+
+func TestCoverageLCOV(t *testing.T) {
+	results := testCoverageResults(t)
+	blobPath := filepath.Join(filepath.Dir(results[0].Path), "foo.blobl")
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCoverageLCOV(&buf, results))
+
+	assert.Equal(t, `TN:
+SF:`+blobPath+`
+BRDA:1,0,0,1
+BRDA:3,0,1,0
+BRF:2
+BRH:1
+DA:1,1
+LF:1
+LH:1
+end_of_record
+TN:
+SF:`+results[0].Path+`
+BRDA:4,0,0,0
+BRF:1
+BRH:0
+DA:4,1
+LF:1
+LH:1
+end_of_record
+`, buf.String())
+}
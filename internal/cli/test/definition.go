@@ -3,7 +3,9 @@ package test
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/log"
 )
 
@@ -12,27 +14,67 @@ type Definition struct {
 	Cases []Case `yaml:"tests"`
 }
 
+// CaseResult contains the outcome of executing a single test case.
+type CaseResult struct {
+	Name     string
+	TestLine int
+	Duration time.Duration
+	Failures []CaseFailure
+}
+
 // Execute the test definition.
 func (d Definition) Execute(testFilePath string, resourcesPaths []string, logger log.Modular) ([]CaseFailure, error) {
+	results, err := d.ExecuteCases(testFilePath, resourcesPaths, logger, ExecuteOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var totalFailures []CaseFailure
+	for _, res := range results {
+		totalFailures = append(totalFailures, res.Failures...)
+	}
+	return totalFailures, nil
+}
+
+// ExecuteOptions customises the execution of a test definition.
+type ExecuteOptions struct {
+	// UpdateSnapshots causes the files of snapshot conditions to be rewritten
+	// with the output of each test case rather than checked.
+	UpdateSnapshots bool
+
+	// Coverage records the execution of Bloblang mappings when set.
+	Coverage *mapping.Coverage
+}
+
+// ExecuteCases executes the test definition and returns the result of each
+// test case.
+func (d Definition) ExecuteCases(testFilePath string, resourcesPaths []string, logger log.Modular, opts ExecuteOptions) ([]CaseResult, error) {
 	procsProvider := NewProcessorsProvider(
 		testFilePath,
 		OptAddResourcesPaths(resourcesPaths),
 		OptProcessorsProviderSetLogger(logger),
+		OptProcessorsProviderSetCoverage(opts.Coverage),
 	)
 
 	dir := filepath.Dir(testFilePath)
 
-	var totalFailures []CaseFailure
+	results := make([]CaseResult, 0, len(d.Cases))
 	for i, c := range d.Cases {
 		cleanupEnv := setEnvironment(c.Environment)
-		failures, err := c.executeFrom(dir, procsProvider)
+		start := time.Now()
+		failures, err := c.executeFrom(dir, procsProvider, opts.UpdateSnapshots)
 		if err != nil {
 			cleanupEnv()
 			return nil, fmt.Errorf("test case %v failed: %v", i, err)
 		}
-		totalFailures = append(totalFailures, failures...)
+		results = append(results, CaseResult{
+			Name:     c.Name,
+			TestLine: c.line,
+			Duration: time.Since(start),
+			Failures: failures,
+		})
 		cleanupEnv()
 	}
 
-	return totalFailures, nil
+	return results, nil
 }
@@ -51,9 +51,13 @@ It is also possible to target processors in a separate file by prefixing the tar
 			"target_mapping",
 			"A file path relative to the test definition path of a Bloblang file to execute as an alternative to testing processors with the `target_processors` field. This allows you to define unit tests for Bloblang mappings directly.",
 		).HasDefault(""),
+		docs.FieldBool(
+			"target_stream",
+			"Whether to execute the entire stream of the config as an alternative to testing processors with the `target_processors` field. The input of the stream is replaced with the batches of `input_batches`, and each output with a label listed in `outputs` is replaced with a sink that captures the batches it receives. This allows you to define unit tests for output routing and batching policies.",
+		).HasDefault(false),
 		docs.FieldAnything(
 			"mocks",
-			"An optional map of processors to mock. Keys should contain either a label or a JSON pointer of a processor that should be mocked. Values should contain a processor definition, which will replace the mocked processor. Most of the time you'll want to use a `bloblang` processor here, and use it to create a result that emulates the target processor.",
+			"An optional map of processors to mock. Keys should contain either a label or a JSON pointer of a processor that should be mocked. Values should contain a processor definition, which will replace the mocked processor. Labels of resources can also be mocked, in which case the value should contain a definition of the resource type. Most of the time you'll want to use a `bloblang` processor here, and use it to create a result that emulates the target processor.",
 			map[string]interface{}{
 				"get_foobar_api": map[string]interface{}{
 					"bloblang": "root = content().string() + \" this is some mock content\"",
@@ -65,22 +69,30 @@ It is also possible to target processors in a separate file by prefixing the tar
 				},
 			},
 		).Map().Optional(),
+		docs.FieldAnything(
+			"mock_caches",
+			"An optional map of cache resource labels to key/value pairs. Each cache resource is replaced with an in-memory cache preloaded with the given keys and values, allowing processors that read from or write to caches to be tested without network access.",
+			map[string]interface{}{
+				"foo_cache": map[string]interface{}{
+					"foo": "bar",
+				},
+			},
+		).Map().Optional(),
+		docs.FieldString(
+			"mock_rate_limits",
+			"An optional list of rate limit resource labels to mock. Each rate limit resource is replaced with a local rate limit that never throttles.",
+			[]string{"foo_rate_limit"},
+		).Array().Optional(),
+		docs.FieldObject(
+			"mock_responses",
+			"An optional map of processor labels to canned responses. Each processor is replaced with one that sets the contents and metadata of messages to the response, which is useful for mocking network processors such as `http` and `sql_raw`.",
+		).Map().Optional().WithChildren(inputPartFields()...),
 		docs.FieldObject(
 			"input_batch", "",
-		).Array().Optional().WithChildren(
-			docs.FieldString("content", "The raw content of the input message.").HasDefault(""),
-			docs.FieldAnything(`json_content`, "Sets the raw content of the message to a JSON document matching the structure of the value.", map[string]interface{}{
-				"foo": "foo value",
-				"bar": []interface{}{"element1", 10},
-			},
-			).Optional(),
-			docs.FieldString(
-				`file_content`,
-				"Sets the raw content of the message by reading a file. The path of the file should be relative to the path of the test file.",
-				"./foo/bar.txt",
-			).Optional(),
-			docs.FieldString("metadata", "A map of metadata key/values to add to the input message.").Map().Optional(),
-		),
+		).Array().Optional().WithChildren(inputPartFields()...),
+		docs.FieldObject(
+			"input_batches", "A list of batches to feed into the input of the stream when `target_stream` is enabled.",
+		).ArrayOfArrays().Optional().WithChildren(inputPartFields()...),
 		docs.FieldObject(
 			"output_batches", "",
 		).ArrayOfArrays().Optional().WithChildren(
@@ -105,6 +117,11 @@ It is also possible to target processors in a separate file by prefixing the tar
 				"Checks that the contents of a message matches the contents of a file. The path of the file should be relative to the path of the test file.",
 				"./foo/bar.txt",
 			).Optional(),
+			docs.FieldString(
+				`snapshot`,
+				"Checks that the contents and metadata of a message match a snapshot file, where contents that are valid JSON are formatted with indentation. The path of the file should be relative to the path of the test file. Snapshot files are created and updated by running tests with the flag `--update-snapshots`.",
+				"./snapshots/foo.yaml",
+			).Optional(),
 			docs.FieldAnything(
 				`json_equals`,
 				"Checks that both the message and the condition are valid JSON documents, and that they are structurally equivalent. Will ignore formatting and ordering differences.",
@@ -116,5 +133,33 @@ It is also possible to target processors in a separate file by prefixing the tar
 				map[string]interface{}{"key": "value"},
 			).Optional(),
 		),
+		docs.FieldAnything(
+			"outputs",
+			"A map of output labels to the batches expected to be received by that output when `target_stream` is enabled, where the batches are defined in the same format as `output_batches`.",
+			map[string]interface{}{
+				"foo_output": []interface{}{
+					[]interface{}{
+						map[string]interface{}{"content_equals": "foo"},
+					},
+				},
+			},
+		).Map().Optional(),
 	)
 }
+
+func inputPartFields() []docs.FieldSpec {
+	return []docs.FieldSpec{
+		docs.FieldString("content", "The raw content of the input message.").HasDefault(""),
+		docs.FieldAnything(`json_content`, "Sets the raw content of the message to a JSON document matching the structure of the value.", map[string]interface{}{
+			"foo": "foo value",
+			"bar": []interface{}{"element1", 10},
+		},
+		).Optional(),
+		docs.FieldString(
+			`file_content`,
+			"Sets the raw content of the message by reading a file. The path of the file should be relative to the path of the test file.",
+			"./foo/bar.txt",
+		).Optional(),
+		docs.FieldString("metadata", "A map of metadata key/values to add to the input message.").Map().Optional(),
+	}
+}
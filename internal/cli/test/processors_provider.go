@@ -10,6 +10,8 @@ import (
 	"github.com/Jeffail/gabs/v2"
 	yaml "gopkg.in/yaml.v3"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/parser"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	iprocessor "github.com/benthosdev/benthos/v4/internal/component/processor"
@@ -32,6 +34,7 @@ type ProcessorsProvider struct {
 	targetPath     string
 	resourcesPaths []string
 	cachedConfigs  map[string]cachedConfig
+	coverage       *mapping.Coverage
 
 	logger log.Modular
 }
@@ -63,6 +66,24 @@ func OptProcessorsProviderSetLogger(logger log.Modular) func(*ProcessorsProvider
 	}
 }
 
+// OptProcessorsProviderSetCoverage sets a coverage recorder for the execution
+// of Bloblang mappings within tested components.
+func OptProcessorsProviderSetCoverage(cov *mapping.Coverage) func(*ProcessorsProvider) {
+	return func(p *ProcessorsProvider) {
+		p.coverage = cov
+	}
+}
+
+// managerOpts returns options for the managers of tested components.
+func (p *ProcessorsProvider) managerOpts() []manager.OptFunc {
+	if p.coverage == nil {
+		return nil
+	}
+	return []manager.OptFunc{
+		manager.OptSetBloblangEnvironment(bloblang.GlobalEnvironment().WithCoverage(p.coverage)),
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Provide attempts to extract an array of processors from a Benthos config.
@@ -90,6 +111,9 @@ func (p *ProcessorsProvider) ProvideBloblang(pathStr string) ([]iprocessor.V1, e
 	}
 
 	pCtx := parser.GlobalContext().WithImporterRelativeToFile(pathStr)
+	if p.coverage != nil {
+		pCtx = pCtx.WithCoverage(p.coverage, pathStr)
+	}
 	exec, mapErr := parser.ParseMapping(pCtx, string(mappingBytes))
 	if mapErr != nil {
 		return nil, mapErr
@@ -103,7 +127,7 @@ func (p *ProcessorsProvider) ProvideBloblang(pathStr string) ([]iprocessor.V1, e
 //------------------------------------------------------------------------------
 
 func (p *ProcessorsProvider) initProcs(confs cachedConfig) ([]iprocessor.V1, error) {
-	mgr, err := manager.NewV2(confs.mgr, mock.NewManager(), p.logger, metrics.Noop())
+	mgr, err := manager.NewV2(confs.mgr, mock.NewManager(), p.logger, metrics.Noop(), p.managerOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialise resources: %v", err)
 	}
@@ -197,44 +221,95 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 	cleanupEnv := setEnvironment(environment)
 	defer cleanupEnv()
 
-	remainingMocks := map[string]yaml.Node{}
-	for k, v := range mocks {
-		remainingMocks[k] = v
-	}
-
 	configBytes, _, err := config.ReadFileEnvSwap(targetPath)
 	if err != nil {
 		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
 	}
 
+	root := &yaml.Node{}
+	if err = yaml.Unmarshal(configBytes, root); err != nil {
+		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+	}
+
+	labelsToPaths, err := applyMocks(root, mocks)
+	if err != nil {
+		return confs, err
+	}
+
+	// Resources are extracted after mocks are applied so that mocked resources
+	// replace the originals.
 	mgrWrapper := manager.NewResourceConfig()
-	if err = yaml.Unmarshal(configBytes, &mgrWrapper); err != nil {
+	if err = root.Decode(&mgrWrapper); err != nil {
 		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
 	}
 
+	if err = p.addResources(&mgrWrapper); err != nil {
+		return confs, err
+	}
+
+	confs.mgr = mgrWrapper
+
+	var pathSlice []string
+	if strings.HasPrefix(procPath, "/") {
+		if pathSlice, err = gabs.JSONPointerToSlice(procPath); err != nil {
+			return confs, fmt.Errorf("failed to parse case processors path '%v': %w", procPath, err)
+		}
+	} else {
+		if len(labelsToPaths) == 0 {
+			config.Spec().YAMLLabelsToPaths(docs.DeprecatedProvider, root, labelsToPaths, nil)
+		}
+		if pathSlice, exists = labelsToPaths[procPath]; !exists {
+			return confs, fmt.Errorf("target for label '%v' failed as the label was not found in the test target file, it is not currently possible to target resources imported separate to the test file", procPath)
+		}
+	}
+
+	if root, err = docs.GetYAMLPath(root, pathSlice...); err != nil {
+		return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+	}
+
+	if root.Kind == yaml.SequenceNode {
+		if err = root.Decode(&confs.procs); err != nil {
+			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+		}
+	} else {
+		var procConf processor.Config
+		if err = root.Decode(&procConf); err != nil {
+			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+		}
+		confs.procs = append(confs.procs, procConf)
+	}
+
+	p.cachedConfigs[cacheKey] = confs
+	return confs, nil
+}
+
+func (p *ProcessorsProvider) addResources(mgrWrapper *manager.ResourceConfig) error {
 	for _, path := range p.resourcesPaths {
 		resourceBytes, _, err := config.ReadFileEnvSwap(path)
 		if err != nil {
-			return confs, fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
+			return fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
 		}
 		extraMgrWrapper := manager.NewResourceConfig()
 		if err = yaml.Unmarshal(resourceBytes, &extraMgrWrapper); err != nil {
-			return confs, fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
+			return fmt.Errorf("failed to parse resources config file '%v': %v", path, err)
 		}
 		if err = mgrWrapper.AddFrom(&extraMgrWrapper); err != nil {
-			return confs, fmt.Errorf("failed to merge resources from '%v': %v", path, err)
+			return fmt.Errorf("failed to merge resources from '%v': %v", path, err)
 		}
 	}
+	return nil
+}
 
-	confs.mgr = mgrWrapper
-
-	root := &yaml.Node{}
-	if err = yaml.Unmarshal(configBytes, root); err != nil {
-		return confs, fmt.Errorf("failed to parse config file '%v': %v", targetPath, err)
+// applyMocks replaces the components of a config with mocks, starting with all
+// absolute paths in JSON pointer form, then parsing remaining mock targets as
+// label names. The labels of the config are returned mapped to their paths
+// when any have been resolved.
+func applyMocks(root *yaml.Node, mocks map[string]yaml.Node) (map[string][]string, error) {
+	remainingMocks := map[string]yaml.Node{}
+	for k, v := range mocks {
+		remainingMocks[k] = v
 	}
 
-	// Replace mock components, starting with all absolute paths in JSON pointer
-	// form, then parsing remaining mock targets as label names.
 	confSpec := config.Spec()
 	for k, v := range remainingMocks {
 		if !strings.HasPrefix(k, "/") {
@@ -242,10 +317,10 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		}
 		mockPathSlice, err := gabs.JSONPointerToSlice(k)
 		if err != nil {
-			return confs, fmt.Errorf("failed to parse mock path '%v': %w", k, err)
+			return nil, fmt.Errorf("failed to parse mock path '%v': %w", k, err)
 		}
 		if err = confSpec.SetYAMLPath(docs.DeprecatedProvider, root, &v, mockPathSlice...); err != nil {
-			return confs, fmt.Errorf("failed to set mock '%v': %w", k, err)
+			return nil, fmt.Errorf("failed to set mock '%v': %w", k, err)
 		}
 		delete(remainingMocks, k)
 	}
@@ -256,45 +331,39 @@ func (p *ProcessorsProvider) getConfs(jsonPtr string, environment map[string]str
 		for k, v := range remainingMocks {
 			mockPathSlice, exists := labelsToPaths[k]
 			if !exists {
-				return confs, fmt.Errorf("mock for label '%v' could not be applied as the label was not found in the test target file, it is not currently possible to mock resources imported separate to the test file", k)
+				return nil, fmt.Errorf("mock for label '%v' could not be applied as the label was not found in the test target file, it is not currently possible to mock resources imported separate to the test file", k)
+			}
+			if strings.HasSuffix(mockPathSlice[0], "_resources") {
+				v = withLabel(v, k)
 			}
-			if err = confSpec.SetYAMLPath(docs.DeprecatedProvider, root, &v, mockPathSlice...); err != nil {
-				return confs, fmt.Errorf("failed to set mock '%v': %w", k, err)
+			if err := confSpec.SetYAMLPath(docs.DeprecatedProvider, root, &v, mockPathSlice...); err != nil {
+				return nil, fmt.Errorf("failed to set mock '%v': %w", k, err)
 			}
 			delete(remainingMocks, k)
 		}
 	}
+	return labelsToPaths, nil
+}
 
-	var pathSlice []string
-	if strings.HasPrefix(procPath, "/") {
-		if pathSlice, err = gabs.JSONPointerToSlice(procPath); err != nil {
-			return confs, fmt.Errorf("failed to parse case processors path '%v': %w", procPath, err)
-		}
-	} else {
-		if len(labelsToPaths) == 0 {
-			confSpec.YAMLLabelsToPaths(docs.DeprecatedProvider, root, labelsToPaths, nil)
-		}
-		if pathSlice, exists = labelsToPaths[procPath]; !exists {
-			return confs, fmt.Errorf("target for label '%v' failed as the label was not found in the test target file, it is not currently possible to target resources imported separate to the test file", procPath)
-		}
+// withLabel returns a copy of a component config node with a label set, which
+// is necessary for mocked resources to remain accessible.
+func withLabel(node yaml.Node, label string) yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = *node.Content[0]
 	}
-
-	if root, err = docs.GetYAMLPath(root, pathSlice...); err != nil {
-		return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+	if node.Kind != yaml.MappingNode {
+		return node
 	}
 
-	if root.Kind == yaml.SequenceNode {
-		if err = root.Decode(&confs.procs); err != nil {
-			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
-		}
-	} else {
-		var procConf processor.Config
-		if err = root.Decode(&procConf); err != nil {
-			return confs, fmt.Errorf("failed to resolve case processors from '%v': %v", targetPath, err)
+	content := []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "label"},
+		{Kind: yaml.ScalarNode, Value: label},
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value != "label" {
+			content = append(content, node.Content[i], node.Content[i+1])
 		}
-		confs.procs = append(confs.procs, procConf)
 	}
-
-	p.cachedConfigs[cacheKey] = confs
-	return confs, nil
+	node.Content = content
+	return node
 }
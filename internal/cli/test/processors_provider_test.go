@@ -10,6 +10,7 @@ import (
 	yaml "gopkg.in/yaml.v3"
 
 	"github.com/benthosdev/benthos/v4/internal/cli/test"
+	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/message"
 	"github.com/benthosdev/benthos/v4/internal/old/processor"
 
@@ -390,3 +391,90 @@ pipeline:
 	_, err = provider.Provide("/pipeline/processors", nil, nil)
 	require.EqualError(t, err, "failed to initialise resources: cache resource label 'barcache' collides with a previously defined resource")
 }
+
+func TestProcessorsProviderMockResources(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+cache_resources:
+  - label: users
+    redis:
+      url: tcp://localhost:6379
+
+rate_limit_resources:
+  - label: api_limit
+    redis:
+      url: tcp://localhost:6379
+      key: api_limit
+      count: 1
+      interval: 1h
+
+processor_resources:
+  - label: enrich
+    bloblang: 'root = "not mocked"'
+
+pipeline:
+  processors:
+    - branch:
+        request_map: 'root = this.id'
+        processors:
+          - cache:
+              resource: users
+              operator: get
+              key: ${! content() }
+        result_map: 'root.user = this'
+    - label: get_api
+      http:
+        url: http://localhost:1234/nope
+        verb: GET
+        rate_limit: api_limit
+    - resource: enrich
+`,
+	}
+
+	testDir, err := initTestFiles(t, files)
+	require.NoError(t, err)
+
+	var def test.Definition
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tests:
+  - name: mocked resources
+    target_processors: /pipeline/processors/0
+    mock_caches:
+      users:
+        user1: '{"name":"Alice"}'
+    input_batch:
+      - content: '{"id":"user1"}'
+    output_batches:
+      - - json_equals: { "id": "user1", "user": { "name": "Alice" } }
+
+  - name: canned response
+    target_processors: /pipeline/processors/1
+    mock_rate_limits: [ api_limit ]
+    mock_responses:
+      get_api:
+        json_content:
+          status: ok
+        metadata:
+          http_status_code: '200'
+    input_batch:
+      - content: foo
+    output_batches:
+      - - json_equals: { "status": "ok" }
+          metadata_equals:
+            http_status_code: '200'
+
+  - name: mocked processor resource
+    target_processors: /pipeline/processors/2
+    mocks:
+      enrich:
+        bloblang: 'root = "mocked"'
+    input_batch:
+      - content: foo
+    output_batches:
+      - - content_equals: mocked
+`), &def))
+
+	failures, err := def.Execute(filepath.Join(testDir, "config.yaml"), nil, log.Noop())
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
@@ -0,0 +1,401 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
+)
+
+// The formats supported for reporting test results.
+const (
+	FormatDefault = "default"
+	FormatJUnit   = "junit"
+	FormatJSON    = "json"
+	FormatTAP     = "tap"
+)
+
+// TargetResult contains the outcome of executing the tests of a config file.
+type TargetResult struct {
+	Path     string
+	Lints    []string
+	Cases    []CaseResult
+	Duration time.Duration
+	Coverage *mapping.Coverage
+}
+
+// Failed returns true if the target has any lint errors or failed test cases.
+func (t TargetResult) Failed() bool {
+	if len(t.Lints) > 0 {
+		return true
+	}
+	for _, c := range t.Cases {
+		if len(c.Failures) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type reporter interface {
+	// targetDone is called after the tests of each target have been executed.
+	targetDone(w io.Writer, res TargetResult)
+
+	// done is called once the tests of all targets have been executed.
+	done(w io.Writer, results []TargetResult) error
+}
+
+func newReporter(format string) (reporter, error) {
+	switch format {
+	case FormatDefault, "":
+		return defaultReporter{}, nil
+	case FormatJUnit:
+		return junitReporter{}, nil
+	case FormatJSON:
+		return jsonReporter{}, nil
+	case FormatTAP:
+		return tapReporter{}, nil
+	}
+	return nil, fmt.Errorf("format not recognised: %v", format)
+}
+
+var colorCodesRegexp = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// splitReason splits the reason of a case failure into a summary of the
+// failing condition and a diff of the expected and actual values, stripped of
+// terminal color codes.
+func splitReason(reason string) (summary, diff string) {
+	reason = colorCodesRegexp.ReplaceAllString(reason, "")
+	if i := strings.Index(reason, "\n"); i >= 0 {
+		return reason[:i], reason[i+1:]
+	}
+	return reason, ""
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+//------------------------------------------------------------------------------
+
+type defaultReporter struct{}
+
+func (defaultReporter) targetDone(w io.Writer, res TargetResult) {
+	if res.Failed() {
+		fmt.Fprintf(w, "Test '%v' %v\n", res.Path, red("failed"))
+	} else {
+		fmt.Fprintf(w, "Test '%v' %v\n", res.Path, green("succeeded"))
+	}
+}
+
+func (defaultReporter) done(w io.Writer, results []TargetResult) error {
+	var fails []TargetResult
+	for _, res := range results {
+		if res.Failed() {
+			fails = append(fails, res)
+		}
+	}
+	if len(fails) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nFailures:\n\n")
+	for i, fail := range fails {
+		if i > 0 {
+			fmt.Fprintln(w, "")
+		}
+		fmt.Fprintf(w, "--- %v ---\n\n", fail.Path)
+		for _, lint := range fail.Lints {
+			fmt.Fprintf(w, "Lint: %v\n", lint)
+		}
+
+		var failCases []CaseFailure
+		for _, c := range fail.Cases {
+			failCases = append(failCases, c.Failures...)
+		}
+		if len(failCases) > 0 {
+			if len(fail.Lints) > 0 {
+				fmt.Fprintln(w, "")
+			}
+			var namePrev string
+			for i, fail := range failCases {
+				if namePrev != fail.Name {
+					if i > 0 {
+						fmt.Fprintln(w, "")
+					}
+					fmt.Fprintf(w, "%v [line %v]:\n", fail.Name, fail.TestLine)
+					namePrev = fail.Name
+				}
+				fmt.Fprintln(w, fail.Reason)
+			}
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	File      string          `xml:"file,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Time       string           `xml:"time,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+type junitReporter struct{}
+
+func (junitReporter) targetDone(w io.Writer, res TargetResult) {}
+
+func (junitReporter) done(w io.Writer, results []TargetResult) error {
+	var totalDuration time.Duration
+	suites := junitTestSuites{}
+	for _, res := range results {
+		suite := junitTestSuite{
+			Name: res.Path,
+			File: res.Path,
+			Time: junitTime(res.Duration),
+		}
+		if len(res.Lints) > 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "lint",
+				Classname: res.Path,
+				File:      res.Path,
+				Time:      junitTime(0),
+				Failure: &junitFailure{
+					Message: fmt.Sprintf("%v lint errors", len(res.Lints)),
+					Type:    "lint",
+					Content: strings.Join(res.Lints, "\n"),
+				},
+			})
+		}
+		for _, c := range res.Cases {
+			tc := junitTestCase{
+				Name:      c.Name,
+				Classname: res.Path,
+				File:      res.Path,
+				Line:      c.TestLine,
+				Time:      junitTime(c.Duration),
+			}
+			if len(c.Failures) > 0 {
+				summary, _ := splitReason(c.Failures[0].Reason)
+				reasons := make([]string, len(c.Failures))
+				for i, f := range c.Failures {
+					reasons[i] = colorCodesRegexp.ReplaceAllString(f.Reason, "")
+				}
+				tc.Failure = &junitFailure{
+					Message: summary,
+					Type:    "condition",
+					Content: strings.Join(reasons, "\n"),
+				}
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		for _, tc := range suite.TestCases {
+			suite.Tests++
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		totalDuration += res.Duration
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+	suites.Time = junitTime(totalDuration)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+type jsonFailure struct {
+	Condition string `json:"condition"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+type jsonCase struct {
+	Name       string        `json:"name"`
+	File       string        `json:"file"`
+	Line       int           `json:"line"`
+	Passed     bool          `json:"passed"`
+	DurationMS float64       `json:"duration_ms"`
+	Failures   []jsonFailure `json:"failures,omitempty"`
+}
+
+type jsonTarget struct {
+	File       string     `json:"file"`
+	Passed     bool       `json:"passed"`
+	DurationMS float64    `json:"duration_ms"`
+	Lints      []string   `json:"lints,omitempty"`
+	Cases      []jsonCase `json:"cases"`
+}
+
+type jsonReport struct {
+	Passed  bool         `json:"passed"`
+	Targets []jsonTarget `json:"targets"`
+}
+
+type jsonReporter struct{}
+
+func (jsonReporter) targetDone(w io.Writer, res TargetResult) {}
+
+func (jsonReporter) done(w io.Writer, results []TargetResult) error {
+	report := jsonReport{
+		Passed:  true,
+		Targets: make([]jsonTarget, 0, len(results)),
+	}
+	for _, res := range results {
+		target := jsonTarget{
+			File:       res.Path,
+			Passed:     !res.Failed(),
+			DurationMS: durationMillis(res.Duration),
+			Lints:      res.Lints,
+			Cases:      make([]jsonCase, 0, len(res.Cases)),
+		}
+		for _, c := range res.Cases {
+			jc := jsonCase{
+				Name:       c.Name,
+				File:       res.Path,
+				Line:       c.TestLine,
+				Passed:     len(c.Failures) == 0,
+				DurationMS: durationMillis(c.Duration),
+			}
+			for _, f := range c.Failures {
+				summary, diff := splitReason(f.Reason)
+				jc.Failures = append(jc.Failures, jsonFailure{
+					Condition: summary,
+					Diff:      diff,
+				})
+			}
+			target.Cases = append(target.Cases, jc)
+		}
+		if !target.Passed {
+			report.Passed = false
+		}
+		report.Targets = append(report.Targets, target)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+//------------------------------------------------------------------------------
+
+type tapDiagnostic struct {
+	File       string   `yaml:"file"`
+	Line       int      `yaml:"line,omitempty"`
+	DurationMS float64  `yaml:"duration_ms"`
+	Failures   []string `yaml:"failures,omitempty"`
+}
+
+type tapReporter struct{}
+
+func (tapReporter) targetDone(w io.Writer, res TargetResult) {}
+
+func (tapReporter) done(w io.Writer, results []TargetResult) error {
+	total := 0
+	for _, res := range results {
+		if len(res.Lints) > 0 {
+			total++
+		}
+		total += len(res.Cases)
+	}
+
+	fmt.Fprintln(w, "TAP version 13")
+	fmt.Fprintf(w, "1..%v\n", total)
+
+	n := 0
+	writeTest := func(name string, diag tapDiagnostic) error {
+		n++
+		name = strings.ReplaceAll(name, "#", "\\#")
+		if len(diag.Failures) == 0 {
+			fmt.Fprintf(w, "ok %v - %v\n", n, name)
+			return nil
+		}
+		fmt.Fprintf(w, "not ok %v - %v\n", n, name)
+		var diagBuf bytes.Buffer
+		enc := yaml.NewEncoder(&diagBuf)
+		enc.SetIndent(2)
+		if err := enc.Encode(diag); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "  ---")
+		for _, line := range strings.Split(strings.TrimSuffix(diagBuf.String(), "\n"), "\n") {
+			fmt.Fprintf(w, "  %v\n", line)
+		}
+		fmt.Fprintln(w, "  ...")
+		return nil
+	}
+
+	for _, res := range results {
+		if len(res.Lints) > 0 {
+			if err := writeTest(res.Path+" lint", tapDiagnostic{
+				File:     res.Path,
+				Failures: res.Lints,
+			}); err != nil {
+				return err
+			}
+		}
+		for _, c := range res.Cases {
+			diag := tapDiagnostic{
+				File:       res.Path,
+				Line:       c.TestLine,
+				DurationMS: durationMillis(c.Duration),
+			}
+			for _, f := range c.Failures {
+				diag.Failures = append(diag.Failures, colorCodesRegexp.ReplaceAllString(f.Reason, ""))
+			}
+			if err := writeTest(res.Path+" "+c.Name, diag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
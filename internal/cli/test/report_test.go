@@ -0,0 +1,152 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func testReportResults() []TargetResult {
+	return []TargetResult{
+		{
+			Path:     "foo.yaml",
+			Duration: time.Millisecond * 3,
+			Cases: []CaseResult{
+				{
+					Name:     "passes",
+					TestLine: 2,
+					Duration: time.Millisecond,
+				},
+				{
+					Name:     "fails",
+					TestLine: 8,
+					Duration: time.Millisecond * 2,
+					Failures: []CaseFailure{
+						{
+							Name:     "fails",
+							TestLine: 8,
+							Reason:   "batch 0 message 0: content_equals: content mismatch\n  expected: " + blue("foo") + "\n  received: " + red("bar"),
+						},
+					},
+				},
+			},
+		},
+		{
+			Path:  "bar.yaml",
+			Lints: []string{"line 3: field nope not recognised"},
+		},
+	}
+}
+
+func TestReportSplitReason(t *testing.T) {
+	summary, diff := splitReason("content_equals: content mismatch\n  expected: \x1b[34mfoo\x1b[0m\n  received: \x1b[31mbar\x1b[0m")
+	assert.Equal(t, "content_equals: content mismatch", summary)
+	assert.Equal(t, "  expected: foo\n  received: bar", diff)
+
+	summary, diff = splitReason("wrong batch count, expected 1, got 0")
+	assert.Equal(t, "wrong batch count, expected 1, got 0", summary)
+	assert.Equal(t, "", diff)
+}
+
+func TestReportJUnit(t *testing.T) {
+	rep, err := newReporter(FormatJUnit)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rep.done(&buf, testReportResults()))
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	require.Len(t, suites.TestSuites, 2)
+
+	foo := suites.TestSuites[0]
+	assert.Equal(t, "foo.yaml", foo.Name)
+	require.Len(t, foo.TestCases, 2)
+	assert.Nil(t, foo.TestCases[0].Failure)
+	assert.Equal(t, "0.001", foo.TestCases[0].Time)
+	assert.Equal(t, 8, foo.TestCases[1].Line)
+	require.NotNil(t, foo.TestCases[1].Failure)
+	assert.Equal(t, "batch 0 message 0: content_equals: content mismatch", foo.TestCases[1].Failure.Message)
+	assert.Contains(t, foo.TestCases[1].Failure.Content, "  received: bar")
+
+	bar := suites.TestSuites[1]
+	require.Len(t, bar.TestCases, 1)
+	assert.Equal(t, "lint", bar.TestCases[0].Name)
+	assert.Equal(t, "line 3: field nope not recognised", bar.TestCases[0].Failure.Content)
+}
+
+func TestReportJSON(t *testing.T) {
+	rep, err := newReporter(FormatJSON)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rep.done(&buf, testReportResults()))
+
+	var report jsonReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	assert.False(t, report.Passed)
+	require.Len(t, report.Targets, 2)
+
+	foo := report.Targets[0]
+	assert.False(t, foo.Passed)
+	assert.Equal(t, 3.0, foo.DurationMS)
+	require.Len(t, foo.Cases, 2)
+	assert.True(t, foo.Cases[0].Passed)
+	assert.Equal(t, jsonCase{
+		Name:       "fails",
+		File:       "foo.yaml",
+		Line:       8,
+		DurationMS: 2,
+		Failures: []jsonFailure{
+			{
+				Condition: "batch 0 message 0: content_equals: content mismatch",
+				Diff:      "  expected: foo\n  received: bar",
+			},
+		},
+	}, foo.Cases[1])
+
+	assert.Equal(t, []string{"line 3: field nope not recognised"}, report.Targets[1].Lints)
+}
+
+func TestReportTAP(t *testing.T) {
+	rep, err := newReporter(FormatTAP)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rep.done(&buf, testReportResults()))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "TAP version 13\n1..3\nok 1 - foo.yaml passes\nnot ok 2 - foo.yaml fails\n  ---\n"), out)
+	assert.Contains(t, out, "\n  ...\nnot ok 3 - bar.yaml lint\n  ---\n")
+	assert.True(t, strings.HasSuffix(out, "  ...\n"), out)
+
+	blocks := strings.Split(out, "  ---\n")
+	require.Len(t, blocks, 3)
+
+	var diag tapDiagnostic
+	require.NoError(t, yaml.Unmarshal([]byte(strings.Split(blocks[1], "  ...\n")[0]), &diag))
+	assert.Equal(t, tapDiagnostic{
+		File:       "foo.yaml",
+		Line:       8,
+		DurationMS: 2,
+		Failures: []string{
+			"batch 0 message 0: content_equals: content mismatch\n  expected: foo\n  received: bar",
+		},
+	}, diag)
+}
+
+func TestReportUnknownFormat(t *testing.T) {
+	_, err := newReporter("nope")
+	require.EqualError(t, err, "format not recognised: nope")
+}
@@ -0,0 +1,414 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/gabs/v2"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	iinput "github.com/benthosdev/benthos/v4/internal/component/input"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+	"github.com/benthosdev/benthos/v4/internal/old/input"
+	"github.com/benthosdev/benthos/v4/internal/old/output"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+)
+
+// streamFeederType is the input type that replaces the input of a stream under
+// test, which feeds the input batches of a test case into the stream.
+const streamFeederType = "benthos_test_feeder"
+
+// streamTestTimeout is the maximum period of time given to a stream under test
+// to process all input batches and shut down.
+var streamTestTimeout = time.Second * 30
+
+// StreamTarget is a stream extracted from a Benthos config where the input is
+// replaced by a feeder of test batches, and the outputs of chosen labels are
+// replaced by sinks that capture the batches they receive.
+type StreamTarget struct {
+	conf    stream.Config
+	mgrConf manager.ResourceConfig
+	mgrOpts []manager.OptFunc
+	sinks   map[string]string
+
+	logger log.Modular
+}
+
+// ProvideStream attempts to extract the stream of a Benthos config, where the
+// input is replaced with a feeder and each output identified by a label within
+// outputLabels is replaced with a sink. All other outputs that are not mocked
+// are replaced with drop outputs. Supports injected mocked components in the
+// parsed config.
+func (p *ProcessorsProvider) ProvideStream(environment map[string]string, mocks map[string]yaml.Node, outputLabels []string) (*StreamTarget, error) {
+	cleanupEnv := setEnvironment(environment)
+	defer cleanupEnv()
+
+	configBytes, _, err := config.ReadFileEnvSwap(p.targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%v': %v", p.targetPath, err)
+	}
+
+	root := &yaml.Node{}
+	if err = yaml.Unmarshal(configBytes, root); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%v': %v", p.targetPath, err)
+	}
+
+	labelsToPaths, err := applyMocks(root, mocks)
+	if err != nil {
+		return nil, err
+	}
+	if len(labelsToPaths) == 0 {
+		config.Spec().YAMLLabelsToPaths(docs.DeprecatedProvider, root, labelsToPaths, nil)
+	}
+
+	if inputNode, err := docs.GetYAMLPath(root, "input"); err == nil {
+		if err = replaceWithInproc(inputNode, "inputs", streamFeederType); err != nil {
+			return nil, fmt.Errorf("failed to replace stream input: %v", err)
+		}
+	}
+
+	s := &StreamTarget{
+		mgrOpts: p.managerOpts(),
+		sinks:   map[string]string{},
+		logger:  p.logger,
+	}
+	var capturedPaths [][]string
+	for i, label := range outputLabels {
+		path, exists := labelsToPaths[label]
+		if !exists || (path[0] != "output" && path[0] != "output_resources") {
+			return nil, fmt.Errorf("target for output label '%v' failed as the label was not found in the test target file", label)
+		}
+		outputNode, err := docs.GetYAMLPath(root, path...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve output '%v': %v", label, err)
+		}
+		pipe := fmt.Sprintf("benthos_test_sink_%v", i)
+		if err = replaceWithInproc(outputNode, "outputs", pipe); err != nil {
+			return nil, fmt.Errorf("failed to replace output '%v': %v", label, err)
+		}
+		s.sinks[label] = pipe
+		capturedPaths = append(capturedPaths, path)
+	}
+
+	if err = replaceUncapturedOutputs(root, mockPaths(mocks, labelsToPaths), capturedPaths); err != nil {
+		return nil, err
+	}
+
+	conf := config.New()
+	if _, err := docs.GetYAMLPath(root, "output"); err != nil {
+		// Without an output the default would write to stdout.
+		conf.Output.Type = output.TypeDrop
+	}
+	if err = root.Decode(&conf); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%v': %v", p.targetPath, err)
+	}
+	if conf.Input.Type == input.TypeBroker {
+		conf.Input.Broker.Inputs[0].Type = streamFeederType
+	} else {
+		conf.Input.Type = streamFeederType
+	}
+
+	s.conf = conf.Config
+	s.mgrConf = conf.ResourceConfig
+
+	targetOutputs := len(s.mgrConf.ResourceOutputs)
+	if err = p.addResources(&s.mgrConf); err != nil {
+		return nil, err
+	}
+	for i := targetOutputs; i < len(s.mgrConf.ResourceOutputs); i++ {
+		// Output resources imported separate to the test target cannot be
+		// captured or mocked, and are therefore always dropped.
+		dropConf := output.NewConfig()
+		dropConf.Type = output.TypeDrop
+		dropConf.Label = s.mgrConf.ResourceOutputs[i].Label
+		s.mgrConf.ResourceOutputs[i] = dropConf
+	}
+	return s, nil
+}
+
+// mockPaths returns the paths of all mocked components of a config.
+func mockPaths(mocks map[string]yaml.Node, labelsToPaths map[string][]string) [][]string {
+	var paths [][]string
+	for k := range mocks {
+		if strings.HasPrefix(k, "/") {
+			if path, err := gabs.JSONPointerToSlice(k); err == nil {
+				paths = append(paths, path)
+			}
+		} else if path, exists := labelsToPaths[k]; exists {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// hasPathPrefix returns true if a path begins with a given prefix, or is equal
+// to it.
+func hasPathPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i, p := range prefix {
+		if path[i] != p {
+			return false
+		}
+	}
+	return true
+}
+
+// replaceUncapturedOutputs replaces each output of a config that is neither
+// captured by a sink nor mocked with a drop output, in order to ensure that a
+// stream under test never writes to a real output. Outputs that contain a
+// captured or mocked output (such as a switch) are retained, but the remaining
+// outputs nested within them are replaced.
+func replaceUncapturedOutputs(root *yaml.Node, mocked, captured [][]string) error {
+	retained := append(append([][]string{}, mocked...), captured...)
+
+	var dropped [][]string
+	for _, path := range config.Spec().YAMLComponentPaths(docs.DeprecatedProvider, docs.TypeOutput, root, nil) {
+		skip := false
+		for _, r := range retained {
+			if hasPathPrefix(path, r) || hasPathPrefix(r, path) {
+				skip = true
+				break
+			}
+		}
+		for _, d := range dropped {
+			if hasPathPrefix(path, d) {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		outputNode, err := docs.GetYAMLPath(root, path...)
+		if err != nil {
+			return fmt.Errorf("failed to resolve output '%v': %v", strings.Join(path, "."), err)
+		}
+		if err = replaceWithDrop(outputNode); err != nil {
+			return fmt.Errorf("failed to replace output '%v': %v", strings.Join(path, "."), err)
+		}
+		dropped = append(dropped, path)
+	}
+	return nil
+}
+
+// replaceWithDrop replaces the type of an output config with a drop, retaining
+// its label and processors.
+func replaceWithDrop(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.New("expected object")
+	}
+
+	var content []*yaml.Node
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if k.Value == "label" || k.Value == "processors" {
+			content = append(content, k, v)
+		}
+	}
+	content = append(content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: output.TypeDrop},
+		&yaml.Node{Kind: yaml.MappingNode},
+	)
+
+	node.Content = content
+	return nil
+}
+
+// replaceWithInproc replaces the type of a component config with an inproc of
+// a given pipe, retaining its label and processors. When the replaced component
+// has a batching policy the inproc is wrapped with a broker in order to retain
+// it.
+func replaceWithInproc(node *yaml.Node, brokerChildrenField, pipe string) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.New("expected object")
+	}
+
+	var content []*yaml.Node
+	var batching *yaml.Node
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		switch k.Value {
+		case "label", "processors":
+			content = append(content, k, v)
+		default:
+			if v.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j < len(v.Content)-1; j += 2 {
+				if v.Content[j].Value == "batching" {
+					batching = v.Content[j+1]
+				}
+			}
+		}
+	}
+
+	inprocNode := &yaml.Node{}
+	if err := inprocNode.Encode(map[string]string{
+		input.TypeInproc: pipe,
+	}); err != nil {
+		return err
+	}
+	if batching == nil {
+		content = append(content, inprocNode.Content...)
+	} else {
+		brokerNode := &yaml.Node{
+			Kind: yaml.MappingNode,
+			Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Value: brokerChildrenField},
+				{Kind: yaml.SequenceNode, Content: []*yaml.Node{inprocNode}},
+				{Kind: yaml.ScalarNode, Value: "batching"},
+				batching,
+			},
+		}
+		content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Value: input.TypeBroker}, brokerNode)
+	}
+
+	node.Content = content
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// streamFeeder is an input that emits a fixed set of transactions and then
+// closes once they have all been acknowledged, as a real input would, so that
+// outputs are not shut down whilst they are still processing batches.
+type streamFeeder struct {
+	tChan     chan message.Transaction
+	closeOnce sync.Once
+}
+
+func newStreamFeeder(batches []*message.Batch) (*streamFeeder, []chan error) {
+	s := &streamFeeder{
+		tChan: make(chan message.Transaction, len(batches)),
+	}
+
+	var pending sync.WaitGroup
+	pending.Add(len(batches))
+
+	resChans := make([]chan error, len(batches))
+	for i, b := range batches {
+		resChan := make(chan error, 1)
+		resChans[i] = resChan
+
+		var ackOnce sync.Once
+		s.tChan <- message.NewTransactionFunc(b, func(ctx context.Context, err error) error {
+			ackOnce.Do(func() {
+				resChan <- err
+				pending.Done()
+			})
+			return nil
+		})
+	}
+
+	go func() {
+		pending.Wait()
+		s.CloseAsync()
+	}()
+	return s, resChans
+}
+
+func (s *streamFeeder) TransactionChan() <-chan message.Transaction {
+	return s.tChan
+}
+
+func (s *streamFeeder) Connected() bool {
+	return true
+}
+
+func (s *streamFeeder) CloseAsync() {
+	s.closeOnce.Do(func() {
+		close(s.tChan)
+	})
+}
+
+func (s *streamFeeder) WaitForClose(time.Duration) error {
+	return nil
+}
+
+// Run the stream by feeding it a slice of batches and waiting for the stream to
+// shut down once they are consumed. Returns the batches captured by each
+// output sink, and the acknowledgement results of each input batch.
+func (s *StreamTarget) Run(batches []*message.Batch, timeout time.Duration) (map[string][]*message.Batch, []error, error) {
+	feeder, resChans := newStreamFeeder(batches)
+
+	env := bundle.GlobalEnvironment.Clone()
+	if err := env.InputAdd(bundle.InputConstructorFromSimple(func(input.Config, bundle.NewManagement) (iinput.Streamed, error) {
+		return feeder, nil
+	}), docs.ComponentSpec{
+		Name: streamFeederType,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	mgrOpts := append([]manager.OptFunc{manager.OptSetEnvironment(env)}, s.mgrOpts...)
+	mgr, err := manager.NewV2(s.mgrConf, mock.NewManager(), s.logger, metrics.Noop(), mgrOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialise resources: %v", err)
+	}
+	defer func() {
+		mgr.CloseAsync()
+		_ = mgr.WaitForClose(timeout)
+	}()
+
+	closedChan := make(chan struct{})
+	strm, err := stream.New(s.conf, mgr, stream.OptOnClose(func() {
+		close(closedChan)
+	}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialise stream: %v", err)
+	}
+
+	var outputsMut sync.Mutex
+	outputs := map[string][]*message.Batch{}
+
+	var wg sync.WaitGroup
+	for label, pipe := range s.sinks {
+		tChan, err := mgr.GetPipe(pipe)
+		if err != nil {
+			_ = strm.Stop(timeout)
+			return nil, nil, fmt.Errorf("failed to capture output '%v': %v", label, err)
+		}
+		wg.Add(1)
+		go func(label string, tChan <-chan message.Transaction) {
+			defer wg.Done()
+			for tran := range tChan {
+				outputsMut.Lock()
+				outputs[label] = append(outputs[label], tran.Payload)
+				outputsMut.Unlock()
+				_ = tran.Ack(context.Background(), nil)
+			}
+		}(label, tChan)
+	}
+
+	select {
+	case <-closedChan:
+	case <-time.After(timeout):
+		_ = strm.Stop(timeout)
+		return nil, nil, fmt.Errorf("stream failed to consume all input batches within %v", timeout)
+	}
+	wg.Wait()
+
+	ackErrs := make([]error, len(resChans))
+	for i, resChan := range resChans {
+		select {
+		case ackErrs[i] = <-resChan:
+		default:
+			ackErrs[i] = errors.New("batch was not acknowledged")
+		}
+	}
+	return outputs, ackErrs, nil
+}
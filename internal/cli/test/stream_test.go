@@ -0,0 +1,196 @@
+package test_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/cli/test"
+	"github.com/benthosdev/benthos/v4/internal/log"
+)
+
+func TestStreamRouting(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+input:
+  label: foo_input
+  kafka:
+    addresses: [ localhost:9092 ]
+    topics: [ foo ]
+    consumer_group: foogroup
+  processors:
+    - bloblang: 'root = this.merge({"seen": true})'
+
+output:
+  switch:
+    cases:
+      - check: this.type == "order"
+        output:
+          label: orders
+          kafka:
+            addresses: [ localhost:9092 ]
+            topic: orders
+            batching:
+              count: 2
+      - output:
+          label: everything_else
+          kafka:
+            addresses: [ localhost:9092 ]
+            topic: other
+          processors:
+            - bloblang: 'root.id = this.id'
+`,
+	}
+
+	testDir, err := initTestFiles(t, files)
+	require.NoError(t, err)
+
+	var def test.Definition
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tests:
+  - name: routes orders
+    target_stream: true
+    input_batches:
+      - - json_content: { "id": "1", "type": "order" }
+      - - json_content: { "id": "2", "type": "refund" }
+      - - json_content: { "id": "3", "type": "order" }
+    outputs:
+      orders:
+        - - json_equals: { "id": "1", "type": "order", "seen": true }
+          - json_equals: { "id": "3", "type": "order", "seen": true }
+      everything_else:
+        - - json_equals: { "id": "2" }
+
+  - name: wrong routing
+    target_stream: true
+    mocks:
+      everything_else:
+        drop: {}
+    input_batches:
+      - - json_content: { "id": "1", "type": "refund" }
+    outputs:
+      orders:
+        - - json_contains: { "id": "1" }
+`), &def))
+
+	failures, err := def.Execute(filepath.Join(testDir, "config.yaml"), nil, log.Noop())
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, "wrong routing", failures[0].Name)
+	assert.Equal(t, "output 'orders': wrong batch count, expected 1, got 0", failures[0].Reason)
+}
+
+func TestStreamMockedOutputs(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+output:
+  fallback:
+    - label: primary
+      http_client:
+        url: http://localhost:1234/nope
+    - label: secondary
+      drop: {}
+`,
+	}
+
+	testDir, err := initTestFiles(t, files)
+	require.NoError(t, err)
+
+	var def test.Definition
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tests:
+  - name: falls back
+    target_stream: true
+    mocks:
+      primary:
+        reject: 'primary is down'
+    input_batches:
+      - - content: foo
+    outputs:
+      secondary:
+        - - content_equals: foo
+`), &def))
+
+	failures, err := def.Execute(filepath.Join(testDir, "config.yaml"), nil, log.Noop())
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
+
+func TestStreamUncapturedOutputs(t *testing.T) {
+	outDir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": fmt.Sprintf(`
+output:
+  broker:
+    pattern: fan_out
+    outputs:
+      - label: captured
+        drop: {}
+      - file:
+          path: %[1]v/nested.txt
+          codec: lines
+      - resource: foo_resource
+
+output_resources:
+  - label: foo_resource
+    file:
+      path: %[1]v/resource.txt
+      codec: lines
+`, outDir),
+	}
+
+	testDir, err := initTestFiles(t, files)
+	require.NoError(t, err)
+
+	var def test.Definition
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tests:
+  - name: captures labelled output
+    target_stream: true
+    input_batches:
+      - - content: foo
+    outputs:
+      captured:
+        - - content_equals: foo
+
+  - name: no captured outputs
+    target_stream: true
+    input_batches:
+      - - content: bar
+`), &def))
+
+	failures, err := def.Execute(filepath.Join(testDir, "config.yaml"), nil, log.Noop())
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	for _, name := range []string{"nested.txt", "resource.txt"} {
+		_, err := os.Stat(filepath.Join(outDir, name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+}
+
+func TestStreamProviderErrors(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+output:
+  label: foo
+  drop: {}
+`,
+	}
+
+	testDir, err := initTestFiles(t, files)
+	require.NoError(t, err)
+
+	provider := test.NewProcessorsProvider(filepath.Join(testDir, "config.yaml"))
+
+	_, err = provider.ProvideStream(nil, nil, []string{"foo"})
+	require.NoError(t, err)
+
+	_, err = provider.ProvideStream(nil, nil, []string{"bar"})
+	require.Error(t, err)
+}
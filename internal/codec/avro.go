@@ -0,0 +1,170 @@
+package codec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+type avroOCFReader struct {
+	r         io.ReadCloser
+	ocf       *goavro.OCFReader
+	sourceAck ReaderAckFn
+
+	mut      sync.Mutex
+	finished bool
+	pending  int32
+}
+
+func newAvroOCFReader(r io.ReadCloser, ackFn ReaderAckFn) (Reader, error) {
+	ocf, err := goavro.NewOCFReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avro ocf header: %w", err)
+	}
+	return &avroOCFReader{
+		r:         r,
+		ocf:       ocf,
+		sourceAck: ackOnce(ackFn),
+	}, nil
+}
+
+func (a *avroOCFReader) ack(ctx context.Context, err error) error {
+	a.mut.Lock()
+	a.pending--
+	doAck := a.pending == 0 && a.finished
+	a.mut.Unlock()
+
+	if err != nil {
+		return a.sourceAck(ctx, err)
+	}
+	if doAck {
+		return a.sourceAck(ctx, nil)
+	}
+	return nil
+}
+
+func (a *avroOCFReader) Next(ctx context.Context) ([]*message.Part, ReaderAckFn, error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if !a.ocf.Scan() {
+		err := a.ocf.Err()
+		if err == nil {
+			err = io.EOF
+			a.finished = true
+		} else {
+			_ = a.sourceAck(ctx, err)
+		}
+		return nil, nil, err
+	}
+
+	datum, err := a.ocf.Read()
+	if err != nil {
+		_ = a.sourceAck(ctx, err)
+		return nil, nil, err
+	}
+
+	jBytes, err := a.ocf.Codec().TextualFromNative(nil, datum)
+	if err != nil {
+		err = fmt.Errorf("failed to convert avro record to JSON: %w", err)
+		_ = a.sourceAck(ctx, err)
+		return nil, nil, err
+	}
+
+	a.pending++
+	return []*message.Part{message.NewPart(jBytes)}, a.ack, nil
+}
+
+func (a *avroOCFReader) Close(ctx context.Context) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if !a.finished {
+		_ = a.sourceAck(ctx, errors.New("service shutting down"))
+	}
+	if a.pending == 0 {
+		_ = a.sourceAck(ctx, nil)
+	}
+	return a.r.Close()
+}
+
+//------------------------------------------------------------------------------
+
+var avroOCFWriterConfig = WriterConfig{
+	Truncate: true,
+	Batched:  true,
+}
+
+// Records written to an avro OCF writer are buffered until the end of each
+// batch, where they are written as a single block followed by a sync marker.
+type avroOCFWriter struct {
+	w       io.WriteCloser
+	ocf     *goavro.OCFWriter
+	pending []interface{}
+}
+
+// parseAvroOCFWriterArgs parses the argument of an avro-ocf writer codec, which
+// is a schema file path optionally prefixed with a compression codec.
+func parseAvroOCFWriterArgs(args string) (compression, schemaPath string) {
+	for _, c := range []string{goavro.CompressionNullLabel, goavro.CompressionDeflateLabel, goavro.CompressionSnappyLabel} {
+		if strings.HasPrefix(args, c+":") {
+			return c, strings.TrimPrefix(args, c+":")
+		}
+	}
+	return goavro.CompressionNullLabel, args
+}
+
+func newAvroOCFWriter(w io.WriteCloser, compression, schemaPath string) (Writer, error) {
+	schemaBytes, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avro schema file: %w", err)
+	}
+
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:               w,
+		Schema:          string(schemaBytes),
+		CompressionName: compression,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro ocf writer: %w", err)
+	}
+	return &avroOCFWriter{w: w, ocf: ocf}, nil
+}
+
+func (a *avroOCFWriter) Write(ctx context.Context, p *message.Part) error {
+	datum, _, err := a.ocf.Codec().NativeFromTextual(p.Get())
+	if err != nil {
+		return fmt.Errorf("failed to convert JSON to avro record: %w", err)
+	}
+	a.pending = append(a.pending, datum)
+	return nil
+}
+
+func (a *avroOCFWriter) EndBatch(ctx context.Context) error {
+	if len(a.pending) == 0 {
+		return nil
+	}
+	err := a.ocf.Append(a.pending)
+	a.pending = nil
+	return err
+}
+
+func (a *avroOCFWriter) AbortBatch(ctx context.Context) {
+	a.pending = nil
+}
+
+func (a *avroOCFWriter) Close(ctx context.Context) error {
+	if err := a.EndBatch(ctx); err != nil {
+		_ = a.w.Close()
+		return err
+	}
+	return a.w.Close()
+}
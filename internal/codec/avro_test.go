@@ -0,0 +1,166 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+const testAvroSchema = `{
+  "type": "record",
+  "name": "person",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": "int"}
+  ]
+}`
+
+func TestAvroOCFWriterArgs(t *testing.T) {
+	for _, test := range []struct {
+		args        string
+		compression string
+		path        string
+	}{
+		{args: "./foo.avsc", compression: "null", path: "./foo.avsc"},
+		{args: "snappy:./foo.avsc", compression: "snappy", path: "./foo.avsc"},
+		{args: "deflate:/foo.avsc", compression: "deflate", path: "/foo.avsc"},
+		{args: "null:foo.avsc", compression: "null", path: "foo.avsc"},
+	} {
+		compression, path := parseAvroOCFWriterArgs(test.args)
+		assert.Equal(t, test.compression, compression, test.args)
+		assert.Equal(t, test.path, path, test.args)
+	}
+
+	_, _, err := GetWriter("avro-ocf:")
+	require.Error(t, err)
+}
+
+func TestAvroOCFReadWrite(t *testing.T) {
+	for _, compression := range []string{"null", "deflate", "snappy"} {
+		compression := compression
+		t.Run(compression, func(t *testing.T) {
+			schemaPath := filepath.Join(t.TempDir(), "schema.avsc")
+			require.NoError(t, os.WriteFile(schemaPath, []byte(testAvroSchema), 0o644))
+
+			wCtor, wConf, err := GetWriter("avro-ocf:" + compression + ":" + schemaPath)
+			require.NoError(t, err)
+			assert.True(t, wConf.Truncate)
+
+			buf := &bufferWriteCloser{}
+			w, err := wCtor(buf)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"foo","age":10}`))))
+			require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"bar","age":20}`))))
+			require.NoError(t, w.(BatchWriter).EndBatch(ctx))
+			require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"baz","age":30}`))))
+			require.Error(t, w.Write(ctx, message.NewPart([]byte(`{"nope":true}`))))
+			require.NoError(t, w.Close(ctx))
+			assert.True(t, buf.closed)
+
+			// Confirm the file is a valid container with a block for each batch.
+			ocf, err := goavro.NewOCFReader(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			assert.Equal(t, compression, ocf.CompressionName())
+
+			// The number of items remaining in the current block before each
+			// read reveals the block boundaries.
+			var remaining []int64
+			for ocf.Scan() {
+				remaining = append(remaining, ocf.RemainingBlockItems())
+				_, err := ocf.Read()
+				require.NoError(t, err)
+			}
+			require.NoError(t, ocf.Err())
+			assert.Equal(t, []int64{2, 1, 1}, remaining)
+
+			rCtor, err := GetReader("avro-ocf", NewReaderConfig())
+			require.NoError(t, err)
+
+			ack := errors.New("default err")
+			r, err := rCtor("foo.avro", noopCloser{bytes.NewReader(buf.Bytes()), false}, func(ctx context.Context, err error) error {
+				ack = err
+				return nil
+			})
+			require.NoError(t, err)
+
+			var docs []string
+			var ackFns []ReaderAckFn
+			for {
+				parts, ackFn, err := r.Next(ctx)
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				require.Len(t, parts, 1)
+				docs = append(docs, string(parts[0].Get()))
+				ackFns = append(ackFns, ackFn)
+			}
+
+			// The order of record fields within the JSON of decoded records is
+			// not guaranteed.
+			expected := []string{
+				`{"name":"foo","age":10}`,
+				`{"name":"bar","age":20}`,
+				`{"name":"baz","age":30}`,
+			}
+			require.Len(t, docs, len(expected))
+			for i, exp := range expected {
+				assert.JSONEq(t, exp, docs[i])
+			}
+
+			for _, fn := range ackFns {
+				require.NoError(t, fn(ctx, nil))
+			}
+			assert.NoError(t, ack)
+			assert.NoError(t, r.Close(ctx))
+		})
+	}
+}
+
+func TestAvroOCFWriterAbortBatch(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.avsc")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(testAvroSchema), 0o644))
+
+	wCtor, _, err := GetWriter("avro-ocf:" + schemaPath)
+	require.NoError(t, err)
+
+	buf := &bufferWriteCloser{}
+	w, err := wCtor(buf)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// A batch that fails part way through is aborted and then retried.
+	require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"foo","age":10}`))))
+	require.Error(t, w.Write(ctx, message.NewPart([]byte(`{"nope":true}`))))
+	w.(BatchAborter).AbortBatch(ctx)
+
+	require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"foo","age":10}`))))
+	require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"bar","age":20}`))))
+	require.NoError(t, w.(BatchWriter).EndBatch(ctx))
+	require.NoError(t, w.Close(ctx))
+
+	ocf, err := goavro.NewOCFReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	var names []interface{}
+	for ocf.Scan() {
+		datum, err := ocf.Read()
+		require.NoError(t, err)
+		names = append(names, datum.(map[string]interface{})["name"])
+	}
+	require.NoError(t, ocf.Err())
+	assert.Equal(t, []interface{}{"foo", "bar"}, names)
+}
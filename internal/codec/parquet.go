@@ -0,0 +1,263 @@
+package codec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// Parquet files can only be read with random access, and therefore the source
+// stream is written to a temporary file before row groups are read from it one
+// at a time.
+type parquetReader struct {
+	r         io.ReadCloser
+	sourceAck ReaderAckFn
+
+	tmpPath string
+	pFile   source.ParquetFile
+	pr      *reader.ParquetReader
+
+	// Maps the Go field names generated for the schema back to the names of
+	// columns within the file.
+	names map[string]string
+
+	mut      sync.Mutex
+	rowGroup int
+	finished bool
+	pending  int32
+}
+
+func newParquetReader(r io.ReadCloser, ackFn ReaderAckFn) (Reader, error) {
+	tmpFile, err := os.CreateTemp("", "benthos_parquet_*")
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmpFile.Name()
+
+	_, err = io.Copy(tmpFile, r)
+	if cErr := tmpFile.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to buffer parquet file: %w", err)
+	}
+
+	pFile, err := local.NewLocalFileReader(tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+
+	pr, err := reader.NewParquetReader(pFile, nil, 1)
+	if err != nil {
+		_ = pFile.Close()
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+
+	names := make(map[string]string, len(pr.SchemaHandler.Infos))
+	for _, info := range pr.SchemaHandler.Infos {
+		names[info.InName] = info.ExName
+	}
+
+	return &parquetReader{
+		r:         r,
+		sourceAck: ackOnce(ackFn),
+		tmpPath:   tmpPath,
+		pFile:     pFile,
+		pr:        pr,
+		names:     names,
+	}, nil
+}
+
+func (a *parquetReader) ack(ctx context.Context, err error) error {
+	a.mut.Lock()
+	a.pending--
+	doAck := a.pending == 0 && a.finished
+	a.mut.Unlock()
+
+	if err != nil {
+		return a.sourceAck(ctx, err)
+	}
+	if doAck {
+		return a.sourceAck(ctx, nil)
+	}
+	return nil
+}
+
+func (a *parquetReader) Next(ctx context.Context) ([]*message.Part, ReaderAckFn, error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	rowGroups := a.pr.Footer.GetRowGroups()
+	for a.rowGroup < len(rowGroups) {
+		numRows := int(rowGroups[a.rowGroup].GetNumRows())
+		a.rowGroup++
+		if numRows == 0 {
+			continue
+		}
+
+		rows, err := a.pr.ReadByNumber(numRows)
+		if err != nil {
+			err = fmt.Errorf("failed to read parquet row group: %w", err)
+			_ = a.sourceAck(ctx, err)
+			return nil, nil, err
+		}
+
+		parts := make([]*message.Part, 0, len(rows))
+		for _, row := range rows {
+			part := message.NewPart(nil)
+			part.SetJSON(parquetToGeneric(reflect.ValueOf(row), a.names))
+			parts = append(parts, part)
+		}
+
+		a.pending++
+		return parts, a.ack, nil
+	}
+
+	a.finished = true
+	return nil, nil, io.EOF
+}
+
+func (a *parquetReader) Close(ctx context.Context) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if !a.finished {
+		_ = a.sourceAck(ctx, errors.New("service shutting down"))
+	}
+	if a.pending == 0 {
+		_ = a.sourceAck(ctx, nil)
+	}
+
+	a.pr.ReadStop()
+	_ = a.pFile.Close()
+	_ = os.Remove(a.tmpPath)
+	return a.r.Close()
+}
+
+// parquetToGeneric converts the values produced by the parquet reader, which
+// are structs generated from the file schema, into generic values that can be
+// set as structured message contents.
+func parquetToGeneric(v reflect.Value, names map[string]string) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return parquetToGeneric(v.Elem(), names)
+	case reflect.Struct:
+		t := v.Type()
+		obj := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			name := t.Field(i).Name
+			if exName, exists := names[name]; exists {
+				name = exName
+			}
+			obj[name] = parquetToGeneric(v.Field(i), names)
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		arr := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			arr[i] = parquetToGeneric(v.Index(i), names)
+		}
+		return arr
+	case reflect.Map:
+		obj := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			obj[fmt.Sprintf("%v", parquetToGeneric(iter.Key(), names))] = parquetToGeneric(iter.Value(), names)
+		}
+		return obj
+	}
+	return v.Interface()
+}
+
+//------------------------------------------------------------------------------
+
+var parquetWriterConfig = WriterConfig{
+	Truncate: true,
+	Batched:  true,
+}
+
+// Rows written to a parquet writer are buffered until the end of each batch,
+// where they are flushed as a row group. The footer of the file is written
+// once the writer is closed.
+//
+// Documents are held back from the underlying parquet writer until the end of
+// the batch, as it may flush rows into pages at any point, which would prevent
+// the rows of an aborted batch from being discarded.
+type parquetWriter struct {
+	w       io.WriteCloser
+	pw      *writer.JSONWriter
+	pending []string
+}
+
+func newParquetWriter(w io.WriteCloser, schemaPath string) (Writer, error) {
+	schemaBytes, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet schema file: %w", err)
+	}
+
+	pw, err := writer.NewJSONWriterFromWriter(string(schemaBytes), w, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	return &parquetWriter{w: w, pw: pw}, nil
+}
+
+func (p *parquetWriter) Write(ctx context.Context, part *message.Part) error {
+	// The parquet JSON writer silently ignores documents that fail to parse and
+	// writes them as empty rows, therefore we check them up front.
+	v, err := part.JSON()
+	if err != nil {
+		return fmt.Errorf("failed to parse message as JSON: %w", err)
+	}
+	if _, ok := v.(map[string]interface{}); !ok {
+		return fmt.Errorf("expected message to be a JSON object, got %T", v)
+	}
+	p.pending = append(p.pending, string(part.Get()))
+	return nil
+}
+
+func (p *parquetWriter) EndBatch(ctx context.Context) error {
+	pending := p.pending
+	p.pending = nil
+	for _, doc := range pending {
+		if err := p.pw.Write(doc); err != nil {
+			return fmt.Errorf("failed to write document to parquet file: %w", err)
+		}
+	}
+	return p.pw.Flush(true)
+}
+
+func (p *parquetWriter) AbortBatch(ctx context.Context) {
+	p.pending = nil
+}
+
+func (p *parquetWriter) Close(ctx context.Context) error {
+	if err := p.EndBatch(ctx); err != nil {
+		_ = p.w.Close()
+		return err
+	}
+	if err := p.pw.WriteStop(); err != nil {
+		_ = p.w.Close()
+		return fmt.Errorf("failed to close parquet writer: %w", err)
+	}
+	return p.w.Close()
+}
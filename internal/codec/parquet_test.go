@@ -0,0 +1,209 @@
+package codec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+type bufferWriteCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferWriteCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+func writeParquetTestSchema(t *testing.T) string {
+	t.Helper()
+
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{
+  "Tag": "name=root, repetitiontype=REQUIRED",
+  "Fields": [
+    {"Tag": "name=name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"},
+    {"Tag": "name=age, type=INT32, repetitiontype=REQUIRED"}
+  ]
+}`), 0o644))
+	return schemaPath
+}
+
+func writeParquetTestFile(t *testing.T, batches ...[]string) []byte {
+	t.Helper()
+
+	ctor, conf, err := GetWriter("parquet:" + writeParquetTestSchema(t))
+	require.NoError(t, err)
+	assert.True(t, conf.Truncate)
+	assert.False(t, conf.Append)
+
+	buf := &bufferWriteCloser{}
+	w, err := ctor(buf)
+	require.NoError(t, err)
+
+	bw, ok := w.(BatchWriter)
+	require.True(t, ok)
+
+	ctx := context.Background()
+	for _, batch := range batches {
+		for _, doc := range batch {
+			require.NoError(t, w.Write(ctx, message.NewPart([]byte(doc))))
+		}
+		require.NoError(t, bw.EndBatch(ctx))
+	}
+	require.NoError(t, w.Close(ctx))
+	assert.True(t, buf.closed)
+
+	return buf.Bytes()
+}
+
+func TestParquetWriterErrors(t *testing.T) {
+	_, _, err := GetWriter("parquet:")
+	require.Error(t, err)
+
+	ctor, conf, err := GetWriter("parquet:/does/not/exist.json")
+	require.NoError(t, err)
+	assert.True(t, conf.Batched)
+
+	_, err = ctor(&bufferWriteCloser{})
+	require.Error(t, err)
+}
+
+func TestParquetWriterInvalidJSON(t *testing.T) {
+	ctor, _, err := GetWriter("parquet:" + writeParquetTestSchema(t))
+	require.NoError(t, err)
+
+	w, err := ctor(&bufferWriteCloser{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	assert.Error(t, w.Write(ctx, message.NewPart([]byte(`not json`))))
+	assert.Error(t, w.Write(ctx, message.NewPart([]byte(`["not","an","object"]`))))
+	assert.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"foo","age":10}`))))
+	require.NoError(t, w.Close(ctx))
+}
+
+func TestParquetReadWrite(t *testing.T) {
+	data := writeParquetTestFile(t,
+		[]string{
+			`{"name":"foo","age":10}`,
+			`{"name":"bar","age":20}`,
+		},
+		[]string{
+			`{"name":"baz","age":30}`,
+		},
+	)
+
+	ctor, err := GetReader("parquet", NewReaderConfig())
+	require.NoError(t, err)
+
+	ack := errors.New("default err")
+	r, err := ctor("foo.parquet", noopCloser{bytes.NewReader(data), false}, func(ctx context.Context, err error) error {
+		ack = err
+		return nil
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	var batches [][]string
+	var ackFns []ReaderAckFn
+	for {
+		parts, ackFn, err := r.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		var batch []string
+		for _, p := range parts {
+			batch = append(batch, string(p.Get()))
+		}
+		batches = append(batches, batch)
+		ackFns = append(ackFns, ackFn)
+	}
+
+	assert.Equal(t, [][]string{
+		{`{"age":10,"name":"foo"}`, `{"age":20,"name":"bar"}`},
+		{`{"age":30,"name":"baz"}`},
+	}, batches)
+
+	for _, fn := range ackFns {
+		require.NoError(t, fn(ctx, nil))
+	}
+	assert.NoError(t, ack)
+	assert.NoError(t, r.Close(ctx))
+}
+
+func TestParquetAutoCodec(t *testing.T) {
+	data := writeParquetTestFile(t, []string{`{"name":"foo","age":10}`})
+
+	ctor, err := GetReader("auto", NewReaderConfig())
+	require.NoError(t, err)
+
+	r, err := ctor("foo.parquet", noopCloser{bytes.NewReader(data), false}, func(ctx context.Context, err error) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	parts, _, err := r.Next(context.Background())
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	assert.Equal(t, `{"age":10,"name":"foo"}`, string(parts[0].Get()))
+
+	assert.NoError(t, r.Close(context.Background()))
+}
+
+func TestParquetWriterAbortBatch(t *testing.T) {
+	ctor, _, err := GetWriter("parquet:" + writeParquetTestSchema(t))
+	require.NoError(t, err)
+
+	buf := &bufferWriteCloser{}
+	w, err := ctor(buf)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// A batch that fails part way through is aborted and then retried.
+	require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"foo","age":10}`))))
+	require.Error(t, w.Write(ctx, message.NewPart([]byte(`not json`))))
+	w.(BatchAborter).AbortBatch(ctx)
+
+	require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"foo","age":10}`))))
+	require.NoError(t, w.Write(ctx, message.NewPart([]byte(`{"name":"bar","age":20}`))))
+	require.NoError(t, w.(BatchWriter).EndBatch(ctx))
+	require.NoError(t, w.Close(ctx))
+
+	rCtor, err := GetReader("parquet", NewReaderConfig())
+	require.NoError(t, err)
+
+	r, err := rCtor("foo.parquet", noopCloser{bytes.NewReader(buf.Bytes()), false}, func(ctx context.Context, err error) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	var docs []string
+	for {
+		parts, ackFn, err := r.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		for _, p := range parts {
+			docs = append(docs, string(p.Get()))
+		}
+		require.NoError(t, ackFn(ctx, nil))
+	}
+	assert.Equal(t, []string{`{"age":10,"name":"foo"}`, `{"age":20,"name":"bar"}`}, docs)
+	assert.NoError(t, r.Close(ctx))
+}
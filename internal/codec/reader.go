@@ -26,6 +26,7 @@ var ReaderDocs = docs.FieldString(
 ).HasAnnotatedOptions(
 	"auto", "EXPERIMENTAL: Attempts to derive a codec for each file based on information such as the extension. For example, a .tar.gz file would be consumed with the `gzip/tar` codec. Defaults to all-bytes.",
 	"all-bytes", "Consume the entire file as a single binary message.",
+	"avro-ocf", "EXPERIMENTAL: Consume an [Avro Object Container File](https://avro.apache.org/docs/current/spec.html#Object+Container+Files), where each record is consumed as a JSON message. The schema of records and the compression of blocks (`deflate` or `snappy`) are read from the file header.",
 	"chunker:x", "Consume the file in chunks of a given number of bytes.",
 	"csv", "Consume structured rows as comma separated values, the first row must be a header row.",
 	"csv:x", "Consume structured rows as values separated by a custom delimiter, the first row must be a header row. The custom delimiter must be a single character, e.g. the codec `\"csv:\\t\"` would consume a tab delimited file.",
@@ -33,6 +34,7 @@ var ReaderDocs = docs.FieldString(
 	"gzip", "Decompress a gzip file, this codec should precede another codec, e.g. `gzip/all-bytes`, `gzip/tar`, `gzip/csv`, etc.",
 	"lines", "Consume the file in segments divided by linebreaks.",
 	"multipart", "Consumes the output of another codec and batches messages together. A batch ends when an empty message is consumed. For example, the codec `lines/multipart` could be used to consume multipart messages where an empty line indicates the end of each batch.",
+	"parquet", "EXPERIMENTAL: Consume a [Parquet file](https://parquet.apache.org/documentation/latest/), where each row group of the file is consumed as a batch of structured messages, one for each row. The file is buffered to a temporary file on disk in order to be read.",
 	"regex:(?m)^\\d\\d:\\d\\d:\\d\\d", "Consume the file in segments divided by regular expression.",
 	"tar", "Parse the file as a tar archive, and consume each file of the archive as a message.",
 ).LinterFunc(nil) // Disable default option linter as it doesn't include foo:bar formats.
@@ -218,6 +220,14 @@ func partReader(codec string, conf ReaderConfig) (ReaderConstructor, bool, error
 		}, true, nil
 	case "tar":
 		return newTarReader, true, nil
+	case "parquet":
+		return func(path string, r io.ReadCloser, fn ReaderAckFn) (Reader, error) {
+			return newParquetReader(r, fn)
+		}, true, nil
+	case "avro-ocf":
+		return func(path string, r io.ReadCloser, fn ReaderAckFn) (Reader, error) {
+			return newAvroOCFReader(r, fn)
+		}, true, nil
 	}
 	if strings.HasPrefix(codec, "delim:") {
 		by := strings.TrimPrefix(codec, "delim:")
@@ -294,6 +304,10 @@ func autoCodec(conf ReaderConfig) ReaderConstructor {
 			codec = "tar"
 		case ".tgz":
 			codec = "gzip/tar"
+		case ".parquet":
+			codec = "parquet"
+		case ".avro":
+			codec = "avro-ocf"
 		}
 		if strings.HasSuffix(path, ".tar.gzip") {
 			codec = "gzip/tar"
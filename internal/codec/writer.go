@@ -18,10 +18,18 @@ var WriterDocs = docs.FieldString(
 ).HasAnnotatedOptions(
 	"all-bytes", "Only applicable to file based outputs. Writes each message to a file in full, if the file already exists the old content is deleted.",
 	"append", "Append each message to the output stream without any delimiter or special encoding.",
+	"avro-ocf:x", "EXPERIMENTAL: Only applicable to file and object storage based outputs. Writes JSON messages as records of an [Avro Object Container File](https://avro.apache.org/docs/current/spec.html#Object+Container+Files) using a schema read from the file path x. The path can be prefixed with a compression codec for blocks, e.g. `avro-ocf:snappy:./schema.avsc`, where `null`, `deflate` and `snappy` are supported. The records of each batch are written as a block.",
 	"lines", "Append each message to the output stream followed by a line break.",
 	"delim:x", "Append each message to the output stream followed by a custom delimiter.",
+	"parquet:x", "EXPERIMENTAL: Only applicable to file and object storage based outputs. Writes messages as rows of a [Parquet file](https://parquet.apache.org/documentation/latest/) using a schema read from the file path x, the format of which is described in the [`parquet` processor docs](/docs/components/processors/parquet#defining-the-schema). The rows of each batch are flushed as a row group, and the file is completed once it is closed.",
 ).LinterFunc(nil) // Disable default option linter as it doesn't include foo:bar formats.
 
+// ObjectWriterDocs is a static field documentation for the codecs of outputs
+// that upload objects to a storage service.
+var ObjectWriterDocs = docs.FieldString(
+	"codec", "The way in which the messages of a batch are written into objects. With the default `all-bytes` each message is uploaded as an object of its own. Any other codec listed for the [`file` output](/docs/components/outputs/file#codec) writes all messages of a batch into a single object, where the path and other interpolated fields are resolved from the first message of the batch.", "lines", "parquet:./schema.json",
+).AtVersion("4.0.0")
+
 //------------------------------------------------------------------------------
 
 // Writer is a codec type that reads message parts from a source.
@@ -30,12 +38,32 @@ type Writer interface {
 	Close(context.Context) error
 }
 
+// BatchWriter is an optional interface implemented by writer codecs that
+// buffer messages until the end of each batch. Outputs call EndBatch once all
+// messages of a batch have been written.
+type BatchWriter interface {
+	Writer
+	EndBatch(context.Context) error
+}
+
+// BatchAborter is an optional interface implemented by batch writers that are
+// able to discard the messages written since the last call to EndBatch.
+// Outputs call AbortBatch when a batch fails part way through, which prevents
+// the messages of the failed batch from being flushed along with the next.
+type BatchAborter interface {
+	AbortBatch(context.Context)
+}
+
 // WriterConfig contains custom configuration specific to a codec describing how
 // handles should be provided.
 type WriterConfig struct {
 	Append     bool
 	Truncate   bool
 	CloseAfter bool
+
+	// Batched is true for codecs that implement BatchWriter, which can only be
+	// used by outputs that call EndBatch.
+	Batched bool
 }
 
 // WriterConstructor creates a writer from an io.WriteCloser.
@@ -64,6 +92,24 @@ func GetWriter(codec string) (WriterConstructor, WriterConfig, error) {
 			return newCustomDelimWriter(w, by)
 		}, customDelimConfig, nil
 	}
+	if strings.HasPrefix(codec, "parquet:") {
+		schemaPath := strings.TrimPrefix(codec, "parquet:")
+		if schemaPath == "" {
+			return nil, WriterConfig{}, errors.New("parquet codec requires a non-empty schema file path")
+		}
+		return func(w io.WriteCloser) (Writer, error) {
+			return newParquetWriter(w, schemaPath)
+		}, parquetWriterConfig, nil
+	}
+	if strings.HasPrefix(codec, "avro-ocf:") {
+		compression, schemaPath := parseAvroOCFWriterArgs(strings.TrimPrefix(codec, "avro-ocf:"))
+		if schemaPath == "" {
+			return nil, WriterConfig{}, errors.New("avro-ocf codec requires a non-empty schema file path")
+		}
+		return func(w io.WriteCloser) (Writer, error) {
+			return newAvroOCFWriter(w, compression, schemaPath)
+		}, avroOCFWriterConfig, nil
+	}
 	return nil, WriterConfig{}, fmt.Errorf("codec was not recognised: %v", codec)
 }
 
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"github.com/benthosdev/benthos/v4/internal/otlpconfig"
+)
+
+// OpenTelemetryCollectorConfig is config for the OpenTelemetry collector
+// metrics type. Endpoints share the same config as the OpenTelemetry collector
+// tracer type.
+type OpenTelemetryCollectorConfig struct {
+	HTTP         []otlpconfig.EndpointConfig `json:"http" yaml:"http"`
+	GRPC         []otlpconfig.EndpointConfig `json:"grpc" yaml:"grpc"`
+	PushInterval string                      `json:"push_interval" yaml:"push_interval"`
+	Tags         map[string]string           `json:"tags" yaml:"tags"`
+}
+
+// NewOpenTelemetryCollectorConfig creates an OpenTelemetryCollectorConfig
+// struct with default values.
+func NewOpenTelemetryCollectorConfig() OpenTelemetryCollectorConfig {
+	return OpenTelemetryCollectorConfig{
+		HTTP:         []otlpconfig.EndpointConfig{},
+		GRPC:         []otlpconfig.EndpointConfig{},
+		PushInterval: "10s",
+		Tags:         map[string]string{},
+	}
+}
@@ -18,17 +18,21 @@ type Type interface {
 
 // Config is the all encompassing configuration struct for all tracer types.
 type Config struct {
-	Type   string       `json:"type" yaml:"type"`
-	Jaeger JaegerConfig `json:"jaeger" yaml:"jaeger"`
-	None   struct{}     `json:"none" yaml:"none"`
+	Type                   string                       `json:"type" yaml:"type"`
+	Jaeger                 JaegerConfig                 `json:"jaeger" yaml:"jaeger"`
+	None                   struct{}                     `json:"none" yaml:"none"`
+	OpenTelemetryCollector OpenTelemetryCollectorConfig `json:"open_telemetry_collector" yaml:"open_telemetry_collector"`
+	Plugin                 interface{}                  `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 }
 
 // NewConfig returns a configuration struct fully populated with default values.
 func NewConfig() Config {
 	return Config{
-		Type:   "none",
-		Jaeger: NewJaegerConfig(),
-		None:   struct{}{},
+		Type:                   "none",
+		Jaeger:                 NewJaegerConfig(),
+		None:                   struct{}{},
+		OpenTelemetryCollector: NewOpenTelemetryCollectorConfig(),
+		Plugin:                 nil,
 	}
 }
 
@@ -45,10 +49,21 @@ func (conf *Config) UnmarshalYAML(value *yaml.Node) error {
 		return fmt.Errorf("line %v: %v", value.Line, err)
 	}
 
-	if aliased.Type, _, err = docs.GetInferenceCandidateFromYAML(docs.DeprecatedProvider, docs.TypeTracer, value); err != nil {
+	var spec docs.ComponentSpec
+	if aliased.Type, spec, err = docs.GetInferenceCandidateFromYAML(docs.DeprecatedProvider, docs.TypeTracer, value); err != nil {
 		return fmt.Errorf("line %v: %w", value.Line, err)
 	}
 
+	if spec.Plugin {
+		pluginNode, err := docs.GetPluginConfigYAML(aliased.Type, value)
+		if err != nil {
+			return fmt.Errorf("line %v: %v", value.Line, err)
+		}
+		aliased.Plugin = &pluginNode
+	} else {
+		aliased.Plugin = nil
+	}
+
 	*conf = Config(aliased)
 	return nil
 }
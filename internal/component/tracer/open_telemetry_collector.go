@@ -0,0 +1,51 @@
+package tracer
+
+import (
+	"github.com/benthosdev/benthos/v4/internal/otlpconfig"
+)
+
+// OTLPSamplingConfig determines the sampling strategy of spans exported to an
+// OpenTelemetry collector.
+type OTLPSamplingConfig struct {
+	Enabled bool    `json:"enabled" yaml:"enabled"`
+	Ratio   float64 `json:"ratio" yaml:"ratio"`
+}
+
+// OTLPBatchConfig contains settings for the batch span processor used when
+// exporting spans to an OpenTelemetry collector.
+type OTLPBatchConfig struct {
+	MaxQueueSize       int    `json:"max_queue_size" yaml:"max_queue_size"`
+	MaxExportBatchSize int    `json:"max_export_batch_size" yaml:"max_export_batch_size"`
+	FlushInterval      string `json:"flush_interval" yaml:"flush_interval"`
+	ExportTimeout      string `json:"export_timeout" yaml:"export_timeout"`
+}
+
+// OpenTelemetryCollectorConfig is config for the OpenTelemetry collector
+// tracer type.
+type OpenTelemetryCollectorConfig struct {
+	HTTP     []otlpconfig.EndpointConfig `json:"http" yaml:"http"`
+	GRPC     []otlpconfig.EndpointConfig `json:"grpc" yaml:"grpc"`
+	Tags     map[string]string           `json:"tags" yaml:"tags"`
+	Sampling OTLPSamplingConfig          `json:"sampling" yaml:"sampling"`
+	Batch    OTLPBatchConfig             `json:"batch" yaml:"batch"`
+}
+
+// NewOpenTelemetryCollectorConfig creates an OpenTelemetryCollectorConfig
+// struct with default values.
+func NewOpenTelemetryCollectorConfig() OpenTelemetryCollectorConfig {
+	return OpenTelemetryCollectorConfig{
+		HTTP: []otlpconfig.EndpointConfig{},
+		GRPC: []otlpconfig.EndpointConfig{},
+		Tags: map[string]string{},
+		Sampling: OTLPSamplingConfig{
+			Enabled: false,
+			Ratio:   1.0,
+		},
+		Batch: OTLPBatchConfig{
+			MaxQueueSize:       2048,
+			MaxExportBatchSize: 512,
+			FlushInterval:      "5s",
+			ExportTimeout:      "30s",
+		},
+	}
+}
@@ -13,23 +13,29 @@ import (
 
 // ReadFileLinted will attempt to read a configuration file path into a
 // structure. Returns an array of lint messages or an error.
-func ReadFileLinted(path string, rejectDeprecated bool, config *Type) ([]string, error) {
+func ReadFileLinted(path string, lintCtx docs.LintContext, config *Type) ([]string, error) {
 	configBytes, lints, err := ReadFileEnvSwap(path)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := yaml.Unmarshal(configBytes, config); err != nil {
+	var rawNode yaml.Node
+	if err := yaml.Unmarshal(configBytes, &rawNode); err != nil {
 		return nil, err
 	}
-
-	lintCtx := docs.NewLintContext()
-	lintCtx.RejectDeprecated = rejectDeprecated
-	newLints, err := LintBytes(lintCtx, configBytes)
+	tmplLints, err := ExpandStreamTemplatesLinted(lintCtx, &rawNode)
 	if err != nil {
 		return nil, err
 	}
-	lints = append(lints, newLints...)
+	if err := rawNode.Decode(config); err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(configBytes, []byte("# BENTHOS LINT DISABLE")) {
+		return lints, nil
+	}
+
+	lints = append(lints, lintNode(lintCtx, tmplLints, &rawNode)...)
 	return lints, nil
 }
 
@@ -44,14 +50,20 @@ func LintBytes(ctx docs.LintContext, rawBytes []byte) ([]string, error) {
 	if err := yaml.Unmarshal(rawBytes, &rawNode); err != nil {
 		return nil, err
 	}
+	tmplLints, err := ExpandStreamTemplatesLinted(ctx, &rawNode)
+	if err != nil {
+		return nil, err
+	}
+	return lintNode(ctx, tmplLints, &rawNode), nil
+}
 
-	var lintStrs []string
-	for _, lint := range Spec().LintYAML(ctx, &rawNode) {
+func lintNode(ctx docs.LintContext, tmplLints []docs.Lint, rawNode *yaml.Node) (lintStrs []string) {
+	for _, lint := range append(tmplLints, Spec().LintYAML(ctx, rawNode)...) {
 		if lint.Level == docs.LintError {
 			lintStrs = append(lintStrs, fmt.Sprintf("line %v: %v", lint.Line, lint.What))
 		}
 	}
-	return lintStrs, nil
+	return
 }
 
 // ReadFileEnvSwap reads a file and replaces any environment variable
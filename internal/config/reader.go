@@ -64,8 +64,13 @@ type Reader struct {
 
 	mainUpdateFn   MainUpdateFunc
 	streamUpdateFn StreamUpdateFunc
+	streamRemoveFn StreamRemoveFunc
 	watcher        *fsnotify.Watcher
 
+	// Directories of stream configs being watched, where files can be added
+	// and removed.
+	streamDirs []string
+
 	changeFlushPeriod time.Duration
 	changeDelayPeriod time.Duration
 }
@@ -180,12 +185,37 @@ func (r *Reader) SubscribeStreamChanges(fn StreamUpdateFunc) error {
 	return nil
 }
 
+// StreamRemoveFunc is a closure function called whenever a stream config has
+// been removed. A boolean should be returned indicating whether the stream was
+// successfully removed, if false then the attempt will be made again after a
+// grace period.
+type StreamRemoveFunc func(id string) bool
+
+// SubscribeStreamRemovals registers a closure to be called whenever the config
+// file of a stream within a watched directory is removed.
+//
+// The provided closure should return true if the stream was successfully
+// removed.
+func (r *Reader) SubscribeStreamRemovals(fn StreamRemoveFunc) error {
+	if r.watcher != nil {
+		return errors.New("a file watcher has already been started")
+	}
+
+	r.streamRemoveFn = fn
+	return nil
+}
+
 // BeginFileWatching creates a goroutine that watches all active configuration
 // files for changes. If a resource is changed then it is swapped out
 // automatically through the provided manager. If a main config or stream config
 // changes then the closures registered with either SubscribeConfigChanges or
 // SubscribeStreamChanges will be called.
 //
+// Directories of stream configs are watched recursively, where stream config
+// files that are added are passed to the closure registered with
+// SubscribeStreamChanges, and stream config files that are removed are passed
+// to the closure registered with SubscribeStreamRemovals.
+//
 // WARNING: Either SubscribeConfigChanges or SubscribeStreamChanges must be
 // called before this, as otherwise it is unsafe to register them during
 // watching.
@@ -197,6 +227,11 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 		return errors.New("a file watcher cannot be started without a subscription function registered")
 	}
 
+	streamFiles, err := r.resolveStreamDirs()
+	if err != nil {
+		return err
+	}
+
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return err
@@ -215,14 +250,32 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 				if !ok {
 					return
 				}
+				nameClean := filepath.Clean(event.Name)
+				_, inStreamDir := r.streamDirOf(nameClean)
 				switch {
 				case event.Op&fsnotify.Write == fsnotify.Write:
-					collapsedChanges[filepath.Clean(event.Name)] = time.Now()
+					collapsedChanges[nameClean] = time.Now()
+
+				case event.Op&fsnotify.Create == fsnotify.Create:
+					if !inStreamDir {
+						continue
+					}
+					if err := r.watchStreamDirPath(watcher, nameClean, func(path string) {
+						collapsedChanges[path] = time.Now()
+					}); err != nil {
+						mgr.Logger().Errorf("Failed to watch new stream config path %v: %v", nameClean, err)
+					}
 
 				case event.Op&fsnotify.Remove == fsnotify.Remove ||
 					event.Op&fsnotify.Rename == fsnotify.Rename:
 					_ = watcher.Remove(event.Name)
-					lostNames[filepath.Clean(event.Name)] = struct{}{}
+					if inStreamDir {
+						// Files within watched directories are checked for
+						// their existence once the change is flushed.
+						collapsedChanges[nameClean] = time.Now()
+					} else {
+						lostNames[nameClean] = struct{}{}
+					}
 				}
 			case <-ticker.C:
 				for nameClean, changed := range collapsedChanges {
@@ -232,6 +285,8 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 					var succeeded bool
 					if nameClean == filepath.Clean(r.mainPath) {
 						succeeded = r.reactMainUpdate(mgr, strict)
+					} else if dir, inStreamDir := r.streamDirOf(nameClean); inStreamDir {
+						succeeded = r.reactStreamDirChange(mgr, strict, dir, nameClean)
 					} else if _, exists := r.streamFileInfo[nameClean]; exists {
 						succeeded = r.reactStreamUpdate(mgr, strict, nameClean)
 					} else {
@@ -264,12 +319,18 @@ func (r *Reader) BeginFileWatching(mgr bundle.NewManagement, strict bool) error
 			return err
 		}
 	}
-	for _, p := range r.streamsPaths {
+	for _, p := range streamFiles {
 		if err := watcher.Add(p); err != nil {
 			_ = watcher.Close()
 			return err
 		}
 	}
+	for _, p := range r.streamDirs {
+		if err := r.watchStreamDirPath(watcher, p, nil); err != nil {
+			_ = watcher.Close()
+			return err
+		}
+	}
 	for _, p := range r.resourcePaths {
 		if err := watcher.Add(p); err != nil {
 			_ = watcher.Close()
@@ -326,6 +387,7 @@ func (r *Reader) readMain(conf *Type) (lints []string, err error) {
 
 	var rawNode yaml.Node
 	var confBytes []byte
+	var tmplLints []docs.Lint
 	if r.mainPath != "" {
 		if confBytes, lints, err = ReadFileEnvSwap(r.mainPath); err != nil {
 			return
@@ -333,6 +395,9 @@ func (r *Reader) readMain(conf *Type) (lints []string, err error) {
 		if err = yaml.Unmarshal(confBytes, &rawNode); err != nil {
 			return
 		}
+		if tmplLints, err = ExpandStreamTemplatesLinted(docs.NewLintContext(), &rawNode); err != nil {
+			return
+		}
 	}
 
 	// This is an unlikely race condition as the file could've been updated
@@ -357,7 +422,7 @@ func (r *Reader) readMain(conf *Type) (lints []string, err error) {
 		if r.mainPath != "" {
 			lintFilePrefix = fmt.Sprintf("%v: ", r.mainPath)
 		}
-		for _, lint := range confSpec.LintYAML(docs.NewLintContext(), &rawNode) {
+		for _, lint := range append(tmplLints, confSpec.LintYAML(docs.NewLintContext(), &rawNode)...) {
 			lints = append(lints, fmt.Sprintf("%vline %v: %v", lintFilePrefix, lint.Line, lint.What))
 		}
 	}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -127,3 +128,91 @@ output:
 	assert.Equal(t, "kafka", updatedConf.Input.Type)
 	assert.Equal(t, "aws_s3", updatedConf.Output.Type)
 }
+
+func TestReaderStreamDirectoryWatching(t *testing.T) {
+	streamConf := func(value string) []byte {
+		return []byte(`
+pipeline:
+  processors:
+    - bloblang: 'root = "` + value + `"'
+`)
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "first.yaml"), streamConf("first"), 0o644))
+
+	rdr := NewReader("", nil, OptSetStreamPaths(dir))
+	rdr.changeDelayPeriod = 50 * time.Millisecond
+	rdr.changeFlushPeriod = 1 * time.Millisecond
+
+	streamConfs := map[string]stream.Config{}
+	_, err := rdr.ReadStreams(streamConfs)
+	require.NoError(t, err)
+	require.Contains(t, streamConfs, "first")
+
+	type streamChange struct {
+		id      string
+		mapping string
+		removed bool
+	}
+	changeChan := make(chan streamChange, 10)
+	require.NoError(t, rdr.SubscribeStreamChanges(func(id string, conf stream.Config) bool {
+		change := streamChange{id: id}
+		if len(conf.Pipeline.Processors) > 0 {
+			change.mapping = conf.Pipeline.Processors[0].Bloblang
+		}
+		changeChan <- change
+		return true
+	}))
+	require.NoError(t, rdr.SubscribeStreamRemovals(func(id string) bool {
+		changeChan <- streamChange{id: id, removed: true}
+		return true
+	}))
+
+	testMgr, err := manager.NewV2(manager.NewResourceConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+	require.NoError(t, rdr.BeginFileWatching(testMgr, true))
+	t.Cleanup(func() {
+		_ = rdr.Close(context.Background())
+	})
+
+	expectChange := func(exp streamChange) {
+		t.Helper()
+		select {
+		case change := <-changeChan:
+			assert.Equal(t, exp, change)
+		case <-time.After(time.Second):
+			require.FailNow(t, "Expected a stream change to be triggered", "%v", exp)
+		}
+	}
+
+	// A new file within a new sub-directory creates a stream.
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "nested", "second.yaml"), streamConf("second"), 0o644))
+	expectChange(streamChange{id: "nested_second", mapping: `root = "second"`})
+
+	// A changed file updates a stream.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "first.yaml"), streamConf("first updated"), 0o644))
+	expectChange(streamChange{id: "first", mapping: `root = "first updated"`})
+
+	// A new file with lint errors is rejected without affecting others.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(`
+nope: this is not a field
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "third.yaml"), streamConf("third"), 0o644))
+	expectChange(streamChange{id: "third", mapping: `root = "third"`})
+
+	// A removed file removes a stream.
+	require.NoError(t, os.Remove(filepath.Join(dir, "first.yaml")))
+	expectChange(streamChange{id: "first", removed: true})
+
+	// A removed directory removes the streams within it.
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "nested")))
+	expectChange(streamChange{id: "nested_second", removed: true})
+
+	select {
+	case change := <-changeChan:
+		t.Errorf("Unexpected stream change: %v", change)
+	case <-time.After(time.Millisecond * 200):
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"gopkg.in/yaml.v3"
 
 	"github.com/benthosdev/benthos/v4/internal/bundle"
@@ -54,12 +55,16 @@ func ReadStreamFile(path string) (conf stream.Config, lints []string, err error)
 	if err = yaml.Unmarshal(confBytes, &rawNode); err != nil {
 		return
 	}
+	var tmplLints []docs.Lint
+	if tmplLints, err = ExpandStreamTemplatesLinted(docs.NewLintContext(), &rawNode); err != nil {
+		return
+	}
 
 	confSpec := stream.Spec()
 	confSpec = append(confSpec, tdocs.ConfigSpec())
 
 	if !bytes.HasPrefix(confBytes, []byte("# BENTHOS LINT DISABLE")) {
-		for _, lint := range confSpec.LintYAML(docs.NewLintContext(), &rawNode) {
+		for _, lint := range append(tmplLints, confSpec.LintYAML(docs.NewLintContext(), &rawNode)...) {
 			lints = append(lints, fmt.Sprintf("%v: line %v: %v", path, lint.Line, lint.What))
 		}
 	}
@@ -173,3 +178,126 @@ func (r *Reader) reactStreamUpdate(mgr bundle.NewManagement, strict bool, path s
 
 	return r.streamUpdateFn(info.id, conf)
 }
+
+// resolveStreamDirs resolves the stream paths of the reader into directories,
+// which are stored for watching, and returns the remaining file paths.
+func (r *Reader) resolveStreamDirs() (files []string, err error) {
+	streamsPaths, err := ifilepath.Globs(r.streamsPaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve stream glob pattern: %w", err)
+	}
+
+	r.streamDirs = nil
+	for _, target := range streamsPaths {
+		target = filepath.Clean(target)
+		if info, err := os.Stat(target); err == nil && info.IsDir() {
+			r.streamDirs = append(r.streamDirs, target)
+		} else {
+			files = append(files, target)
+		}
+	}
+	return files, nil
+}
+
+// streamDirOf returns the watched stream directory that contains a path, and
+// false if the path is not within a watched stream directory.
+func (r *Reader) streamDirOf(path string) (string, bool) {
+	for _, dir := range r.streamDirs {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func isStreamFileName(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// watchStreamDirPath adds a path within a stream directory to a watcher. When
+// the path is a directory all sub-directories are watched as well, and the
+// provided closure (when non-nil) is called with any stream config files found
+// within it.
+func (r *Reader) watchStreamDirPath(watcher *fsnotify.Watcher, path string, newFileFn func(path string)) error {
+	return filepath.Walk(path, func(p string, info os.FileInfo, werr error) error {
+		if werr != nil {
+			if os.IsNotExist(werr) {
+				// The path has already been removed, which is picked up by a
+				// subsequent event.
+				return nil
+			}
+			return werr
+		}
+		if info.IsDir() {
+			return watcher.Add(p)
+		}
+		if newFileFn != nil && isStreamFileName(info.Name()) {
+			newFileFn(filepath.Clean(p))
+		}
+		return nil
+	})
+}
+
+// reactStreamDirChange reacts to a change of a path within a watched stream
+// directory, where new stream config files result in streams being created,
+// changed files result in streams being updated, and removed files (or
+// directories) result in streams being removed.
+func (r *Reader) reactStreamDirChange(mgr bundle.NewManagement, strict bool, dir, path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			mgr.Logger().Errorf("Failed to read stream config path %v: %v", path, err)
+			return true
+		}
+		return r.reactStreamRemoved(mgr, path)
+	}
+	if info.IsDir() || !isStreamFileName(info.Name()) {
+		return true
+	}
+
+	if _, exists := r.streamFileInfo[path]; !exists {
+		id, err := InferStreamID(dir, path)
+		if err != nil {
+			mgr.Logger().Errorf("Failed to infer stream id of new config %v: %v", path, err)
+			return true
+		}
+
+		// Do not run unit test files
+		if len(r.testSuffix) > 0 && strings.HasSuffix(id, r.testSuffix) {
+			return true
+		}
+
+		for otherPath, otherInfo := range r.streamFileInfo {
+			if otherInfo.id == id {
+				mgr.Logger().Errorf("Rejecting new stream config %v as its id (%v) collides with file: %v", path, id, otherPath)
+				return true
+			}
+		}
+
+		strmInfo := streamFileInfo{id: id}
+		strmInfo.updatedAt = time.Now()
+		r.streamFileInfo[path] = strmInfo
+	}
+
+	return r.reactStreamUpdate(mgr, strict, path)
+}
+
+// reactStreamRemoved removes the streams read from a path that no longer
+// exists, or from any files within it when the path was a directory.
+func (r *Reader) reactStreamRemoved(mgr bundle.NewManagement, path string) bool {
+	succeeded := true
+	for filePath, info := range r.streamFileInfo {
+		if filePath != path && !strings.HasPrefix(filePath, path+string(filepath.Separator)) {
+			continue
+		}
+		if r.streamRemoveFn != nil {
+			mgr.Logger().Infof("Stream %v config removed, attempting to remove stream.", info.id)
+			if !r.streamRemoveFn(info.id) {
+				succeeded = false
+				continue
+			}
+		}
+		delete(r.streamFileInfo, filePath)
+	}
+	return succeeded
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+// StreamTemplateExpandFunc expands the config of a stream template into the
+// fields of a stream config (input, buffer, pipeline and output).
+type StreamTemplateExpandFunc func(node *yaml.Node) (*yaml.Node, error)
+
+type streamTemplate struct {
+	spec docs.FieldSpec
+	fn   StreamTemplateExpandFunc
+}
+
+var streamTemplates = struct {
+	tmpls map[string]streamTemplate
+	sync.RWMutex
+}{
+	tmpls: map[string]streamTemplate{},
+}
+
+// RegisterStreamTemplate adds a stream template, which allows configs to
+// specify a field of the same name at the root level that is expanded into a
+// full stream config. The field is linted against the provided spec.
+func RegisterStreamTemplate(name string, spec docs.FieldSpec, fn StreamTemplateExpandFunc) error {
+	for _, f := range Spec() {
+		if f.Name == name {
+			return fmt.Errorf("stream template name '%v' collides with an existing config field", name)
+		}
+	}
+
+	streamTemplates.Lock()
+	streamTemplates.tmpls[name] = streamTemplate{spec: spec, fn: fn}
+	streamTemplates.Unlock()
+	return nil
+}
+
+// ExpandStreamTemplates walks the root level fields of a config and replaces a
+// field named after a registered stream template with the stream config fields
+// that the template expands into. Only one stream template can be used within
+// a config, and the fields it expands into must not also be set explicitly.
+func ExpandStreamTemplates(root *yaml.Node) error {
+	_, err := ExpandStreamTemplatesLinted(docs.NewLintContext(), root)
+	return err
+}
+
+// ExpandStreamTemplatesLinted expands a stream template in the same way as
+// ExpandStreamTemplates, and also returns linting errors for the fields of the
+// template, as they are no longer present once it has been expanded.
+func ExpandStreamTemplatesLinted(ctx docs.LintContext, root *yaml.Node) ([]docs.Lint, error) {
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	streamTemplates.RLock()
+	defer streamTemplates.RUnlock()
+
+	tmplIndex := -1
+	for i := 0; i < len(root.Content)-1; i += 2 {
+		if _, exists := streamTemplates.tmpls[root.Content[i].Value]; !exists {
+			continue
+		}
+		if tmplIndex >= 0 {
+			return nil, fmt.Errorf("line %v: only one stream template can be used within a config, found both %v and %v", root.Content[i].Line, root.Content[tmplIndex].Value, root.Content[i].Value)
+		}
+		tmplIndex = i
+	}
+	if tmplIndex < 0 {
+		return nil, nil
+	}
+
+	name := root.Content[tmplIndex].Value
+	tmpl := streamTemplates.tmpls[name]
+
+	lints := tmpl.spec.LintYAML(ctx, root.Content[tmplIndex+1])
+
+	expanded, err := tmpl.fn(root.Content[tmplIndex+1])
+	if err != nil {
+		return nil, fmt.Errorf("line %v: stream template %v: %w", root.Content[tmplIndex].Line, name, err)
+	}
+	if expanded.Kind == yaml.DocumentNode && len(expanded.Content) > 0 {
+		expanded = expanded.Content[0]
+	}
+	if expanded.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %v: stream template %v: expected an object, got %v", root.Content[tmplIndex].Line, name, expanded.Tag)
+	}
+
+	for i := 0; i < len(expanded.Content)-1; i += 2 {
+		for j := 0; j < len(root.Content)-1; j += 2 {
+			if root.Content[j].Value == expanded.Content[i].Value {
+				return nil, fmt.Errorf("line %v: field %v cannot be set alongside stream template %v", root.Content[j].Line, root.Content[j].Value, name)
+			}
+		}
+	}
+
+	newContent := make([]*yaml.Node, 0, len(root.Content)-2+len(expanded.Content))
+	newContent = append(newContent, root.Content[:tmplIndex]...)
+	newContent = append(newContent, expanded.Content...)
+	newContent = append(newContent, root.Content[tmplIndex+2:]...)
+	root.Content = newContent
+	return lints, nil
+}
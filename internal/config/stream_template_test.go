@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+)
+
+func TestStreamTemplateExpansion(t *testing.T) {
+	require.NoError(t, config.RegisterStreamTemplate("test_generate_to_stdout", docs.FieldComponent().WithChildren(
+		docs.FieldString("text", "").HasDefault(""),
+		docs.FieldInt("count", "").HasDefault(0),
+	), func(node *yaml.Node) (*yaml.Node, error) {
+		var fields struct {
+			Text string `yaml:"text"`
+		}
+		if err := node.Decode(&fields); err != nil {
+			return nil, err
+		}
+		var result yaml.Node
+		err := result.Encode(map[string]interface{}{
+			"input": map[string]interface{}{
+				"generate": map[string]interface{}{
+					"mapping": `root = "` + fields.Text + `"`,
+				},
+			},
+			"output": map[string]interface{}{
+				"stdout": map[string]interface{}{},
+			},
+		})
+		return &result, err
+	}))
+
+	require.Error(t, config.RegisterStreamTemplate("input", docs.FieldComponent(), nil))
+
+	dir := t.TempDir()
+
+	goodPath := filepath.Join(dir, "good.yaml")
+	require.NoError(t, os.WriteFile(goodPath, []byte(`
+test_generate_to_stdout:
+  text: hello world
+pipeline:
+  processors:
+    - bloblang: 'root = content().uppercase()'
+`), 0o644))
+
+	conf, lints, err := config.ReadStreamFile(goodPath)
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+	assert.Equal(t, "generate", conf.Input.Type)
+	assert.Equal(t, `root = "hello world"`, conf.Input.Generate.Mapping)
+	assert.Equal(t, "stdout", conf.Output.Type)
+	require.Len(t, conf.Pipeline.Processors, 1)
+	assert.Equal(t, "bloblang", conf.Pipeline.Processors[0].Type)
+
+	conflictPath := filepath.Join(dir, "conflict.yaml")
+	require.NoError(t, os.WriteFile(conflictPath, []byte(`
+test_generate_to_stdout:
+  text: hello world
+output:
+  drop: {}
+`), 0o644))
+
+	_, _, err = config.ReadStreamFile(conflictPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field output cannot be set alongside stream template test_generate_to_stdout")
+
+	typoPath := filepath.Join(dir, "typo.yaml")
+	require.NoError(t, os.WriteFile(typoPath, []byte(`
+test_generate_to_stdout:
+  text: hello world
+  cuont: 3
+`), 0o644))
+
+	_, lints, err = config.ReadStreamFile(typoPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{typoPath + ": line 4: field cuont not recognised"}, lints)
+
+	lints, err = config.LintBytes(docs.NewLintContext(), []byte(`
+test_generate_to_stdout:
+  count: [ 3 ]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 3: expected int value"}, lints)
+}
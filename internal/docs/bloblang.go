@@ -19,9 +19,22 @@ func LintBloblangMapping(ctx LintContext, line, col int, v interface{}) []Lint {
 	if str == "" {
 		return nil
 	}
-	_, err := ctx.BloblangEnv.NewMapping(str)
+	exec, err := ctx.BloblangEnv.NewMapping(str)
 	if err == nil {
-		return nil
+		if !ctx.BloblangAnalysis {
+			return nil
+		}
+		var lints []Lint
+		for _, l := range exec.Lint() {
+			if l.Line == 0 {
+				lints = append(lints, NewLintError(line, l.What))
+				continue
+			}
+			lint := NewLintError(line+l.Line-1, l.What)
+			lint.Column = col + l.Column
+			lints = append(lints, lint)
+		}
+		return lints
 	}
 	if mErr, ok := err.(*parser.Error); ok {
 		bline, bcol := parser.LineAndColOf([]rune(str), mErr.Input)
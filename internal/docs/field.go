@@ -563,6 +563,10 @@ type LintContext struct {
 
 	// Reject any deprecated components or fields as linting errors.
 	RejectDeprecated bool
+
+	// Report problems found by a static analysis of Bloblang mappings as
+	// linting errors.
+	BloblangAnalysis bool
 }
 
 // NewLintContext creates a new linting context.
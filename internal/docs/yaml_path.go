@@ -374,3 +374,74 @@ func (f FieldSpec) YAMLLabelsToPaths(docsProvider Provider, node *yaml.Node, lab
 		}
 	}
 }
+
+// YAMLComponentPaths walks a YAML tree using a field spec as a reference point
+// and returns the path of each component of a given type within the tree,
+// including components nested within other components. Paths are returned in
+// the order that they are walked, and therefore a component always precedes
+// the components nested within it.
+func (f FieldSpecs) YAMLComponentPaths(docsProvider Provider, cType Type, node *yaml.Node, path []string) [][]string {
+	node = unwrapDocumentNode(node)
+
+	fieldMap := map[string]FieldSpec{}
+	for _, spec := range f {
+		fieldMap[spec.Name] = spec
+	}
+
+	var paths [][]string
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		key := node.Content[i].Value
+		if spec, exists := fieldMap[key]; exists {
+			paths = append(paths, spec.YAMLComponentPaths(docsProvider, cType, node.Content[i+1], append(path, key))...)
+		}
+	}
+	return paths
+}
+
+// YAMLComponentPaths walks a YAML tree using a field spec as a reference point
+// and returns the path of each component of a given type within the tree,
+// including components nested within other components.
+func (f FieldSpec) YAMLComponentPaths(docsProvider Provider, cType Type, node *yaml.Node, path []string) [][]string {
+	node = unwrapDocumentNode(node)
+
+	var paths [][]string
+	switch f.Kind {
+	case Kind2DArray:
+		nextSpec := f.Array()
+		for i, child := range node.Content {
+			paths = append(paths, nextSpec.YAMLComponentPaths(docsProvider, cType, child, append(path, strconv.Itoa(i)))...)
+		}
+	case KindArray:
+		nextSpec := f.Scalar()
+		for i, child := range node.Content {
+			paths = append(paths, nextSpec.YAMLComponentPaths(docsProvider, cType, child, append(path, strconv.Itoa(i)))...)
+		}
+	case KindMap:
+		nextSpec := f.Scalar()
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			key := node.Content[i].Value
+			paths = append(paths, nextSpec.YAMLComponentPaths(docsProvider, cType, node.Content[i+1], append(path, key))...)
+		}
+	default:
+		if coreType, isCore := f.Type.IsCoreComponent(); isCore {
+			if coreType == cType {
+				pathCopy := make([]string, len(path))
+				copy(pathCopy, path)
+				paths = append(paths, pathCopy)
+			}
+			coreFields := FieldSpecs{}
+			for _, f := range ReservedFieldsByType(coreType) {
+				coreFields = append(coreFields, f)
+			}
+			if inferred, cSpec, err := GetInferenceCandidateFromYAML(docsProvider, coreType, node); err == nil {
+				conf := cSpec.Config
+				conf.Name = inferred
+				coreFields = append(coreFields, conf)
+			}
+			paths = append(paths, coreFields.YAMLComponentPaths(docsProvider, cType, node, path)...)
+		} else if len(f.Children) > 0 {
+			paths = append(paths, f.Children.YAMLComponentPaths(docsProvider, cType, node, path)...)
+		}
+	}
+	return paths
+}
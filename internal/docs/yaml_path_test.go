@@ -835,3 +835,66 @@ pipeline:
 		})
 	}
 }
+
+func TestYAMLComponentPaths(t *testing.T) {
+	mockProv := docs.NewMappedDocsProvider()
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "nats",
+		Type: docs.TypeOutput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("subject", ""),
+		),
+	})
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "fan_out",
+		Type: docs.TypeOutput,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldOutput("outputs", "").Array(),
+		),
+	})
+	mockProv.RegisterDocs(docs.ComponentSpec{
+		Name: "compress",
+		Type: docs.TypeProcessor,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldString("algorithm", ""),
+		),
+	})
+
+	spec := docs.FieldSpecs{
+		docs.FieldOutput("output", ""),
+		docs.FieldOutput("output_resources", "").Array(),
+	}
+
+	var input yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+output:
+  fan_out:
+    outputs:
+      - nats:
+          subject: foo
+      - fan_out:
+          outputs:
+            - nats:
+                subject: bar
+  processors:
+    - compress:
+        algorithm: nahm8
+
+output_resources:
+  - label: baz
+    nats:
+      subject: baz
+`), &input))
+
+	assert.Equal(t, [][]string{
+		{"output"},
+		{"output", "fan_out", "outputs", "0"},
+		{"output", "fan_out", "outputs", "1"},
+		{"output", "fan_out", "outputs", "1", "fan_out", "outputs", "0"},
+		{"output_resources", "0"},
+	}, spec.YAMLComponentPaths(mockProv, docs.TypeOutput, &input, nil))
+
+	assert.Equal(t, [][]string{
+		{"output", "processors", "0"},
+	}, spec.YAMLComponentPaths(mockProv, docs.TypeProcessor, &input, nil))
+}
@@ -266,32 +266,34 @@ func (a *amqp09Reader) disconnect() error {
 //------------------------------------------------------------------------------
 
 func amqpSetMetadata(p *message.Part, k string, v interface{}) {
-	var metaValue string
+	var metaValue interface{}
 	var metaKey = strings.ReplaceAll(k, "-", "_")
 
 	switch v := v.(type) {
 	case bool:
-		metaValue = strconv.FormatBool(v)
+		metaValue = v
 	case float32:
-		metaValue = strconv.FormatFloat(float64(v), 'f', -1, 32)
+		metaValue = float64(v)
 	case float64:
-		metaValue = strconv.FormatFloat(v, 'f', -1, 64)
+		metaValue = v
 	case byte:
-		metaValue = strconv.Itoa(int(v))
+		metaValue = int64(v)
 	case int16:
-		metaValue = strconv.Itoa(int(v))
+		metaValue = int64(v)
 	case int32:
-		metaValue = strconv.Itoa(int(v))
+		metaValue = int64(v)
 	case int64:
-		metaValue = strconv.Itoa(int(v))
-	case nil:
-		metaValue = ""
-	case string:
 		metaValue = v
+	case string:
+		if v != "" {
+			metaValue = v
+		}
 	case []byte:
-		metaValue = string(v)
+		if len(v) > 0 {
+			metaValue = v
+		}
 	case time.Time:
-		metaValue = v.Format(time.RFC3339)
+		metaValue = v
 	case amqp.Decimal:
 		dec := strconv.Itoa(int(v.Value))
 		index := len(dec) - int(v.Scale)
@@ -301,12 +303,10 @@ func amqpSetMetadata(p *message.Part, k string, v interface{}) {
 			amqpSetMetadata(p, metaKey+"_"+key, value)
 		}
 		return
-	default:
-		metaValue = ""
 	}
 
-	if metaValue != "" {
-		p.MetaSet(metaKey, metaValue)
+	if metaValue != nil {
+		p.MetaSetMut(metaKey, metaValue)
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
@@ -258,8 +260,8 @@ func (a *amqp09Writer) WriteWithContext(ctx context.Context, msg *message.Batch)
 		}
 
 		headers := amqp.Table{}
-		_ = a.metaFilter.Iter(p, func(k, v string) error {
-			headers[strings.ReplaceAll(k, "_", "-")] = v
+		_ = a.metaFilter.IterMut(p, func(k string, v interface{}) error {
+			headers[strings.ReplaceAll(k, "_", "-")] = amqpHeaderValue(v)
 			return nil
 		})
 
@@ -355,3 +357,31 @@ func (a *amqp09Writer) dial(amqpURL string) (conn *amqp.Connection, err error) {
 
 	return conn, nil
 }
+
+// amqpHeaderValue converts a metadata value into a type supported by AMQP
+// header tables, falling back to a string representation for all other types.
+func amqpHeaderValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string, []byte, bool, int64, float64, time.Time:
+		return t
+	case int:
+		return int64(t)
+	case uint64:
+		if t <= math.MaxInt64 {
+			return int64(t)
+		}
+	case map[string]interface{}:
+		table := make(amqp.Table, len(t))
+		for k, v := range t {
+			table[k] = amqpHeaderValue(v)
+		}
+		return table
+	case []interface{}:
+		values := make([]interface{}, len(t))
+		for i, v := range t {
+			values[i] = amqpHeaderValue(v)
+		}
+		return values
+	}
+	return query.IToString(v)
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -250,10 +249,8 @@ func (a *amqp1Reader) ReadWithContext(ctx context.Context) (*message.Batch, read
 	}
 	if amqpMsg.Annotations != nil {
 		for k, v := range amqpMsg.Annotations {
-			keyStr, keyIsStr := k.(string)
-			valStr, valIsStr := v.(string)
-			if keyIsStr && valIsStr {
-				amqpSetMetadata(part, keyStr, valStr)
+			if keyStr, keyIsStr := k.(string); keyIsStr {
+				amqpSetMetadata(part, keyStr, v)
 			}
 		}
 	}
@@ -414,37 +411,37 @@ func (a *amqp1Reader) renewWithContext(ctx context.Context, msg *amqp.Message) (
 }
 
 func amqpSetMetadata(p *message.Part, k string, v interface{}) {
-	var metaValue string
+	var metaValue interface{}
 	var metaKey = strings.ReplaceAll(k, "-", "_")
 
 	switch v := v.(type) {
 	case bool:
-		metaValue = strconv.FormatBool(v)
+		metaValue = v
 	case float32:
-		metaValue = strconv.FormatFloat(float64(v), 'f', -1, 32)
+		metaValue = float64(v)
 	case float64:
-		metaValue = strconv.FormatFloat(v, 'f', -1, 64)
+		metaValue = v
 	case byte:
-		metaValue = strconv.Itoa(int(v))
+		metaValue = int64(v)
 	case int16:
-		metaValue = strconv.Itoa(int(v))
+		metaValue = int64(v)
 	case int32:
-		metaValue = strconv.Itoa(int(v))
+		metaValue = int64(v)
 	case int64:
-		metaValue = strconv.Itoa(int(v))
-	case nil:
-		metaValue = ""
-	case string:
 		metaValue = v
+	case string:
+		if v != "" {
+			metaValue = v
+		}
 	case []byte:
-		metaValue = string(v)
+		if len(v) > 0 {
+			metaValue = v
+		}
 	case time.Time:
-		metaValue = v.Format(time.RFC3339)
-	default:
-		metaValue = ""
+		metaValue = v
 	}
 
-	if metaValue != "" {
-		p.MetaSet(metaKey, metaValue)
+	if metaValue != nil {
+		p.MetaSetMut(metaKey, metaValue)
 	}
 }
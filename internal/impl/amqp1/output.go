@@ -9,6 +9,7 @@ import (
 
 	"github.com/Azure/go-amqp"
 
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
@@ -191,11 +192,16 @@ func (a *amqp1Writer) WriteWithContext(ctx context.Context, msg *message.Batch)
 
 	return writer.IterateBatchedSend(msg, func(i int, p *message.Part) error {
 		m := amqp.NewMessage(p.Get())
-		_ = a.metaFilter.Iter(p, func(k, v string) error {
+		_ = a.metaFilter.IterMut(p, func(k string, v interface{}) error {
 			if m.Annotations == nil {
 				m.Annotations = amqp.Annotations{}
 			}
-			m.Annotations[k] = v
+			switch v.(type) {
+			case string, []byte, bool, int64, float64, time.Time:
+				m.Annotations[k] = v
+			default:
+				m.Annotations[k] = query.IToString(v)
+			}
 			return nil
 		})
 		err := s.Send(ctx, m)
@@ -18,6 +18,7 @@ import (
 
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/docs"
@@ -48,6 +49,8 @@ Consumes messages from one or more Kinesis streams either by automatically balan
 
 Benthos will not store a consumed sequence unless it is acknowledged at the output level, which ensures at-least-once delivery guarantees.
 
+Alternatively, the latest consumed sequence of each shard can be stored within a [cache resource](/docs/components/caches/about) by setting the field ` + "`checkpoint_cache`" + `, in which case the DynamoDB table is not used. Since a cache cannot be used for coordination only a single instance of the input may consume the streams when a cache is used, as multiple instances sharing a cache would each consume every shard.
+
 ### Ordering
 
 By default messages of a shard can be processed in parallel, up to a limit determined by the field ` + "`checkpoint_limit`" + `. However, if strict ordered processing is required then this value must be set to 1 in order to process shard messages in lock-step. When doing so it is recommended that you perform batching at this component for performance as it will not be possible to batch lock-stepped messages at the output level.
@@ -71,6 +74,7 @@ Use the ` + "`batching`" + ` fields to configure an optional [batching policy](/
 				docs.FieldInt("read_capacity_units", "Set the provisioned read capacity when creating the table with a `billing_mode` of `PROVISIONED`.").Advanced(),
 				docs.FieldInt("write_capacity_units", "Set the provisioned write capacity when creating the table with a `billing_mode` of `PROVISIONED`.").Advanced(),
 			),
+			docs.FieldString("checkpoint_cache", "An optional [cache resource](/docs/components/caches/about) in which to store the latest consumed sequence of each shard instead of the DynamoDB table. Only a single instance of this input may consume the streams when a cache is used, as shards cannot be balanced across multiple consumers.").Advanced(),
 			docs.FieldInt(
 				"checkpoint_limit", "The maximum gap between the in flight sequence versus the latest acknowledged sequence at a given time. Increasing this limit enables parallel processing and batching at the output level to work on individual shards. Any given sequence will not be committed unless all messages under that offset are delivered in order to preserve at least once delivery guarantees.",
 			),
@@ -113,7 +117,7 @@ type kinesisReader struct {
 	boffPool    sync.Pool
 
 	svc          kinesisiface.KinesisAPI
-	checkpointer *awsKinesisCheckpointer
+	checkpointer kinesisCheckpointer
 
 	streamShards    map[string][]string
 	balancedStreams []string
@@ -197,6 +201,9 @@ func newKinesisReader(conf oinput.AWSKinesisConfig, mgr interop.Manager) (*kines
 	if k.rebalancePeriod, err = time.ParseDuration(k.conf.RebalancePeriod); err != nil {
 		return nil, fmt.Errorf("failed to parse rebalance period string: %v", err)
 	}
+	if k.conf.CheckpointCache != "" && !mgr.ProbeCache(k.conf.CheckpointCache) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", k.conf.CheckpointCache)
+	}
 	return &k, nil
 }
 
@@ -369,10 +376,10 @@ func (k *kinesisReader) runConsumer(wg *sync.WaitGroup, streamID, shardID, start
 			}
 
 			wg.Done()
-			k.log.Debugf("Closing stream '%v' shard '%v' as client '%v'%v\n", streamID, shardID, k.checkpointer.clientID, reason)
+			k.log.Debugf("Closing stream '%v' shard '%v' as client '%v'%v\n", streamID, shardID, k.clientID, reason)
 		}()
 
-		k.log.Debugf("Consuming stream '%v' shard '%v' as client '%v'\n", streamID, shardID, k.checkpointer.clientID)
+		k.log.Debugf("Consuming stream '%v' shard '%v' as client '%v'\n", streamID, shardID, k.clientID)
 
 		// Switches our pull chan to unblocked only if it's currently blocked,
 		// as otherwise it's set to a timed channel that we do not want to
@@ -691,8 +698,10 @@ func (k *kinesisReader) ConnectWithContext(ctx context.Context) error {
 	}
 
 	svc := kinesis.New(sess)
-	checkpointer, err := newAWSKinesisCheckpointer(sess, k.clientID, k.conf.DynamoDB, k.leasePeriod, k.commitPeriod)
-	if err != nil {
+	var checkpointer kinesisCheckpointer
+	if k.conf.CheckpointCache != "" {
+		checkpointer = newAWSKinesisCacheCheckpointer(checkpoint.ManagerCacheAccess(k.mgr, k.conf.CheckpointCache), k.clientID, k.leasePeriod)
+	} else if checkpointer, err = newAWSKinesisCheckpointer(sess, k.clientID, k.conf.DynamoDB, k.leasePeriod, k.commitPeriod); err != nil {
 		return err
 	}
 
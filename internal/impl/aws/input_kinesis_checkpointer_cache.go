@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
+)
+
+// kinesisCheckpointer manages the shard checkpointing and claims for a given
+// client identifier.
+type kinesisCheckpointer interface {
+	AllClaims(ctx context.Context, streamID string) (map[string][]awsKinesisClientClaim, error)
+	Claim(ctx context.Context, streamID, shardID, fromClientID string) (string, error)
+	Checkpoint(ctx context.Context, streamID, shardID, sequenceNumber string, final bool) (bool, error)
+	Yield(ctx context.Context, streamID, shardID, sequenceNumber string) error
+	Delete(ctx context.Context, streamID, shardID string) error
+}
+
+// awsKinesisCacheCheckpointer stores the sequence of each shard within a cache
+// resource using a checkpoint.CacheStore per shard. Sequences are resolved by
+// the record batcher, and are therefore set directly rather than tracked by the
+// store.
+//
+// A cache offers no means of atomically acquiring or listing leases, and
+// therefore a shard is claimed by this process for as long as it holds the
+// store of that shard. This checkpointer only supports a single instance
+// consuming a given stream, as multiple instances sharing a cache would each
+// claim and consume every shard.
+type awsKinesisCacheCheckpointer struct {
+	access        checkpoint.CacheAccessFunc
+	clientID      string
+	leaseDuration time.Duration
+
+	storesMut sync.Mutex
+	stores    map[string]map[string]*checkpoint.CacheStore
+}
+
+func newAWSKinesisCacheCheckpointer(access checkpoint.CacheAccessFunc, clientID string, leaseDuration time.Duration) *awsKinesisCacheCheckpointer {
+	return &awsKinesisCacheCheckpointer{
+		access:        access,
+		clientID:      clientID,
+		leaseDuration: leaseDuration,
+		stores:        map[string]map[string]*checkpoint.CacheStore{},
+	}
+}
+
+func cacheCheckpointKey(streamID, shardID string) string {
+	return streamID + ":" + shardID
+}
+
+// claimedStore returns the store of a shard claimed by this client, creating
+// it when the shard is not yet claimed.
+func (k *awsKinesisCacheCheckpointer) claimedStore(streamID, shardID string) *checkpoint.CacheStore {
+	k.storesMut.Lock()
+	defer k.storesMut.Unlock()
+
+	shards, exists := k.stores[streamID]
+	if !exists {
+		shards = map[string]*checkpoint.CacheStore{}
+		k.stores[streamID] = shards
+	}
+	store, exists := shards[shardID]
+	if !exists {
+		// The capacity of the store is irrelevant as sequences are never
+		// tracked by it.
+		store = checkpoint.NewCacheStore(k.access, cacheCheckpointKey(streamID, shardID), 1)
+		shards[shardID] = store
+	}
+	return store
+}
+
+func (k *awsKinesisCacheCheckpointer) release(streamID, shardID string) {
+	k.storesMut.Lock()
+	defer k.storesMut.Unlock()
+	delete(k.stores[streamID], shardID)
+}
+
+// AllClaims returns the shards claimed by this client, which are the only
+// claims known to the checkpointer.
+func (k *awsKinesisCacheCheckpointer) AllClaims(ctx context.Context, streamID string) (map[string][]awsKinesisClientClaim, error) {
+	k.storesMut.Lock()
+	defer k.storesMut.Unlock()
+
+	clientClaims := map[string][]awsKinesisClientClaim{}
+	leaseTimeout := time.Now().Add(k.leaseDuration)
+	for shardID := range k.stores[streamID] {
+		clientClaims[k.clientID] = append(clientClaims[k.clientID], awsKinesisClientClaim{
+			ShardID:      shardID,
+			LeaseTimeout: leaseTimeout,
+		})
+	}
+	return clientClaims, nil
+}
+
+// Claim a shard and obtain the latest sequence stored for it. Stealing shards
+// from other clients is not supported.
+func (k *awsKinesisCacheCheckpointer) Claim(ctx context.Context, streamID, shardID, fromClientID string) (string, error) {
+	if fromClientID != "" && fromClientID != k.clientID {
+		return "", ErrLeaseNotAcquired
+	}
+
+	store := k.claimedStore(streamID, shardID)
+	sequence, _, err := store.Load(ctx)
+	if err != nil {
+		k.release(streamID, shardID)
+		return "", err
+	}
+	return sequence, nil
+}
+
+// Checkpoint sets a sequence number for a stream shard. The shard is always
+// still owned by this client.
+func (k *awsKinesisCacheCheckpointer) Checkpoint(ctx context.Context, streamID, shardID, sequenceNumber string, final bool) (bool, error) {
+	if err := k.claimedStore(streamID, shardID).Set(ctx, sequenceNumber); err != nil {
+		return false, err
+	}
+	if final {
+		k.release(streamID, shardID)
+	}
+	return true, nil
+}
+
+// Yield updates an existing checkpoint sequence number and releases the claim
+// of the shard.
+func (k *awsKinesisCacheCheckpointer) Yield(ctx context.Context, streamID, shardID, sequenceNumber string) error {
+	if err := k.claimedStore(streamID, shardID).Set(ctx, sequenceNumber); err != nil {
+		return err
+	}
+	k.release(streamID, shardID)
+	return nil
+}
+
+// Delete removes a checkpoint, this should be called when a shard is emptied.
+func (k *awsKinesisCacheCheckpointer) Delete(ctx context.Context, streamID, shardID string) error {
+	if err := k.claimedStore(streamID, shardID).Delete(ctx); err != nil {
+		return err
+	}
+	k.release(streamID, shardID)
+	return nil
+}
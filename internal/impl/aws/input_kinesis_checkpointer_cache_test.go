@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+)
+
+func TestKinesisCacheCheckpointer(t *testing.T) {
+	ctx := context.Background()
+
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	c := newAWSKinesisCacheCheckpointer(checkpoint.ManagerCacheAccess(mgr, "foocache"), "foo", time.Minute)
+
+	claims, err := c.AllClaims(ctx, "stream")
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+
+	seq, err := c.Claim(ctx, "stream", "0", "")
+	require.NoError(t, err)
+	assert.Equal(t, "", seq)
+
+	claims, err = c.AllClaims(ctx, "stream")
+	require.NoError(t, err)
+	require.Len(t, claims["foo"], 1)
+	assert.Equal(t, "0", claims["foo"][0].ShardID)
+
+	_, err = c.Claim(ctx, "stream", "0", "bar")
+	assert.Equal(t, ErrLeaseNotAcquired, err)
+
+	stillOwned, err := c.Checkpoint(ctx, "stream", "0", "123", false)
+	require.NoError(t, err)
+	assert.True(t, stillOwned)
+	assert.Equal(t, "123", mgr.Caches["foocache"]["stream:0"].Value)
+
+	require.NoError(t, c.Yield(ctx, "stream", "0", "124"))
+	assert.Equal(t, "124", mgr.Caches["foocache"]["stream:0"].Value)
+
+	claims, err = c.AllClaims(ctx, "stream")
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+
+	seq, err = c.Claim(ctx, "stream", "0", "")
+	require.NoError(t, err)
+	assert.Equal(t, "124", seq)
+
+	_, err = c.Checkpoint(ctx, "stream", "0", "125", true)
+	require.NoError(t, err)
+
+	claims, err = c.AllClaims(ctx, "stream")
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+
+	seq, err = c.Claim(ctx, "stream", "0", "")
+	require.NoError(t, err)
+	assert.Equal(t, "125", seq)
+
+	require.NoError(t, c.Delete(ctx, "stream", "0"))
+	_, exists := mgr.Caches["foocache"]["stream:0"]
+	assert.False(t, exists)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/codec"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/docs"
@@ -115,6 +116,19 @@ output:
       processors:
         - archive:
             format: json_array
+`+"```"+`
+
+It's also possible to write all messages of a batch into a single object with a `+"[`codec`](#codec)"+`. For example, if we wished to upload JSON documents as Parquet files, with each batch written as a row group, we can do that with:
+
+`+"```yaml"+`
+output:
+  aws_s3:
+    bucket: TODO
+    path: ${!count("files")}-${!timestamp_unix_nano()}.parquet
+    codec: parquet:./schema.json
+    batching:
+      count: 1000
+      period: 1m
 `+"```"+``),
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("bucket", "The bucket to upload messages to."),
@@ -141,6 +155,7 @@ output:
 			docs.FieldString("storage_class", "The storage class to set for each object.").HasOptions(
 				"STANDARD", "REDUCED_REDUNDANCY", "GLACIER", "STANDARD_IA", "ONEZONE_IA", "INTELLIGENT_TIERING", "DEEP_ARCHIVE",
 			).IsInterpolated().Advanced(),
+			codec.ObjectWriterDocs,
 			docs.FieldString("kms_key_id", "An optional server side encryption key.").Advanced(),
 			docs.FieldString("server_side_encryption", "An optional server side encryption algorithm.").AtVersion("3.63.0").Advanced(),
 			docs.FieldBool("force_path_style_urls", "Forces the client API to use path style URLs, which helps when connecting to custom endpoints.").Advanced(),
@@ -176,6 +191,7 @@ type amazonS3Writer struct {
 	websiteRedirectLocation *field.Expression
 	storageClass            *field.Expression
 	metaFilter              *metadata.ExcludeFilter
+	codec                   codec.WriterConstructor
 
 	session  *session.Session
 	uploader *s3manager.Uploader
@@ -242,6 +258,15 @@ func newAmazonS3Writer(conf ooutput.AmazonS3Config, mgr interop.Manager) (*amazo
 		return a.tags[i].key < a.tags[j].key
 	})
 
+	ctor, codecConf, err := codec.GetWriter(conf.Codec)
+	if err != nil {
+		return nil, err
+	}
+	// Codecs that close after each message upload an object per message, which
+	// is the default behaviour, otherwise each batch is uploaded as an object.
+	if !codecConf.CloseAfter {
+		a.codec = ctor
+	}
 	return a, nil
 }
 
@@ -274,74 +299,86 @@ func (a *amazonS3Writer) WriteWithContext(wctx context.Context, msg *message.Bat
 	)
 	defer cancel()
 
-	return writer.IterateBatchedSend(msg, func(i int, p *message.Part) error {
-		metadata := map[string]*string{}
-		_ = a.metaFilter.Iter(p, func(k, v string) error {
-			metadata[k] = aws.String(v)
-			return nil
-		})
-
-		var contentEncoding *string
-		if ce := a.contentEncoding.String(i, msg); len(ce) > 0 {
-			contentEncoding = aws.String(ce)
-		}
-		var cacheControl *string
-		if ce := a.cacheControl.String(i, msg); len(ce) > 0 {
-			cacheControl = aws.String(ce)
-		}
-		var contentDisposition *string
-		if ce := a.contentDisposition.String(i, msg); len(ce) > 0 {
-			contentDisposition = aws.String(ce)
-		}
-		var contentLanguage *string
-		if ce := a.contentLanguage.String(i, msg); len(ce) > 0 {
-			contentLanguage = aws.String(ce)
+	if a.codec != nil {
+		payload, encErr := writer.EncodeBatch(ctx, a.codec, msg)
+		if payload == nil {
+			return encErr
 		}
-		var websiteRedirectLocation *string
-		if ce := a.websiteRedirectLocation.String(i, msg); len(ce) > 0 {
-			websiteRedirectLocation = aws.String(ce)
+		if _, err := a.uploader.UploadWithContext(ctx, a.uploadInput(0, msg, payload)); err != nil {
+			return err
 		}
+		return encErr
+	}
 
-		uploadInput := &s3manager.UploadInput{
-			Bucket:                  &a.conf.Bucket,
-			Key:                     aws.String(a.path.String(i, msg)),
-			Body:                    bytes.NewReader(p.Get()),
-			ContentType:             aws.String(a.contentType.String(i, msg)),
-			ContentEncoding:         contentEncoding,
-			CacheControl:            cacheControl,
-			ContentDisposition:      contentDisposition,
-			ContentLanguage:         contentLanguage,
-			WebsiteRedirectLocation: websiteRedirectLocation,
-			StorageClass:            aws.String(a.storageClass.String(i, msg)),
-			Metadata:                metadata,
-		}
+	return writer.IterateBatchedSend(msg, func(i int, p *message.Part) error {
+		_, err := a.uploader.UploadWithContext(ctx, a.uploadInput(i, msg, p.Get()))
+		return err
+	})
+}
 
-		// Prepare tags, escaping keys and values to ensure they're valid query string parameters.
-		if len(a.tags) > 0 {
-			tags := make([]string, len(a.tags))
-			for j, pair := range a.tags {
-				tags[j] = url.QueryEscape(pair.key) + "=" + url.QueryEscape(pair.value.String(i, msg))
-			}
-			uploadInput.Tagging = aws.String(strings.Join(tags, "&"))
-		}
+func (a *amazonS3Writer) uploadInput(i int, msg *message.Batch, body []byte) *s3manager.UploadInput {
+	metadata := map[string]*string{}
+	_ = a.metaFilter.Iter(msg.Get(i), func(k, v string) error {
+		metadata[k] = aws.String(v)
+		return nil
+	})
 
-		if a.conf.KMSKeyID != "" {
-			uploadInput.ServerSideEncryption = aws.String("aws:kms")
-			uploadInput.SSEKMSKeyId = &a.conf.KMSKeyID
-		}
+	var contentEncoding *string
+	if ce := a.contentEncoding.String(i, msg); len(ce) > 0 {
+		contentEncoding = aws.String(ce)
+	}
+	var cacheControl *string
+	if ce := a.cacheControl.String(i, msg); len(ce) > 0 {
+		cacheControl = aws.String(ce)
+	}
+	var contentDisposition *string
+	if ce := a.contentDisposition.String(i, msg); len(ce) > 0 {
+		contentDisposition = aws.String(ce)
+	}
+	var contentLanguage *string
+	if ce := a.contentLanguage.String(i, msg); len(ce) > 0 {
+		contentLanguage = aws.String(ce)
+	}
+	var websiteRedirectLocation *string
+	if ce := a.websiteRedirectLocation.String(i, msg); len(ce) > 0 {
+		websiteRedirectLocation = aws.String(ce)
+	}
 
-		// NOTE: This overrides the ServerSideEncryption set above. We need this to preserve
-		// backwards compatibility, where it is allowed to only set kms_key_id in the config and
-		// the ServerSideEncryption value of "aws:kms" is implied.
-		if a.conf.ServerSideEncryption != "" {
-			uploadInput.ServerSideEncryption = &a.conf.ServerSideEncryption
-		}
+	uploadInput := &s3manager.UploadInput{
+		Bucket:                  &a.conf.Bucket,
+		Key:                     aws.String(a.path.String(i, msg)),
+		Body:                    bytes.NewReader(body),
+		ContentType:             aws.String(a.contentType.String(i, msg)),
+		ContentEncoding:         contentEncoding,
+		CacheControl:            cacheControl,
+		ContentDisposition:      contentDisposition,
+		ContentLanguage:         contentLanguage,
+		WebsiteRedirectLocation: websiteRedirectLocation,
+		StorageClass:            aws.String(a.storageClass.String(i, msg)),
+		Metadata:                metadata,
+	}
 
-		if _, err := a.uploader.UploadWithContext(ctx, uploadInput); err != nil {
-			return err
+	// Prepare tags, escaping keys and values to ensure they're valid query string parameters.
+	if len(a.tags) > 0 {
+		tags := make([]string, len(a.tags))
+		for j, pair := range a.tags {
+			tags[j] = url.QueryEscape(pair.key) + "=" + url.QueryEscape(pair.value.String(i, msg))
 		}
-		return nil
-	})
+		uploadInput.Tagging = aws.String(strings.Join(tags, "&"))
+	}
+
+	if a.conf.KMSKeyID != "" {
+		uploadInput.ServerSideEncryption = aws.String("aws:kms")
+		uploadInput.SSEKMSKeyId = &a.conf.KMSKeyID
+	}
+
+	// NOTE: This overrides the ServerSideEncryption set above. We need this to preserve
+	// backwards compatibility, where it is allowed to only set kms_key_id in the config and
+	// the ServerSideEncryption value of "aws:kms" is implied.
+	if a.conf.ServerSideEncryption != "" {
+		uploadInput.ServerSideEncryption = &a.conf.ServerSideEncryption
+	}
+	return uploadInput
 }
 
 func (a *amazonS3Writer) CloseAsync() {
@@ -0,0 +1,164 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/bloblang"
+)
+
+func rsaTestKeys(t *testing.T) (private, public string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	private = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	public = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}))
+	return
+}
+
+func ecdsaTestKeys(t *testing.T, curve elliptic.Curve) (private, public string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+
+	privBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	private = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privBytes}))
+	public = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}))
+	return
+}
+
+func TestJWTRoundTrip(t *testing.T) {
+	rsaPriv, rsaPub := rsaTestKeys(t)
+	ec256Priv, ec256Pub := ecdsaTestKeys(t, elliptic.P256())
+	ec384Priv, ec384Pub := ecdsaTestKeys(t, elliptic.P384())
+	ec521Priv, ec521Pub := ecdsaTestKeys(t, elliptic.P521())
+
+	tests := []struct {
+		family    string
+		alg       string
+		signKey   string
+		verifyKey string
+	}{
+		{family: "hs", alg: "HS256", signKey: "foo", verifyKey: "foo"},
+		{family: "hs", alg: "HS384", signKey: "foo", verifyKey: "foo"},
+		{family: "hs", alg: "HS512", signKey: "foo", verifyKey: "foo"},
+		{family: "rs", alg: "RS256", signKey: rsaPriv, verifyKey: rsaPub},
+		{family: "rs", alg: "RS384", signKey: rsaPriv, verifyKey: rsaPub},
+		{family: "rs", alg: "RS512", signKey: rsaPriv, verifyKey: rsaPub},
+		{family: "es", alg: "ES256", signKey: ec256Priv, verifyKey: ec256Pub},
+		{family: "es", alg: "ES384", signKey: ec384Priv, verifyKey: ec384Pub},
+		{family: "es", alg: "ES512", signKey: ec521Priv, verifyKey: ec521Pub},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.alg, func(t *testing.T) {
+			exec, err := bloblang.Parse(`root = this.claims.sign_jwt_` + test.family + `(this.sign_key, this.alg).parse_jwt_` + test.family + `(this.verify_key)`)
+			require.NoError(t, err)
+
+			res, err := exec.Query(map[string]interface{}{
+				"claims": map[string]interface{}{
+					"sub": "user123",
+					"exp": time.Now().Add(time.Hour).Unix(),
+				},
+				"alg":        test.alg,
+				"sign_key":   test.signKey,
+				"verify_key": test.verifyKey,
+			})
+			require.NoError(t, err)
+
+			claims, ok := res.(map[string]interface{})
+			require.True(t, ok, "%T", res)
+			assert.Equal(t, "user123", claims["sub"])
+		})
+	}
+}
+
+func TestJWTParseErrors(t *testing.T) {
+	_, rsaPub := rsaTestKeys(t)
+
+	exec, err := bloblang.Parse(`root = this.claims.sign_jwt_hs(this.secret)`)
+	require.NoError(t, err)
+
+	expired, err := exec.Query(map[string]interface{}{
+		"claims": map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()},
+		"secret": "foo",
+	})
+	require.NoError(t, err)
+
+	valid, err := exec.Query(map[string]interface{}{
+		"claims": map[string]interface{}{"sub": "user123"},
+		"secret": "foo",
+	})
+	require.NoError(t, err)
+
+	// A token signed with HMAC using the public key as the secret must not be
+	// accepted by methods that expect an asymmetric algorithm.
+	confused, err := exec.Query(map[string]interface{}{
+		"claims": map[string]interface{}{"sub": "user123"},
+		"secret": rsaPub,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		mapping string
+		token   interface{}
+		key     string
+	}{
+		{name: "expired", mapping: `parse_jwt_hs`, token: expired, key: "foo"},
+		{name: "wrong secret", mapping: `parse_jwt_hs`, token: valid, key: "bar"},
+		{name: "wrong algorithm", mapping: `parse_jwt_rs`, token: confused, key: rsaPub},
+		{name: "not a token", mapping: `parse_jwt_hs`, token: "nope", key: "foo"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(`root = this.token.` + test.mapping + `(this.key)`)
+			require.NoError(t, err)
+
+			_, err = exec.Query(map[string]interface{}{
+				"token": test.token,
+				"key":   test.key,
+			})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestJWTSignErrors(t *testing.T) {
+	_, err := bloblang.Parse(`root = this.sign_jwt_hs("foo", "RS256")`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported signing algorithm RS256")
+
+	_, err = bloblang.Parse(`root = this.sign_jwt_rs("not a key")`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse private_key")
+
+	exec, err := bloblang.Parse(`root = this.sign_jwt_hs("foo")`)
+	require.NoError(t, err)
+
+	_, err = exec.Query("not an object")
+	require.Error(t, err)
+}
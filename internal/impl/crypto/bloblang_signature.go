@@ -0,0 +1,287 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"fmt"
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/bloblang/query"
+	"github.com/benthosdev/benthos/v4/public/bloblang"
+)
+
+var signatureHashes = map[string]crypto.Hash{
+	"sha1":   crypto.SHA1,
+	"sha256": crypto.SHA256,
+	"sha384": crypto.SHA384,
+	"sha512": crypto.SHA512,
+}
+
+func hashParam(name string) bloblang.ParamDefinition {
+	return bloblang.NewStringParam(name).Description("The hashing algorithm to use, one of `sha1`, `sha256`, `sha384` or `sha512`.")
+}
+
+func getHash(args *bloblang.ParsedParams, name string) (crypto.Hash, error) {
+	hashStr, err := args.GetString(name)
+	if err != nil {
+		return 0, err
+	}
+	h, exists := signatureHashes[hashStr]
+	if !exists {
+		return 0, fmt.Errorf("unsupported hashing algorithm: %v", hashStr)
+	}
+	return h, nil
+}
+
+func digest(h crypto.Hash, b []byte) []byte {
+	hasher := h.New()
+	_, _ = hasher.Write(b)
+	return hasher.Sum(nil)
+}
+
+// signMethod creates a method constructor that signs the digest of a string or
+// byte array with a private key provided by a parameter.
+func signMethod(keyFn parseKeyFn, signFn func(key interface{}, h crypto.Hash, sum []byte) ([]byte, error)) bloblang.MethodConstructorV2 {
+	return func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+		keyStr, err := args.GetString("private_key")
+		if err != nil {
+			return nil, err
+		}
+		key, err := keyFn(keyStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private_key: %w", err)
+		}
+		h, err := getHash(args, "hash")
+		if err != nil {
+			return nil, err
+		}
+		return func(v interface{}) (interface{}, error) {
+			b, err := query.IGetBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			return signFn(key, h, digest(h, b))
+		}, nil
+	}
+}
+
+// parsedKeyCache keeps the most recently parsed key of a method constructor.
+// Constructors are called again for each invocation of a method with dynamic
+// arguments, which is always the case when a signature is derived from the
+// input, and so the cache avoids parsing the same PEM encoded key every time.
+type parsedKeyCache struct {
+	keyFn parseKeyFn
+
+	mut    sync.Mutex
+	keyStr string
+	key    interface{}
+}
+
+func (c *parsedKeyCache) parse(keyStr string) (interface{}, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.key != nil && c.keyStr == keyStr {
+		return c.key, nil
+	}
+	key, err := c.keyFn(keyStr)
+	if err != nil {
+		return nil, err
+	}
+	c.keyStr, c.key = keyStr, key
+	return key, nil
+}
+
+// verifyMethod creates a method constructor that checks a signature of the
+// digest of a string or byte array with a public key provided by a parameter.
+// The public key is only parsed again when it changes, leaving the signature
+// as the only argument read on each invocation.
+func verifyMethod(keyFn parseKeyFn, verifyFn func(key interface{}, h crypto.Hash, sum, sig []byte) bool) bloblang.MethodConstructorV2 {
+	keys := &parsedKeyCache{keyFn: keyFn}
+	return func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+		keyStr, err := args.GetString("public_key")
+		if err != nil {
+			return nil, err
+		}
+		key, err := keys.parse(keyStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public_key: %w", err)
+		}
+		sigV, err := args.Get("signature")
+		if err != nil {
+			return nil, err
+		}
+		sig, err := query.IGetBytes(sigV)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature: %w", err)
+		}
+		h, err := getHash(args, "hash")
+		if err != nil {
+			return nil, err
+		}
+		return func(v interface{}) (interface{}, error) {
+			b, err := query.IGetBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			return verifyFn(key, h, digest(h, b), sig), nil
+		}, nil
+	}
+}
+
+func init() {
+	signRSASpec := bloblang.NewPluginSpec().
+		Category(string(query.MethodCategoryEncoding)).
+		Description("Signs a string or byte array with an RSA private key using the RSASSA-PKCS1-v1_5 scheme and returns the signature as a byte array. When mapping the result to a JSON field the value should be encoded using the method [`encode`](#encode), otherwise it will be base64 encoded by default."+keySourceDescription).
+		Param(bloblang.NewStringParam("private_key").Description("A PEM encoded RSA private key in PKCS #1 or PKCS #8 format.")).
+		Param(hashParam("hash").Default("sha256")).
+		Example("",
+			`root.signature = this.body.sign_rsa("""`+exampleRSAPrivateKey+`""").encode("base64")`,
+			[2]string{
+				`{"body":"hello world"}`,
+				`{"signature":"XOZANpedretuvgMJE1+8xDTkdF7xv8tWDPSTOXVwxPAD665nYXzJs7HCiAJW3roCd/eza6RvzYE0Hnd+eUGO1VFBhqgzSWeb+umSHp3TsKm+5Ot1cKKWNKBhlBFUGvL65Nfo/7j5DnQscPD7C4uTX3loe4bFeb79X8H72EhZQ6Z2DCtAXsgg+i4y0WbCYbd+cznxJoEcGS5zUJ/zUL4SsaO+rd+cPzkYa9baM9c3dN2vnDdlQYAfBeLiAGNlyjW+t8EXoAoSMQtejnM1Z+JacAoNCgB86GNrFD2sCO0WKsMIlg8/5w9pjx+FHGqYwgVLyUw0WQXGQgZb6VNHvnU62Q=="}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"sign_rsa", signRSASpec,
+		signMethod(rsaPrivateKey, func(key interface{}, h crypto.Hash, sum []byte) ([]byte, error) {
+			return rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), h, sum)
+		}),
+	); err != nil {
+		panic(err)
+	}
+
+	verifyRSASpec := bloblang.NewPluginSpec().
+		Category(string(query.MethodCategoryEncoding)).
+		Description("Checks an RSASSA-PKCS1-v1_5 signature of a string or byte array with an RSA public key and returns a boolean indicating whether the signature is valid."+keySourceDescription).
+		Param(bloblang.NewStringParam("public_key").Description("A PEM encoded RSA public key or certificate.")).
+		Param(bloblang.NewAnyParam("signature").Description("The signature to check as a byte array, which might need to be decoded with the method [`decode`](#decode) first.")).
+		Param(hashParam("hash").Default("sha256")).
+		Example("",
+			`root.valid = this.body.verify_rsa("""`+exampleRSAPublicKey+`""", this.signature.decode("base64"))`,
+			[2]string{
+				`{"body":"hello world","signature":"XOZANpedretuvgMJE1+8xDTkdF7xv8tWDPSTOXVwxPAD665nYXzJs7HCiAJW3roCd/eza6RvzYE0Hnd+eUGO1VFBhqgzSWeb+umSHp3TsKm+5Ot1cKKWNKBhlBFUGvL65Nfo/7j5DnQscPD7C4uTX3loe4bFeb79X8H72EhZQ6Z2DCtAXsgg+i4y0WbCYbd+cznxJoEcGS5zUJ/zUL4SsaO+rd+cPzkYa9baM9c3dN2vnDdlQYAfBeLiAGNlyjW+t8EXoAoSMQtejnM1Z+JacAoNCgB86GNrFD2sCO0WKsMIlg8/5w9pjx+FHGqYwgVLyUw0WQXGQgZb6VNHvnU62Q=="}`,
+				`{"valid":true}`,
+			},
+			[2]string{
+				`{"body":"hello world!","signature":"XOZANpedretuvgMJE1+8xDTkdF7xv8tWDPSTOXVwxPAD665nYXzJs7HCiAJW3roCd/eza6RvzYE0Hnd+eUGO1VFBhqgzSWeb+umSHp3TsKm+5Ot1cKKWNKBhlBFUGvL65Nfo/7j5DnQscPD7C4uTX3loe4bFeb79X8H72EhZQ6Z2DCtAXsgg+i4y0WbCYbd+cznxJoEcGS5zUJ/zUL4SsaO+rd+cPzkYa9baM9c3dN2vnDdlQYAfBeLiAGNlyjW+t8EXoAoSMQtejnM1Z+JacAoNCgB86GNrFD2sCO0WKsMIlg8/5w9pjx+FHGqYwgVLyUw0WQXGQgZb6VNHvnU62Q=="}`,
+				`{"valid":false}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"verify_rsa", verifyRSASpec,
+		verifyMethod(rsaPublicKey, func(key interface{}, h crypto.Hash, sum, sig []byte) bool {
+			return rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), h, sum, sig) == nil
+		}),
+	); err != nil {
+		panic(err)
+	}
+
+	signECDSASpec := bloblang.NewPluginSpec().
+		Category(string(query.MethodCategoryEncoding)).
+		Description("Signs a string or byte array with an ECDSA private key and returns the ASN.1 DER encoded signature as a byte array. When mapping the result to a JSON field the value should be encoded using the method [`encode`](#encode), otherwise it will be base64 encoded by default."+keySourceDescription).
+		Param(bloblang.NewStringParam("private_key").Description("A PEM encoded ECDSA private key in SEC 1 or PKCS #8 format.")).
+		Param(hashParam("hash").Default("sha256")).
+		Example("ECDSA signatures are not deterministic and so the resulting signature differs each time.",
+			`root.signature = this.body.sign_ecdsa("""`+exampleECDSAPrivateKey+`""").encode("base64")`)
+
+	if err := bloblang.RegisterMethodV2(
+		"sign_ecdsa", signECDSASpec,
+		signMethod(ecdsaPrivateKey, func(key interface{}, h crypto.Hash, sum []byte) ([]byte, error) {
+			return ecdsa.SignASN1(rand.Reader, key.(*ecdsa.PrivateKey), sum)
+		}),
+	); err != nil {
+		panic(err)
+	}
+
+	verifyECDSASpec := bloblang.NewPluginSpec().
+		Category(string(query.MethodCategoryEncoding)).
+		Description("Checks an ASN.1 DER encoded ECDSA signature of a string or byte array with an ECDSA public key and returns a boolean indicating whether the signature is valid."+keySourceDescription).
+		Param(bloblang.NewStringParam("public_key").Description("A PEM encoded ECDSA public key or certificate.")).
+		Param(bloblang.NewAnyParam("signature").Description("The signature to check as a byte array, which might need to be decoded with the method [`decode`](#decode) first.")).
+		Param(hashParam("hash").Default("sha256")).
+		Example("",
+			`root.valid = this.body.verify_ecdsa("""`+exampleECDSAPublicKey+`""", this.signature.decode("base64"))`,
+			[2]string{
+				`{"body":"hello world","signature":"MEUCIHfVagASPd6sSObNstbHfmPc7qqYlNqbaIlp+h3+eSrOAiEArzGzOAvYYOOtgq6tXejmfFWQIS4gMoMz4/ZvoC3RSos="}`,
+				`{"valid":true}`,
+			},
+			[2]string{
+				`{"body":"hello world!","signature":"MEUCIHfVagASPd6sSObNstbHfmPc7qqYlNqbaIlp+h3+eSrOAiEArzGzOAvYYOOtgq6tXejmfFWQIS4gMoMz4/ZvoC3RSos="}`,
+				`{"valid":false}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"verify_ecdsa", verifyECDSASpec,
+		verifyMethod(ecdsaPublicKey, func(key interface{}, h crypto.Hash, sum, sig []byte) bool {
+			return ecdsa.VerifyASN1(key.(*ecdsa.PublicKey), sum, sig)
+		}),
+	); err != nil {
+		panic(err)
+	}
+
+	verifyHMACSpec := bloblang.NewPluginSpec().
+		Category(string(query.MethodCategoryEncoding)).
+		Description("Computes the HMAC of a string or byte array with a secret key and compares it in constant time against a provided signature, returning a boolean indicating whether they match. This should be preferred over comparing the result of the method [`hash`](#hash) with a signature as the time taken by a regular comparison can leak information about the expected signature."+keySourceDescription).
+		Param(hashParam("algorithm")).
+		Param(bloblang.NewStringParam("key").Description("The secret key to compute the HMAC with.")).
+		Param(bloblang.NewAnyParam("signature").Description("The signature to compare against as a byte array, which might need to be decoded with the method [`decode`](#decode) first.")).
+		Example("",
+			`root.valid = this.body.verify_hmac("sha256", "dont-tell-anyone", this.signature.decode("hex"))`,
+			[2]string{
+				`{"body":"hello world","signature":"0153387bda178b7e8aee1150ae98aa2e07727cfc12bd100ac5d265fb14307915"}`,
+				`{"valid":true}`,
+			},
+			[2]string{
+				`{"body":"hello world!","signature":"0153387bda178b7e8aee1150ae98aa2e07727cfc12bd100ac5d265fb14307915"}`,
+				`{"valid":false}`,
+			}).
+		Example("When ingesting webhooks with an `http_server` input the raw payload and signature header can be checked before any processing takes place.",
+			`root = if !content().verify_hmac("sha256", "dont-tell-anyone", meta("X-Signature").decode("hex")) {
+  throw("invalid webhook signature")
+} else {
+  this
+}`)
+
+	if err := bloblang.RegisterMethodV2(
+		"verify_hmac", verifyHMACSpec,
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			h, err := getHash(args, "algorithm")
+			if err != nil {
+				return nil, err
+			}
+			key, err := args.GetString("key")
+			if err != nil {
+				return nil, err
+			}
+			if key == "" {
+				return nil, fmt.Errorf("key must not be empty")
+			}
+			sigV, err := args.Get("signature")
+			if err != nil {
+				return nil, err
+			}
+			sig, err := query.IGetBytes(sigV)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read signature: %w", err)
+			}
+			return func(v interface{}) (interface{}, error) {
+				b, err := query.IGetBytes(v)
+				if err != nil {
+					return nil, err
+				}
+				mac := hmac.New(h.New, []byte(key))
+				_, _ = mac.Write(b)
+				return hmac.Equal(mac.Sum(nil), sig), nil
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+}
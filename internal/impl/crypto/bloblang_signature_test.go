@@ -0,0 +1,135 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/bloblang"
+)
+
+func TestSignatureRoundTrip(t *testing.T) {
+	rsaPriv, rsaPub := rsaTestKeys(t)
+	ecPriv, ecPub := ecdsaTestKeys(t, elliptic.P256())
+
+	tests := []struct {
+		name      string
+		method    string
+		hash      string
+		signKey   string
+		verifyKey string
+	}{
+		{name: "rsa sha1", method: "rsa", hash: "sha1", signKey: rsaPriv, verifyKey: rsaPub},
+		{name: "rsa sha256", method: "rsa", hash: "sha256", signKey: rsaPriv, verifyKey: rsaPub},
+		{name: "rsa sha512", method: "rsa", hash: "sha512", signKey: rsaPriv, verifyKey: rsaPub},
+		{name: "ecdsa sha256", method: "ecdsa", hash: "sha256", signKey: ecPriv, verifyKey: ecPub},
+		{name: "ecdsa sha384", method: "ecdsa", hash: "sha384", signKey: ecPriv, verifyKey: ecPub},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(`let sig = this.body.sign_` + test.method + `(this.sign_key, this.hash)
+root.valid = this.body.verify_` + test.method + `(this.verify_key, $sig, this.hash)
+root.tampered = (this.body + "!").verify_` + test.method + `(this.verify_key, $sig, this.hash)
+root.other_hash = this.body.verify_` + test.method + `(this.verify_key, $sig, if this.hash == "sha256" { "sha512" } else { "sha256" })`)
+			require.NoError(t, err)
+
+			res, err := exec.Query(map[string]interface{}{
+				"body":       "hello world",
+				"hash":       test.hash,
+				"sign_key":   test.signKey,
+				"verify_key": test.verifyKey,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, map[string]interface{}{
+				"valid":      true,
+				"tampered":   false,
+				"other_hash": false,
+			}, res)
+		})
+	}
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	exec, err := bloblang.Parse(`root.sha256 = this.body.verify_hmac("sha256", "dont-tell-anyone", this.signature.decode("hex"))
+root.sha1 = this.body.verify_hmac("sha1", "dont-tell-anyone", this.signature.decode("hex"))
+root.hashed = this.body.verify_hmac("sha256", "dont-tell-anyone", this.body.hash("hmac_sha256", "dont-tell-anyone"))
+root.wrong_key = this.body.verify_hmac("sha256", "tell-everyone", this.signature.decode("hex"))`)
+	require.NoError(t, err)
+
+	res, err := exec.Query(map[string]interface{}{
+		"body":      "hello world",
+		"signature": "0153387bda178b7e8aee1150ae98aa2e07727cfc12bd100ac5d265fb14307915",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"sha256":    true,
+		"sha1":      false,
+		"hashed":    true,
+		"wrong_key": false,
+	}, res)
+}
+
+func TestSignatureErrors(t *testing.T) {
+	_, err := bloblang.Parse(`root = this.verify_hmac("md5", "foo", "bar")`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported hashing algorithm: md5")
+
+	_, err = bloblang.Parse(`root = this.verify_hmac("sha256", "", "bar")`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "key must not be empty")
+
+	_, err = bloblang.Parse(`root = this.sign_rsa("not a key")`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse private_key")
+
+	_, err = bloblang.Parse(`root = this.verify_ecdsa("not a key", "foo")`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse public_key")
+}
+
+func TestVerifyParsesKeyOnce(t *testing.T) {
+	rsaPriv, rsaPub := rsaTestKeys(t)
+
+	var parsed int
+	env := bloblang.NewEmptyEnvironment()
+	require.NoError(t, env.RegisterMethodV2("verify_counted",
+		bloblang.NewPluginSpec().
+			Param(bloblang.NewStringParam("public_key")).
+			Param(bloblang.NewAnyParam("signature")).
+			Param(hashParam("hash").Default("sha256")),
+		verifyMethod(func(key string) (interface{}, error) {
+			parsed++
+			return rsaPublicKey(key)
+		}, func(key interface{}, h crypto.Hash, sum, sig []byte) bool {
+			return rsa.VerifyPKCS1v15(key.(*rsa.PublicKey), h, sum, sig) == nil
+		}),
+	))
+
+	signExec, err := bloblang.Parse(`root = this.body.sign_rsa(this.key)`)
+	require.NoError(t, err)
+
+	verifyExec, err := env.Parse(`root = this.body.verify_counted(this.key, this.signature)`)
+	require.NoError(t, err)
+
+	for _, body := range []string{"foo", "bar", "baz"} {
+		sig, err := signExec.Query(map[string]interface{}{"body": body, "key": rsaPriv})
+		require.NoError(t, err)
+
+		res, err := verifyExec.Query(map[string]interface{}{
+			"body":      body,
+			"key":       rsaPub,
+			"signature": sig,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, true, res)
+	}
+	assert.Equal(t, 1, parsed)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/batch/policy"
 	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/codec"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	ioutput "github.com/benthosdev/benthos/v4/internal/component/output"
@@ -33,7 +34,9 @@ func init() {
 		if err != nil {
 			return nil, err
 		}
-		w = output.OnlySinglePayloads(w)
+		if g.codec == nil {
+			w = output.OnlySinglePayloads(w)
+		}
 		return output.NewBatcherFromConfig(c.GCPCloudStorage.Batching, w, nm, nm.Logger(), nm.Metrics())
 	}), docs.ComponentSpec{
 		Name:       output.TypeGCPCloudStorage,
@@ -97,6 +100,19 @@ output:
       processors:
         - archive:
             format: json_array
+`+"```"+`
+
+It's also possible to write all messages of a batch into a single object with a `+"[`codec`](#codec)"+`. For example, if we wished to upload JSON documents as Parquet files, with each batch written as a row group, we can do that with:
+
+`+"```yaml"+`
+output:
+  gcp_cloud_storage:
+    bucket: TODO
+    path: ${!count("files")}-${!timestamp_unix_nano()}.parquet
+    codec: parquet:./schema.json
+    batching:
+      count: 1000
+      period: 1m
 `+"```"+``),
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString("bucket", "The bucket to upload messages to."),
@@ -116,6 +132,7 @@ output:
 					"ignore", "Do not modify the original file, the new data will be dropped.",
 				).AtVersion("3.53.0"),
 			docs.FieldString("content_encoding", "An optional content encoding to set for each object.").IsInterpolated().Advanced(),
+			codec.ObjectWriterDocs,
 			docs.FieldInt("chunk_size", "An optional chunk size which controls the maximum number of bytes of the object that the Writer will attempt to send to the server in a single request. If ChunkSize is set to zero, chunking will be disabled.").Advanced(),
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
 			policy.FieldSpec(),
@@ -134,6 +151,7 @@ type gcpCloudStorageOutput struct {
 	path            *field.Expression
 	contentType     *field.Expression
 	contentEncoding *field.Expression
+	codec           codec.WriterConstructor
 
 	client  *storage.Client
 	connMut sync.RWMutex
@@ -168,6 +186,15 @@ func newGCPCloudStorageOutput(
 		return nil, fmt.Errorf("failed to parse content encoding expression: %v", err)
 	}
 
+	ctor, codecConf, err := codec.GetWriter(conf.Codec)
+	if err != nil {
+		return nil, err
+	}
+	// Codecs that close after each message upload an object per message, which
+	// is the default behaviour, otherwise each batch is uploaded as an object.
+	if !codecConf.CloseAfter {
+		g.codec = ctor
+	}
 	return g, nil
 }
 
@@ -198,67 +225,82 @@ func (g *gcpCloudStorageOutput) WriteWithContext(ctx context.Context, msg *messa
 		return component.ErrNotConnected
 	}
 
-	return writer.IterateBatchedSend(msg, func(i int, p *message.Part) error {
-		metadata := map[string]string{}
-		_ = p.MetaIter(func(k, v string) error {
-			metadata[k] = v
-			return nil
-		})
-
-		outputPath := g.path.String(i, msg)
-		var err error
-		if g.conf.CollisionMode != output.GCPCloudStorageOverwriteCollisionMode {
-			_, err = client.Bucket(g.conf.Bucket).Object(outputPath).Attrs(ctx)
+	if g.codec != nil {
+		payload, encErr := writer.EncodeBatch(ctx, g.codec, msg)
+		if payload == nil {
+			return encErr
+		}
+		if err := g.writeObject(ctx, client, 0, msg, payload); err != nil {
+			return err
 		}
+		return encErr
+	}
 
-		isMerge := false
-		var tempPath string
-		if err == storage.ErrObjectNotExist || g.conf.CollisionMode == output.GCPCloudStorageOverwriteCollisionMode {
-			tempPath = outputPath
-		} else {
-			isMerge = true
-
-			if g.conf.CollisionMode == output.GCPCloudStorageErrorIfExistsCollisionMode {
-				if err == nil {
-					err = fmt.Errorf("file at path already exists: %s", outputPath)
-				}
-				return err
-			} else if g.conf.CollisionMode == output.GCPCloudStorageIgnoreCollisionMode {
-				return nil
-			}
+	return writer.IterateBatchedSend(msg, func(i int, p *message.Part) error {
+		return g.writeObject(ctx, client, i, msg, p.Get())
+	})
+}
 
-			tempUUID, err := uuid.NewV4()
-			if err != nil {
-				return err
-			}
+func (g *gcpCloudStorageOutput) writeObject(ctx context.Context, client *storage.Client, i int, msg *message.Batch, payload []byte) error {
+	metadata := map[string]string{}
+	_ = msg.Get(i).MetaIter(func(k, v string) error {
+		metadata[k] = v
+		return nil
+	})
 
-			dir := path.Dir(outputPath)
-			tempFileName := fmt.Sprintf("%s.tmp", tempUUID.String())
-			tempPath = path.Join(dir, tempFileName)
-		}
+	outputPath := g.path.String(i, msg)
+	var err error
+	if g.conf.CollisionMode != output.GCPCloudStorageOverwriteCollisionMode {
+		_, err = client.Bucket(g.conf.Bucket).Object(outputPath).Attrs(ctx)
+	}
 
-		w := client.Bucket(g.conf.Bucket).Object(tempPath).NewWriter(ctx)
+	isMerge := false
+	var tempPath string
+	if err == storage.ErrObjectNotExist || g.conf.CollisionMode == output.GCPCloudStorageOverwriteCollisionMode {
+		tempPath = outputPath
+	} else {
+		isMerge = true
 
-		w.ChunkSize = g.conf.ChunkSize
-		w.ContentType = g.contentType.String(i, msg)
-		w.ContentEncoding = g.contentEncoding.String(i, msg)
-		w.Metadata = metadata
-		if _, err = w.Write(p.Get()); err != nil {
+		if g.conf.CollisionMode == output.GCPCloudStorageErrorIfExistsCollisionMode {
+			if err == nil {
+				err = fmt.Errorf("file at path already exists: %s", outputPath)
+			}
 			return err
+		} else if g.conf.CollisionMode == output.GCPCloudStorageIgnoreCollisionMode {
+			return nil
 		}
 
-		if err := w.Close(); err != nil {
+		tempUUID, err := uuid.NewV4()
+		if err != nil {
 			return err
 		}
 
-		if isMerge {
-			if err := g.appendToFile(ctx, tempPath, outputPath); err != nil {
-				return err
-			}
-		}
+		dir := path.Dir(outputPath)
+		tempFileName := fmt.Sprintf("%s.tmp", tempUUID.String())
+		tempPath = path.Join(dir, tempFileName)
+	}
 
+	w := client.Bucket(g.conf.Bucket).Object(tempPath).NewWriter(ctx)
+
+	w.ChunkSize = g.conf.ChunkSize
+	w.ContentType = g.contentType.String(i, msg)
+	w.ContentEncoding = g.contentEncoding.String(i, msg)
+	w.Metadata = metadata
+	if _, err = w.Write(payload); err != nil {
 		return err
-	})
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if isMerge {
+		if err := g.appendToFile(ctx, tempPath, outputPath); err != nil {
+			return err
+		}
+	}
+
+	return err
 }
 
 // CloseAsync begins cleaning up resources used by this reader asynchronously.
@@ -0,0 +1,824 @@
+package generic
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+const (
+	fbufFieldDirectory       = "directory"
+	fbufFieldLimit           = "limit"
+	fbufFieldSegmentMaxBytes = "segment_max_bytes"
+	fbufFieldSync            = "sync"
+	fbufFieldSyncInterval    = "sync_interval"
+
+	fbufSyncAlways   = "always"
+	fbufSyncInterval = "interval"
+	fbufSyncNever    = "never"
+
+	fbufSegmentExt    = ".wal"
+	fbufRecordHeadLen = 8
+)
+
+func fileBufferConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.0.0").
+		Summary("Stores consumed messages in segmented write-ahead log files on disk, where they persist until they have been acknowledged downstream.").
+		Description(`
+Message batches are appended to log segment files within the configured directory. A segment is deleted only once it has been rotated and every batch written to it has been successfully delivered downstream, and batches that are rejected downstream are redelivered.
+
+When Benthos is restarted any segments remaining within the directory are replayed from the beginning, and therefore batches that were already delivered before the restart but share a segment with undelivered batches will be delivered again.
+
+## Delivery Guarantees
+
+Input batches are acknowledged once they have been written to disk, with the durability of that write determined by the ` + "`sync`" + ` field. When ` + "`sync`" + ` is set to ` + "`always`" + ` this buffer preserves at-least-once delivery guarantees across crashes, with the other policies trading some risk of data loss in the event of a machine failure for throughput.
+
+## Back Pressure
+
+This buffer has a configurable limit on the total size of all segments on disk, where consumption will be stopped with back pressure upstream once this limit is reached and will resume as segments are acknowledged and removed.`).
+		Field(service.NewStringField(fbufFieldDirectory).
+			Description("The directory within which to store log segment files. The directory is created if it does not already exist, and must not be shared with any other buffer.")).
+		Field(service.NewIntField(fbufFieldLimit).
+			Description("The maximum total size (in bytes) of all segments on disk before back pressure is applied upstream.").
+			Default(1073741824)).
+		Field(service.NewIntField(fbufFieldSegmentMaxBytes).
+			Description("The maximum size (in bytes) of an individual segment file before a new segment is started. Smaller segments are removed sooner after being acknowledged at the cost of more files.").
+			Advanced().
+			Default(67108864)).
+		Field(service.NewStringAnnotatedEnumField(fbufFieldSync, map[string]string{
+			fbufSyncAlways:   "Sync the active segment to disk after every write, before acknowledging the input.",
+			fbufSyncInterval: "Sync the active segment to disk periodically according to `sync_interval`.",
+			fbufSyncNever:    "Never explicitly sync segments, leaving it to the operating system.",
+		}).
+			Description("The policy for flushing written batches to stable storage.").
+			Default(fbufSyncAlways)).
+		Field(service.NewDurationField(fbufFieldSyncInterval).
+			Description("The period at which to sync the active segment when `sync` is set to `interval`.").
+			Advanced().
+			Default("1s"))
+}
+
+func init() {
+	err := service.RegisterBatchBuffer(
+		"file", fileBufferConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newFileBufferFromConfig(conf, mgr)
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newFileBufferFromConfig(conf *service.ParsedConfig, res *service.Resources) (*fileBuffer, error) {
+	dir, err := conf.FieldString(fbufFieldDirectory)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := conf.FieldInt(fbufFieldLimit)
+	if err != nil {
+		return nil, err
+	}
+	segMax, err := conf.FieldInt(fbufFieldSegmentMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	syncPolicy, err := conf.FieldString(fbufFieldSync)
+	if err != nil {
+		return nil, err
+	}
+	syncInterval, err := conf.FieldDuration(fbufFieldSyncInterval)
+	if err != nil {
+		return nil, err
+	}
+	switch syncPolicy {
+	case fbufSyncAlways, fbufSyncNever:
+	case fbufSyncInterval:
+		if syncInterval <= 0 {
+			return nil, fmt.Errorf("field %v must be greater than zero when %v is %v", fbufFieldSyncInterval, fbufFieldSync, fbufSyncInterval)
+		}
+	default:
+		return nil, fmt.Errorf("unrecognised %v policy: %v", fbufFieldSync, syncPolicy)
+	}
+	if segMax <= fbufRecordHeadLen {
+		return nil, fmt.Errorf("field %v must be greater than %v", fbufFieldSegmentMaxBytes, fbufRecordHeadLen)
+	}
+	if limit < segMax {
+		return nil, fmt.Errorf("field %v must be at least as large as %v", fbufFieldLimit, fbufFieldSegmentMaxBytes)
+	}
+	return newFileBuffer(dir, int64(limit), int64(segMax), syncPolicy, syncInterval, res.Logger())
+}
+
+//------------------------------------------------------------------------------
+
+// fileSegment tracks the state of a single log file on disk.
+type fileSegment struct {
+	id   uint64
+	path string
+
+	// The number of bytes and records written to the segment.
+	size    int64
+	written int
+
+	// The number of records that have been delivered downstream.
+	acked int
+
+	// A sealed segment will no longer be written to, and can therefore be
+	// deleted once all of its records are acknowledged.
+	sealed bool
+}
+
+func (s *fileSegment) fullyAcked() bool {
+	return s.written > 0 && s.acked >= s.written
+}
+
+type pendingBatch struct {
+	b   service.MessageBatch
+	seg *fileSegment
+}
+
+type fileBuffer struct {
+	dir          string
+	limit        int64
+	segMax       int64
+	syncPolicy   string
+	syncInterval time.Duration
+	log          *service.Logger
+
+	cond *sync.Cond
+
+	// All segments currently on disk, ordered from oldest to newest. The last
+	// segment is the one being written to unless it is sealed.
+	segments  []*fileSegment
+	nextID    uint64
+	diskBytes int64
+
+	head     *os.File
+	headSeg  *fileSegment
+	headDirt bool
+
+	readID   uint64
+	readOff  int64
+	readFile *os.File
+	readFID  uint64
+
+	// Batches that were rejected downstream and must be delivered again
+	// before continuing through the log.
+	retries []pendingBatch
+	pending int
+
+	endOfInput bool
+	closed     bool
+	closeChan  chan struct{}
+}
+
+func newFileBuffer(dir string, limit, segMax int64, syncPolicy string, syncInterval time.Duration, log *service.Logger) (*fileBuffer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	f := &fileBuffer{
+		dir:          dir,
+		limit:        limit,
+		segMax:       segMax,
+		syncPolicy:   syncPolicy,
+		syncInterval: syncInterval,
+		log:          log,
+		cond:         sync.NewCond(&sync.Mutex{}),
+		closeChan:    make(chan struct{}),
+	}
+	if err := f.recoverSegments(); err != nil {
+		return nil, err
+	}
+	if syncPolicy == fbufSyncInterval {
+		go f.syncLoop()
+	}
+	return f, nil
+}
+
+func (f *fileBuffer) segmentPath(id uint64) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%020d%v", id, fbufSegmentExt))
+}
+
+// recoverSegments scans the buffer directory for segments left over from a previous
+// run, all of which are sealed and queued for replay. Any trailing partial
+// records, which are the result of an interrupted write, are truncated.
+func (f *fileBuffer) recoverSegments() error {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+
+	var ids []uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, fbufSegmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, fbufSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		seg := &fileSegment{
+			id:     id,
+			path:   f.segmentPath(id),
+			sealed: true,
+		}
+		if err := f.scanSegment(seg); err != nil {
+			return fmt.Errorf("failed to recover segment %v: %w", seg.path, err)
+		}
+		if seg.written == 0 {
+			if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		f.log.Infof("Recovered %v unacknowledged batches from segment %v", seg.written, seg.path)
+		f.segments = append(f.segments, seg)
+		f.diskBytes += seg.size
+		f.nextID = id + 1
+	}
+	if len(f.segments) > 0 {
+		f.readID = f.segments[0].id
+	}
+	return nil
+}
+
+func (f *fileBuffer) scanSegment(seg *fileSegment) error {
+	file, err := os.OpenFile(seg.path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var off int64
+	for {
+		payload, err := readRecord(file, off)
+		if err != nil {
+			if err != io.EOF {
+				f.log.Warnf("Truncating segment %v at offset %v due to a partial or corrupt record: %v", seg.path, off, err)
+				if err := file.Truncate(off); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		off += int64(fbufRecordHeadLen + len(payload))
+		seg.written++
+	}
+	seg.size = off
+	return nil
+}
+
+func (f *fileBuffer) syncLoop() {
+	ticker := time.NewTicker(f.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.cond.L.Lock()
+			if f.head != nil && f.headDirt {
+				if err := f.head.Sync(); err != nil {
+					f.log.Errorf("Failed to sync buffer segment: %v", err)
+				}
+				f.headDirt = false
+			}
+			f.cond.L.Unlock()
+		case <-f.closeChan:
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// readRecord reads a single length prefixed and checksummed record from a file
+// at a given offset. io.EOF is returned if there are no bytes remaining,
+// io.ErrUnexpectedEOF if the record is incomplete, and errCorruptRecord if the
+// header claims a length larger than the remainder of the file.
+func readRecord(file *os.File, off int64) ([]byte, error) {
+	var head [fbufRecordHeadLen]byte
+	n, err := file.ReadAt(head[:], off)
+	if n == 0 && err == io.EOF {
+		return nil, io.EOF
+	}
+	if n < fbufRecordHeadLen {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	// The length is read from disk and therefore cannot be trusted, check it
+	// against the file before allocating anything.
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	length := int64(binary.BigEndian.Uint32(head[0:4]))
+	if remaining := info.Size() - off - fbufRecordHeadLen; length > remaining {
+		return nil, fmt.Errorf("%w: record length %v exceeds the %v bytes remaining in segment", errCorruptRecord, length, remaining)
+	}
+
+	payload := make([]byte, length)
+	if n, err = file.ReadAt(payload, off+fbufRecordHeadLen); n < len(payload) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(head[4:8]) {
+		return nil, errors.New("record checksum mismatch")
+	}
+	return payload, nil
+}
+
+func encodeRecord(b service.MessageBatch) ([]byte, error) {
+	buf := make([]byte, fbufRecordHeadLen, 256)
+	var lenBuf [binary.MaxVarintLen64]byte
+	appendUvarint := func(v uint64) {
+		n := binary.PutUvarint(lenBuf[:], v)
+		buf = append(buf, lenBuf[:n]...)
+	}
+	appendBytes := func(v []byte) {
+		appendUvarint(uint64(len(v)))
+		buf = append(buf, v...)
+	}
+
+	appendUvarint(uint64(len(b)))
+	for _, m := range b {
+		mBytes, err := m.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		appendBytes(mBytes)
+
+		var keys []string
+		var values []interface{}
+		_ = m.MetaWalkMut(func(k string, v interface{}) error {
+			keys = append(keys, k)
+			values = append(values, v)
+			return nil
+		})
+		appendUvarint(uint64(len(keys)))
+		for i, k := range keys {
+			tag, vBytes, err := encodeMetaValue(values[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode metadata key %v: %w", k, err)
+			}
+			appendBytes([]byte(k))
+			buf = append(buf, tag)
+			appendBytes(vBytes)
+		}
+	}
+
+	payload := buf[fbufRecordHeadLen:]
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	return buf, nil
+}
+
+// Type tags for metadata values, which allow typed metadata to survive a round
+// trip through the buffer.
+const (
+	fbufMetaString byte = iota
+	fbufMetaBytes
+	fbufMetaInt
+	fbufMetaUint
+	fbufMetaFloat
+	fbufMetaBool
+	fbufMetaTime
+	fbufMetaStructured
+)
+
+// normaliseMetaNumber widens numeric metadata values to int64, uint64 or
+// float64 so that they can be encoded without losing their numeric type.
+func normaliseMetaNumber(v interface{}) interface{} {
+	switch t := v.(type) {
+	case int:
+		return int64(t)
+	case int8:
+		return int64(t)
+	case int16:
+		return int64(t)
+	case int32:
+		return int64(t)
+	case uint:
+		return uint64(t)
+	case uint8:
+		return uint64(t)
+	case uint16:
+		return uint64(t)
+	case uint32:
+		return uint64(t)
+	case float32:
+		return float64(t)
+	}
+	return v
+}
+
+func encodeMetaValue(v interface{}) (byte, []byte, error) {
+	switch t := normaliseMetaNumber(v).(type) {
+	case string:
+		return fbufMetaString, []byte(t), nil
+	case []byte:
+		return fbufMetaBytes, t, nil
+	case int64:
+		return fbufMetaInt, []byte(strconv.FormatInt(t, 10)), nil
+	case uint64:
+		return fbufMetaUint, []byte(strconv.FormatUint(t, 10)), nil
+	case float64:
+		return fbufMetaFloat, []byte(strconv.FormatFloat(t, 'g', -1, 64)), nil
+	case bool:
+		return fbufMetaBool, []byte(strconv.FormatBool(t)), nil
+	case time.Time:
+		b, err := t.MarshalText()
+		return fbufMetaTime, b, err
+	}
+	b, err := json.Marshal(v)
+	return fbufMetaStructured, b, err
+}
+
+func decodeMetaValue(tag byte, b []byte) (interface{}, error) {
+	switch tag {
+	case fbufMetaString:
+		return string(b), nil
+	case fbufMetaBytes:
+		return b, nil
+	case fbufMetaInt:
+		return strconv.ParseInt(string(b), 10, 64)
+	case fbufMetaUint:
+		return strconv.ParseUint(string(b), 10, 64)
+	case fbufMetaFloat:
+		return strconv.ParseFloat(string(b), 64)
+	case fbufMetaBool:
+		return strconv.ParseBool(string(b))
+	case fbufMetaTime:
+		var t time.Time
+		err := t.UnmarshalText(b)
+		return t, err
+	case fbufMetaStructured:
+		var v interface{}
+		err := json.Unmarshal(b, &v)
+		return v, err
+	}
+	return nil, errCorruptRecord
+}
+
+var errCorruptRecord = errors.New("corrupt buffer record")
+
+func decodeRecord(payload []byte) (service.MessageBatch, error) {
+	readUvarint := func() (uint64, error) {
+		v, n := binary.Uvarint(payload)
+		if n <= 0 {
+			return 0, errCorruptRecord
+		}
+		payload = payload[n:]
+		return v, nil
+	}
+	readBytes := func() ([]byte, error) {
+		l, err := readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(payload)) < l {
+			return nil, errCorruptRecord
+		}
+		v := payload[:l:l]
+		payload = payload[l:]
+		return v, nil
+	}
+
+	count, err := readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	batch := make(service.MessageBatch, 0, count)
+	for i := uint64(0); i < count; i++ {
+		mBytes, err := readBytes()
+		if err != nil {
+			return nil, err
+		}
+		msg := service.NewMessage(mBytes)
+
+		metaCount, err := readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		for j := uint64(0); j < metaCount; j++ {
+			k, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			if len(payload) == 0 {
+				return nil, errCorruptRecord
+			}
+			tag := payload[0]
+			payload = payload[1:]
+			vBytes, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeMetaValue(tag, vBytes)
+			if err != nil {
+				return nil, err
+			}
+			msg.MetaSetMut(string(k), v)
+		}
+		batch = append(batch, msg)
+	}
+	return batch, nil
+}
+
+//------------------------------------------------------------------------------
+
+// sealHead stops writing to the current head segment, which is removed
+// immediately if it has already been fully acknowledged.
+func (f *fileBuffer) sealHead() error {
+	if f.head == nil {
+		return nil
+	}
+	var err error
+	if f.syncPolicy != fbufSyncNever {
+		err = f.head.Sync()
+	}
+	if cErr := f.head.Close(); err == nil {
+		err = cErr
+	}
+	f.head, f.headDirt = nil, false
+
+	seg := f.headSeg
+	f.headSeg = nil
+	seg.sealed = true
+	if seg.fullyAcked() || seg.written == 0 {
+		if rErr := f.removeSegment(seg); err == nil {
+			err = rErr
+		}
+	}
+	return err
+}
+
+func (f *fileBuffer) openHead() error {
+	seg := &fileSegment{
+		id:   f.nextID,
+		path: f.segmentPath(f.nextID),
+	}
+	file, err := os.OpenFile(seg.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	f.nextID++
+	f.head, f.headSeg = file, seg
+	f.segments = append(f.segments, seg)
+	return nil
+}
+
+func (f *fileBuffer) removeSegment(seg *fileSegment) error {
+	for i, s := range f.segments {
+		if s == seg {
+			f.segments = append(f.segments[:i], f.segments[i+1:]...)
+			break
+		}
+	}
+	f.diskBytes -= seg.size
+	if f.readFile != nil && f.readFID == seg.id {
+		_ = f.readFile.Close()
+		f.readFile = nil
+	}
+	if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// nextRecord attempts to read the next unread record from the log, returning
+// a nil payload if none are available.
+func (f *fileBuffer) nextRecord() ([]byte, *fileSegment, error) {
+	for _, seg := range f.segments {
+		if seg.id < f.readID {
+			continue
+		}
+		if seg.id > f.readID {
+			f.readID, f.readOff = seg.id, 0
+		}
+		if f.readOff < seg.size {
+			if f.readFile == nil || f.readFID != seg.id {
+				if f.readFile != nil {
+					_ = f.readFile.Close()
+				}
+				file, err := os.Open(seg.path)
+				if err != nil {
+					return nil, nil, err
+				}
+				f.readFile, f.readFID = file, seg.id
+			}
+			payload, err := readRecord(f.readFile, f.readOff)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read segment %v at offset %v: %w", seg.path, f.readOff, err)
+			}
+			f.readOff += int64(fbufRecordHeadLen + len(payload))
+			return payload, seg, nil
+		}
+		if !seg.sealed {
+			break
+		}
+	}
+	return nil, nil, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (f *fileBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		f.cond.Broadcast()
+	}()
+
+	f.cond.L.Lock()
+	defer f.cond.L.Unlock()
+
+	var next pendingBatch
+	for {
+		if f.closed {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		if len(f.retries) > 0 {
+			next = f.retries[0]
+			f.retries[0] = pendingBatch{}
+			f.retries = f.retries[1:]
+			break
+		}
+
+		payload, seg, err := f.nextRecord()
+		if err != nil {
+			return nil, nil, err
+		}
+		if payload != nil {
+			if next.b, err = decodeRecord(payload); err != nil {
+				return nil, nil, fmt.Errorf("failed to decode record from segment %v: %w", seg.path, err)
+			}
+			next.seg = seg
+			break
+		}
+
+		if f.endOfInput && f.pending == 0 {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		f.cond.Wait()
+	}
+
+	f.pending++
+	return next.b, func(ctx context.Context, err error) error {
+		f.cond.L.Lock()
+		defer f.cond.L.Unlock()
+
+		f.pending--
+		defer f.cond.Broadcast()
+
+		if err != nil {
+			f.retries = append(f.retries, next)
+			return nil
+		}
+
+		seg := next.seg
+		seg.acked++
+		if !seg.fullyAcked() {
+			return nil
+		}
+		if seg.sealed {
+			return f.removeSegment(seg)
+		}
+		if f.diskBytes >= f.limit {
+			// The head segment is the only thing standing in the way of new
+			// writes, and so we seal it early in order to free the space.
+			return f.sealHead()
+		}
+		return nil
+	}, nil
+}
+
+func (f *fileBuffer) WriteBatch(ctx context.Context, msgBatch service.MessageBatch, aFn service.AckFunc) error {
+	record, err := encodeRecord(msgBatch)
+	if err != nil {
+		return err
+	}
+
+	recordSize := int64(len(record))
+	if recordSize > f.segMax {
+		return component.ErrMessageTooLarge
+	}
+
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		f.cond.Broadcast()
+	}()
+
+	f.cond.L.Lock()
+	defer f.cond.L.Unlock()
+
+	for {
+		if f.closed {
+			return component.ErrTypeClosed
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if f.diskBytes+recordSize <= f.limit {
+			break
+		}
+		if f.headSeg != nil && f.headSeg.fullyAcked() {
+			if err := f.sealHead(); err != nil {
+				return err
+			}
+			continue
+		}
+		f.cond.Wait()
+	}
+
+	if f.head != nil && f.headSeg.size+recordSize > f.segMax {
+		if err := f.sealHead(); err != nil {
+			return err
+		}
+	}
+	if f.head == nil {
+		if err := f.openHead(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := f.head.Write(record); err != nil {
+		// A partial write would corrupt any subsequent records, so we abandon
+		// the segment and leave the remainder to be truncated on recovery.
+		_ = f.sealHead()
+		return err
+	}
+	if f.syncPolicy == fbufSyncAlways {
+		if err := f.head.Sync(); err != nil {
+			return err
+		}
+	} else {
+		f.headDirt = true
+	}
+
+	f.headSeg.size += recordSize
+	f.headSeg.written++
+	f.diskBytes += recordSize
+	f.cond.Broadcast()
+
+	return aFn(ctx, nil)
+}
+
+func (f *fileBuffer) EndOfInput() {
+	f.cond.L.Lock()
+	f.endOfInput = true
+	f.cond.Broadcast()
+	f.cond.L.Unlock()
+}
+
+func (f *fileBuffer) Close(ctx context.Context) error {
+	f.cond.L.Lock()
+	defer f.cond.L.Unlock()
+
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	close(f.closeChan)
+	f.cond.Broadcast()
+
+	err := f.sealHead()
+	if f.readFile != nil {
+		_ = f.readFile.Close()
+		f.readFile = nil
+	}
+	return err
+}
@@ -0,0 +1,326 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func fileBufFromConf(t *testing.T, conf string) *fileBuffer {
+	t.Helper()
+
+	parsedConf, err := fileBufferConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	buf, err := newFileBufferFromConfig(parsedConf, service.MockResources())
+	require.NoError(t, err)
+
+	return buf
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fbufSegmentExt))
+	require.NoError(t, err)
+	return files
+}
+
+func TestFileBufferBasic(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	dir := t.TempDir()
+	block := fileBufFromConf(t, fmt.Sprintf(`
+directory: %v
+`, dir))
+
+	n := 100
+	for i := 0; i < n; i++ {
+		inMsg := service.NewMessage([]byte(fmt.Sprintf("test%v", i)))
+		inMsg.MetaSet("foo", fmt.Sprintf("bar%v", i))
+		inMsg.MetaSetMut("num", int64(i))
+		inMsg.MetaSetMut("num32", int32(i))
+		inMsg.MetaSetMut("unum32", uint32(i))
+		inMsg.MetaSetMut("float32", float32(0.5))
+		inMsg.MetaSetMut("obj", map[string]interface{}{"baz": true})
+		require.NoError(t, block.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage([]byte("hello")),
+			inMsg,
+		}, noopAck))
+	}
+
+	for i := 0; i < n; i++ {
+		m, ackFunc, err := block.ReadBatch(ctx)
+		require.NoError(t, err)
+		require.Len(t, m, 2)
+		msgEqual(t, "hello", m[0])
+		msgEqual(t, fmt.Sprintf("test%v", i), m[1])
+
+		v, exists := m[1].MetaGet("foo")
+		assert.True(t, exists)
+		assert.Equal(t, fmt.Sprintf("bar%v", i), v)
+
+		num, _ := m[1].MetaGetMut("num")
+		assert.Equal(t, int64(i), num)
+
+		num32, _ := m[1].MetaGetMut("num32")
+		assert.Equal(t, int64(i), num32)
+
+		unum32, _ := m[1].MetaGetMut("unum32")
+		assert.Equal(t, uint64(i), unum32)
+
+		float32Value, _ := m[1].MetaGetMut("float32")
+		assert.Equal(t, float64(0.5), float32Value)
+
+		obj, _ := m[1].MetaGetMut("obj")
+		assert.Equal(t, map[string]interface{}{"baz": true}, obj)
+
+		require.NoError(t, ackFunc(ctx, nil))
+	}
+
+	require.NoError(t, block.Close(ctx))
+	assert.Empty(t, segmentFiles(t, dir))
+}
+
+func TestFileBufferInputAckedOnWrite(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	block := fileBufFromConf(t, fmt.Sprintf(`
+directory: %v
+`, t.TempDir()))
+	defer block.Close(ctx)
+
+	var acked bool
+	require.NoError(t, block.WriteBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte("hello")),
+	}, func(ctx context.Context, err error) error {
+		require.NoError(t, err)
+		acked = true
+		return nil
+	}))
+	assert.True(t, acked)
+}
+
+func TestFileBufferReplayUnacked(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	dir := t.TempDir()
+	conf := fmt.Sprintf(`
+directory: %v
+segment_max_bytes: 48
+limit: 100000
+`, dir)
+
+	block := fileBufFromConf(t, conf)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, block.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage([]byte(fmt.Sprintf("hello world %v", i))),
+		}, noopAck))
+	}
+
+	// Each record is 24 bytes, and therefore every two records fill a segment.
+	require.Len(t, segmentFiles(t, dir), 5)
+
+	// Fully ack the first two segments, partially ack the third.
+	for i := 0; i < 5; i++ {
+		m, ackFunc, err := block.ReadBatch(ctx)
+		require.NoError(t, err)
+		require.Len(t, m, 1)
+		msgEqual(t, fmt.Sprintf("hello world %v", i), m[0])
+		require.NoError(t, ackFunc(ctx, nil))
+	}
+	require.NoError(t, block.Close(ctx))
+	require.Len(t, segmentFiles(t, dir), 3)
+
+	block = fileBufFromConf(t, conf)
+	defer block.Close(ctx)
+
+	block.EndOfInput()
+	for i := 4; i < 10; i++ {
+		m, ackFunc, err := block.ReadBatch(ctx)
+		require.NoError(t, err)
+		require.Len(t, m, 1)
+		msgEqual(t, fmt.Sprintf("hello world %v", i), m[0])
+		require.NoError(t, ackFunc(ctx, nil))
+	}
+
+	_, _, err := block.ReadBatch(ctx)
+	assert.Equal(t, service.ErrEndOfBuffer, err)
+	assert.Empty(t, segmentFiles(t, dir))
+}
+
+func TestFileBufferNackRedelivered(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	block := fileBufFromConf(t, fmt.Sprintf(`
+directory: %v
+`, t.TempDir()))
+	defer block.Close(ctx)
+
+	for _, v := range []string{"first", "second"} {
+		require.NoError(t, block.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage([]byte(v)),
+		}, noopAck))
+	}
+
+	m, ackFunc, err := block.ReadBatch(ctx)
+	require.NoError(t, err)
+	msgEqual(t, "first", m[0])
+	require.NoError(t, ackFunc(ctx, errors.New("nope")))
+
+	m, ackFunc, err = block.ReadBatch(ctx)
+	require.NoError(t, err)
+	msgEqual(t, "first", m[0])
+	require.NoError(t, ackFunc(ctx, nil))
+
+	m, ackFunc, err = block.ReadBatch(ctx)
+	require.NoError(t, err)
+	msgEqual(t, "second", m[0])
+	require.NoError(t, ackFunc(ctx, nil))
+}
+
+func TestFileBufferTruncatedTail(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	dir := t.TempDir()
+	conf := fmt.Sprintf(`
+directory: %v
+`, dir)
+
+	block := fileBufFromConf(t, conf)
+	for _, v := range []string{"first", "second"} {
+		require.NoError(t, block.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage([]byte(v)),
+		}, noopAck))
+	}
+	require.NoError(t, block.Close(ctx))
+
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 1)
+
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(files[0], info.Size()-3))
+
+	block = fileBufFromConf(t, conf)
+	defer block.Close(ctx)
+
+	block.EndOfInput()
+
+	m, ackFunc, err := block.ReadBatch(ctx)
+	require.NoError(t, err)
+	msgEqual(t, "first", m[0])
+	require.NoError(t, ackFunc(ctx, nil))
+
+	_, _, err = block.ReadBatch(ctx)
+	assert.Equal(t, service.ErrEndOfBuffer, err)
+}
+
+func TestFileBufferCorruptLength(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	dir := t.TempDir()
+	conf := fmt.Sprintf(`
+directory: %v
+`, dir)
+
+	block := fileBufFromConf(t, conf)
+	for _, v := range []string{"first", "second"} {
+		require.NoError(t, block.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage([]byte(v)),
+		}, noopAck))
+	}
+	require.NoError(t, block.Close(ctx))
+
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 1)
+
+	file, err := os.OpenFile(files[0], os.O_RDWR, 0)
+	require.NoError(t, err)
+
+	// Overwrite the length of the second record with an absurd value.
+	payload, err := readRecord(file, 0)
+	require.NoError(t, err)
+	_, err = file.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, int64(fbufRecordHeadLen+len(payload)))
+	require.NoError(t, err)
+
+	_, err = readRecord(file, int64(fbufRecordHeadLen+len(payload)))
+	assert.True(t, errors.Is(err, errCorruptRecord), err)
+	require.NoError(t, file.Close())
+
+	block = fileBufFromConf(t, conf)
+	defer block.Close(ctx)
+
+	block.EndOfInput()
+
+	m, ackFunc, err := block.ReadBatch(ctx)
+	require.NoError(t, err)
+	msgEqual(t, "first", m[0])
+	require.NoError(t, ackFunc(ctx, nil))
+
+	_, _, err = block.ReadBatch(ctx)
+	assert.Equal(t, service.ErrEndOfBuffer, err)
+}
+
+func TestFileBufferBackPressure(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	block := fileBufFromConf(t, fmt.Sprintf(`
+directory: %v
+segment_max_bytes: 50
+limit: 100
+`, t.TempDir()))
+	defer block.Close(ctx)
+
+	payload := []byte("this is a thirty byte message.")
+	for i := 0; i < 2; i++ {
+		require.NoError(t, block.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage(payload),
+		}, noopAck))
+	}
+
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- block.WriteBatch(ctx, service.MessageBatch{
+			service.NewMessage(payload),
+		}, noopAck)
+	}()
+
+	select {
+	case err := <-writeErr:
+		t.Fatalf("Expected write to block, got: %v", err)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	m, ackFunc, err := block.ReadBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	require.NoError(t, ackFunc(ctx, nil))
+
+	select {
+	case err := <-writeErr:
+		require.NoError(t, err)
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	}
+
+	largeMsg := service.NewMessage(make([]byte, 100))
+	assert.Equal(t, component.ErrMessageTooLarge, block.WriteBatch(ctx, service.MessageBatch{largeMsg}, noopAck))
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -424,9 +423,9 @@ func recordToMessage(record *kgo.Record) *service.Message {
 	msg := service.NewMessage(record.Value)
 	msg.MetaSet("kafka_key", string(record.Key))
 	msg.MetaSet("kafka_topic", record.Topic)
-	msg.MetaSet("kafka_partition", strconv.Itoa(int(record.Partition)))
-	msg.MetaSet("kafka_offset", strconv.Itoa(int(record.Offset)))
-	msg.MetaSet("kafka_timestamp_unix", strconv.FormatInt(record.Timestamp.Unix(), 10))
+	msg.MetaSetMut("kafka_partition", int64(record.Partition))
+	msg.MetaSetMut("kafka_offset", record.Offset)
+	msg.MetaSetMut("kafka_timestamp_unix", record.Timestamp.Unix())
 	for _, hdr := range record.Headers {
 		msg.MetaSet(hdr.Key, string(hdr.Value))
 	}
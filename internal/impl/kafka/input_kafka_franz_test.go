@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestRecordToMessageMetadata(t *testing.T) {
+	msg := recordToMessage(&kgo.Record{
+		Key:       []byte("foo"),
+		Value:     []byte("hello world"),
+		Topic:     "bar",
+		Partition: 3,
+		Offset:    42,
+		Timestamp: time.Unix(1600000000, 0),
+		Headers: []kgo.RecordHeader{
+			{Key: "baz", Value: []byte("buz")},
+		},
+	})
+
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+
+	meta := map[string]interface{}{}
+	require.NoError(t, msg.MetaWalkMut(func(k string, v interface{}) error {
+		meta[k] = v
+		return nil
+	}))
+	assert.Equal(t, map[string]interface{}{
+		"kafka_key":            "foo",
+		"kafka_topic":          "bar",
+		"kafka_partition":      int64(3),
+		"kafka_offset":         int64(42),
+		"kafka_timestamp_unix": int64(1600000000),
+		"baz":                  "buz",
+	}, meta)
+
+	partition, _ := msg.MetaGet("kafka_partition")
+	assert.Equal(t, "3", partition)
+}
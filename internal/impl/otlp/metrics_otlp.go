@@ -0,0 +1,467 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/otlpconfig"
+)
+
+func init() {
+	_ = bundle.AllMetrics.Add(newOTLPMetrics, docs.ComponentSpec{
+		Name:    "open_telemetry_collector",
+		Type:    docs.TypeMetrics,
+		Status:  docs.StatusBeta,
+		Version: "4.0.0",
+		Summary: `Push metrics to an [OpenTelemetry collector](https://opentelemetry.io/docs/collector/) using the OTLP protocol.`,
+		Description: `
+Metrics are collected and pushed to each listed endpoint periodically at the interval specified by ` + "`push_interval`" + `, and once more when Benthos shuts down.
+
+Counters are exported as monotonic sums and gauges as gauges. Timing metrics are exported as histograms, where the delta values are converted from nanoseconds into seconds in order to better fit within the default bucket definitions. Labels of metrics are exported as attributes.
+
+All ` + "`tags`" + ` are added to metrics as resource attributes. The attribute ` + "`service.name`" + ` defaults to ` + "`benthos`" + ` and can be overridden with a tag of the same name.`,
+		Config: docs.FieldComponent().WithChildren(
+			docs.FieldObject("http", "A list of HTTP collector endpoints to push metrics to.").Array().WithChildren(
+				endpointFieldSpecs("localhost:4318")...,
+			).HasDefault([]interface{}{}),
+			docs.FieldObject("grpc", "A list of gRPC collector endpoints to push metrics to.").Array().WithChildren(
+				endpointFieldSpecs("localhost:4317")...,
+			).HasDefault([]interface{}{}),
+			docs.FieldString("push_interval", "The period of time between each push of metrics.").HasDefault("10s"),
+			docs.FieldString("tags", "A map of tags to add to all metrics as resource attributes.").Map().Advanced().HasDefault(map[string]interface{}{}),
+		),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+func labelAttrs(names, values []string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(names))
+	for i, n := range names {
+		var v string
+		if i < len(values) {
+			v = values[i]
+		}
+		attrs = append(attrs, attribute.String(n, v))
+	}
+	return attrs
+}
+
+type otlpCounter struct {
+	ctrs  []metric.Int64Counter
+	attrs []attribute.KeyValue
+}
+
+func (o *otlpCounter) Incr(count int64) {
+	for _, c := range o.ctrs {
+		c.Add(context.Background(), count, o.attrs...)
+	}
+}
+
+type otlpCounterVec struct {
+	ctrs       []metric.Int64Counter
+	labelNames []string
+}
+
+func (o *otlpCounterVec) With(labelValues ...string) metrics.StatCounter {
+	return &otlpCounter{
+		ctrs:  o.ctrs,
+		attrs: labelAttrs(o.labelNames, labelValues),
+	}
+}
+
+type otlpTimer struct {
+	hists []metric.Float64Histogram
+	attrs []attribute.KeyValue
+}
+
+func (o *otlpTimer) Timing(delta int64) {
+	secs := float64(delta) / 1_000_000_000
+	for _, h := range o.hists {
+		h.Record(context.Background(), secs, o.attrs...)
+	}
+}
+
+type otlpTimerVec struct {
+	hists      []metric.Float64Histogram
+	labelNames []string
+}
+
+func (o *otlpTimerVec) With(labelValues ...string) metrics.StatTimer {
+	return &otlpTimer{
+		hists: o.hists,
+		attrs: labelAttrs(o.labelNames, labelValues),
+	}
+}
+
+// OpenTelemetry only supports gauges as asynchronous instruments, and therefore
+// gauge values are stored and observed whenever metrics are collected.
+type otlpGauge struct {
+	value int64
+	attrs []attribute.KeyValue
+}
+
+func (o *otlpGauge) Set(value int64) {
+	atomic.StoreInt64(&o.value, value)
+}
+
+func (o *otlpGauge) Incr(count int64) {
+	atomic.AddInt64(&o.value, count)
+}
+
+func (o *otlpGauge) Decr(count int64) {
+	atomic.AddInt64(&o.value, -count)
+}
+
+// otlpGauges holds every gauge of a metric path so that they can be observed
+// by a single instrument, regardless of the label names each vec was created
+// with.
+type otlpGauges struct {
+	mut    sync.Mutex
+	gauges map[string]*otlpGauge
+}
+
+func newOTLPGauges() *otlpGauges {
+	return &otlpGauges{
+		gauges: map[string]*otlpGauge{},
+	}
+}
+
+func (o *otlpGauges) get(labelNames, labelValues []string) *otlpGauge {
+	attrs := labelAttrs(labelNames, labelValues)
+
+	var keyB strings.Builder
+	for _, a := range attrs {
+		keyB.WriteString(string(a.Key))
+		keyB.WriteByte(0)
+		keyB.WriteString(a.Value.AsString())
+		keyB.WriteByte(0)
+	}
+	key := keyB.String()
+
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	g, exists := o.gauges[key]
+	if !exists {
+		g = &otlpGauge{attrs: attrs}
+		o.gauges[key] = g
+	}
+	return g
+}
+
+func (o *otlpGauges) observe(_ context.Context, res metric.Int64ObserverResult) {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	for _, g := range o.gauges {
+		res.Observe(atomic.LoadInt64(&g.value), g.attrs...)
+	}
+}
+
+type otlpGaugeVec struct {
+	gauges     *otlpGauges
+	labelNames []string
+}
+
+func (o *otlpGaugeVec) With(labelValues ...string) metrics.StatGauge {
+	return o.gauges.get(o.labelNames, labelValues)
+}
+
+//------------------------------------------------------------------------------
+
+type otlpMetrics struct {
+	log         log.Modular
+	exporters   []*otlpmetric.Exporter
+	controllers []*controller.Controller
+	meters      []metric.Meter
+
+	mut      sync.Mutex
+	counters map[string][]metric.Int64Counter
+	timers   map[string][]metric.Float64Histogram
+	gauges   map[string]*otlpGauges
+}
+
+func newOTLPMetrics(config metrics.Config, log log.Modular) (metrics.Type, error) {
+	conf := config.OpenTelemetryCollector
+	if len(conf.HTTP) == 0 && len(conf.GRPC) == 0 {
+		return nil, errors.New("at least one http or grpc endpoint must be specified")
+	}
+
+	pushInterval, err := time.ParseDuration(conf.PushInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse push interval: %v", err)
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceNameKey.String("benthos")}
+	for k, v := range conf.Tags {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL, attrs...)
+
+	var clients []otlpmetric.Client
+	for _, ep := range conf.HTTP {
+		timeout, err := endpointTimeout(ep)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, newMetricHTTPClient(ep, timeout))
+	}
+	for _, ep := range conf.GRPC {
+		timeout, err := endpointTimeout(ep)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, newMetricGRPCClient(ep, timeout))
+	}
+
+	o := &otlpMetrics{
+		log:      log,
+		counters: map[string][]metric.Int64Counter{},
+		timers:   map[string][]metric.Float64Histogram{},
+		gauges:   map[string]*otlpGauges{},
+	}
+
+	ctx := context.Background()
+	for _, client := range clients {
+		exp, err := otlpmetric.New(ctx, client)
+		if err != nil {
+			_ = o.Close()
+			return nil, fmt.Errorf("failed to create metrics exporter: %w", err)
+		}
+
+		cont := controller.New(
+			processor.NewFactory(simple.NewWithHistogramDistribution(), exp),
+			controller.WithExporter(exp),
+			controller.WithCollectPeriod(pushInterval),
+			controller.WithResource(res),
+		)
+		if err := cont.Start(ctx); err != nil {
+			_ = exp.Shutdown(ctx)
+			_ = o.Close()
+			return nil, fmt.Errorf("failed to start metrics controller: %w", err)
+		}
+
+		o.exporters = append(o.exporters, exp)
+		o.controllers = append(o.controllers, cont)
+		o.meters = append(o.meters, cont.Meter("benthos"))
+	}
+	return o, nil
+}
+
+func newMetricHTTPClient(ep otlpconfig.EndpointConfig, timeout time.Duration) otlpmetric.Client {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithTimeout(timeout),
+	}
+	if ep.URL != "" {
+		opts = append(opts, otlpmetrichttp.WithEndpoint(ep.URL))
+	}
+	if ep.Insecure {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if len(ep.Headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(ep.Headers))
+	}
+	return otlpmetrichttp.NewClient(opts...)
+}
+
+func newMetricGRPCClient(ep otlpconfig.EndpointConfig, timeout time.Duration) otlpmetric.Client {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithTimeout(timeout),
+	}
+	if ep.URL != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(ep.URL))
+	}
+	if ep.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if len(ep.Headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(ep.Headers))
+	}
+	return otlpmetricgrpc.NewClient(opts...)
+}
+
+//------------------------------------------------------------------------------
+
+func (o *otlpMetrics) HandlerFunc() http.HandlerFunc {
+	return nil
+}
+
+// checkPathUnused returns an error if a metric path has already been registered
+// as an instrument of a different kind. The meters of all endpoints reject
+// conflicting instruments, and therefore checking this before creating any
+// instruments prevents a metric from being registered with only some of them.
+func (o *otlpMetrics) checkPathUnused(path string) error {
+	if _, exists := o.counters[path]; exists {
+		return fmt.Errorf("metric '%v' is already registered as a counter", path)
+	}
+	if _, exists := o.timers[path]; exists {
+		return fmt.Errorf("metric '%v' is already registered as a timer", path)
+	}
+	if _, exists := o.gauges[path]; exists {
+		return fmt.Errorf("metric '%v' is already registered as a gauge", path)
+	}
+	return nil
+}
+
+func (o *otlpMetrics) GetCounter(path string) metrics.StatCounter {
+	return o.GetCounterVec(path).With()
+}
+
+func (o *otlpMetrics) GetCounterVec(path string, labelNames ...string) metrics.StatCounterVec {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	ctrs, exists := o.counters[path]
+	if !exists {
+		var err error
+		if ctrs, err = o.newCounters(path); err != nil {
+			o.log.Errorf("Ignoring metric '%v' due to error: %v", path, err)
+			return metrics.FakeCounterVec(func(l ...string) metrics.StatCounter {
+				return metrics.DudStat{}
+			})
+		}
+		o.counters[path] = ctrs
+	}
+
+	return &otlpCounterVec{
+		ctrs:       ctrs,
+		labelNames: labelNames,
+	}
+}
+
+func (o *otlpMetrics) GetTimer(path string) metrics.StatTimer {
+	return o.GetTimerVec(path).With()
+}
+
+func (o *otlpMetrics) GetTimerVec(path string, labelNames ...string) metrics.StatTimerVec {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	hists, exists := o.timers[path]
+	if !exists {
+		var err error
+		if hists, err = o.newHistograms(path); err != nil {
+			o.log.Errorf("Ignoring metric '%v' due to error: %v", path, err)
+			return metrics.FakeTimerVec(func(l ...string) metrics.StatTimer {
+				return metrics.DudStat{}
+			})
+		}
+		o.timers[path] = hists
+	}
+
+	return &otlpTimerVec{
+		hists:      hists,
+		labelNames: labelNames,
+	}
+}
+
+func (o *otlpMetrics) GetGauge(path string) metrics.StatGauge {
+	return o.GetGaugeVec(path).With()
+}
+
+func (o *otlpMetrics) GetGaugeVec(path string, labelNames ...string) metrics.StatGaugeVec {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	gauges, exists := o.gauges[path]
+	if !exists {
+		var err error
+		if gauges, err = o.newGauges(path); err != nil {
+			o.log.Errorf("Ignoring metric '%v' due to error: %v", path, err)
+			return metrics.FakeGaugeVec(func(l ...string) metrics.StatGauge {
+				return metrics.DudStat{}
+			})
+		}
+		o.gauges[path] = gauges
+	}
+
+	return &otlpGaugeVec{
+		gauges:     gauges,
+		labelNames: labelNames,
+	}
+}
+
+// newCounters creates a counter instrument for each meter, and must be called
+// with the mutex held.
+func (o *otlpMetrics) newCounters(path string) ([]metric.Int64Counter, error) {
+	if err := o.checkPathUnused(path); err != nil {
+		return nil, err
+	}
+	ctrs := make([]metric.Int64Counter, 0, len(o.meters))
+	for _, m := range o.meters {
+		ctr, err := m.NewInt64Counter(path, metric.WithDescription("Benthos Counter metric"))
+		if err != nil {
+			return nil, err
+		}
+		ctrs = append(ctrs, ctr)
+	}
+	return ctrs, nil
+}
+
+// newHistograms creates a histogram instrument for each meter, and must be
+// called with the mutex held.
+func (o *otlpMetrics) newHistograms(path string) ([]metric.Float64Histogram, error) {
+	if err := o.checkPathUnused(path); err != nil {
+		return nil, err
+	}
+	hists := make([]metric.Float64Histogram, 0, len(o.meters))
+	for _, m := range o.meters {
+		hist, err := m.NewFloat64Histogram(path, metric.WithDescription("Benthos Timing metric in seconds"))
+		if err != nil {
+			return nil, err
+		}
+		hists = append(hists, hist)
+	}
+	return hists, nil
+}
+
+// newGauges creates a gauge observer for each meter, and must be called with
+// the mutex held.
+func (o *otlpMetrics) newGauges(path string) (*otlpGauges, error) {
+	if err := o.checkPathUnused(path); err != nil {
+		return nil, err
+	}
+	gauges := newOTLPGauges()
+	for _, m := range o.meters {
+		if _, err := m.NewInt64GaugeObserver(path, gauges.observe, metric.WithDescription("Benthos Gauge metric")); err != nil {
+			return nil, err
+		}
+	}
+	return gauges, nil
+}
+
+func (o *otlpMetrics) Close() error {
+	ctx := context.Background()
+	for _, c := range o.controllers {
+		if err := c.Stop(ctx); err != nil {
+			o.log.Errorf("Failed to flush metrics: %v", err)
+		}
+	}
+	for _, e := range o.exporters {
+		_ = e.Shutdown(ctx)
+	}
+	o.controllers, o.exporters = nil, nil
+	return nil
+}
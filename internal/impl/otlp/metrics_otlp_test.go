@@ -0,0 +1,164 @@
+package otlp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/otlpconfig"
+)
+
+func TestOTLPMetricsConfErrors(t *testing.T) {
+	conf := metrics.NewConfig()
+	conf.Type = "open_telemetry_collector"
+
+	_, err := newOTLPMetrics(conf, log.Noop())
+	require.Error(t, err)
+
+	ep := otlpconfig.NewEndpointConfig()
+	ep.URL = "localhost:4317"
+	conf.OpenTelemetryCollector.GRPC = append(conf.OpenTelemetryCollector.GRPC, ep)
+	conf.OpenTelemetryCollector.PushInterval = "not a duration"
+
+	_, err = newOTLPMetrics(conf, log.Noop())
+	require.Error(t, err)
+
+	conf.OpenTelemetryCollector.GRPC[0].Timeout = "not a duration"
+	conf.OpenTelemetryCollector.PushInterval = "10s"
+
+	_, err = newOTLPMetrics(conf, log.Noop())
+	require.Error(t, err)
+}
+
+func TestOTLPMetricsGaugeVec(t *testing.T) {
+	vec := &otlpGaugeVec{
+		gauges:     newOTLPGauges(),
+		labelNames: []string{"foo", "bar"},
+	}
+
+	vec.With("a", "b").Set(10)
+	vec.With("a", "b").Incr(5)
+	vec.With("a", "c").Incr(3)
+	vec.With("a", "c").Decr(1)
+
+	require.Len(t, vec.gauges.gauges, 2)
+
+	g := vec.With("a", "b").(*otlpGauge)
+	assert.Equal(t, int64(15), g.value)
+	assert.Equal(t, "foo", string(g.attrs[0].Key))
+	assert.Equal(t, "a", g.attrs[0].Value.AsString())
+	assert.Equal(t, "bar", string(g.attrs[1].Key))
+	assert.Equal(t, "b", g.attrs[1].Value.AsString())
+
+	g = vec.With("a", "c").(*otlpGauge)
+	assert.Equal(t, int64(2), g.value)
+}
+
+func TestOTLPMetricsGaugeVecLabelNames(t *testing.T) {
+	gauges := newOTLPGauges()
+
+	vecA := &otlpGaugeVec{gauges: gauges, labelNames: []string{"foo"}}
+	vecB := &otlpGaugeVec{gauges: gauges, labelNames: []string{"bar"}}
+
+	vecA.With("a").Set(1)
+	vecB.With("a").Set(2)
+
+	require.Len(t, gauges.gauges, 2)
+
+	g := vecA.With("a").(*otlpGauge)
+	assert.Equal(t, int64(1), g.value)
+	assert.Equal(t, "foo", string(g.attrs[0].Key))
+
+	g = vecB.With("a").(*otlpGauge)
+	assert.Equal(t, int64(2), g.value)
+	assert.Equal(t, "bar", string(g.attrs[0].Key))
+}
+
+func TestOTLPMetricsHTTP(t *testing.T) {
+	var reqMut sync.Mutex
+	var paths, authHeaders []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqMut.Lock()
+		paths = append(paths, r.URL.Path)
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		reqMut.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := metrics.NewConfig()
+	conf.Type = "open_telemetry_collector"
+
+	ep := otlpconfig.NewEndpointConfig()
+	ep.URL = strings.TrimPrefix(server.URL, "http://")
+	ep.Insecure = true
+	ep.Headers["Authorization"] = "Bearer foo"
+	conf.OpenTelemetryCollector.HTTP = append(conf.OpenTelemetryCollector.HTTP, ep)
+	conf.OpenTelemetryCollector.PushInterval = "1h"
+
+	m, err := newOTLPMetrics(conf, log.Noop())
+	require.NoError(t, err)
+
+	assert.Nil(t, m.HandlerFunc())
+
+	m.GetCounter("counter_foo").Incr(10)
+	m.GetCounterVec("counter_bar", "label").With("baz").Incr(1)
+	m.GetTimer("timer_foo").Timing(1000)
+	m.GetGaugeVec("gauge_foo", "label").With("baz").Set(5)
+
+	// Closing the metrics type pushes all metrics one last time.
+	require.NoError(t, m.Close())
+
+	reqMut.Lock()
+	defer reqMut.Unlock()
+
+	require.NotEmpty(t, paths)
+	assert.Equal(t, "/v1/metrics", paths[0])
+	assert.Equal(t, "Bearer foo", authHeaders[0])
+}
+
+func TestOTLPMetricsConflictingKinds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := metrics.NewConfig()
+	conf.Type = "open_telemetry_collector"
+
+	ep := otlpconfig.NewEndpointConfig()
+	ep.URL = strings.TrimPrefix(server.URL, "http://")
+	ep.Insecure = true
+	conf.OpenTelemetryCollector.HTTP = append(conf.OpenTelemetryCollector.HTTP, ep, ep)
+	conf.OpenTelemetryCollector.PushInterval = "1h"
+
+	m, err := newOTLPMetrics(conf, log.Noop())
+	require.NoError(t, err)
+	defer m.Close()
+
+	o := m.(*otlpMetrics)
+
+	o.GetCounter("foo").Incr(1)
+	require.Len(t, o.counters["foo"], 2)
+
+	// A metric path registered as a counter cannot also be a timer or gauge,
+	// and the conflict must not register anything with the meters.
+	o.GetTimer("foo").Timing(1)
+	o.GetGaugeVec("foo", "label").With("bar").Set(1)
+	assert.NotContains(t, o.timers, "foo")
+	assert.NotContains(t, o.gauges, "foo")
+
+	o.GetGauge("bar").Set(1)
+	require.Contains(t, o.gauges, "bar")
+
+	o.GetCounter("bar").Incr(1)
+	assert.NotContains(t, o.counters, "bar")
+}
@@ -0,0 +1,225 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component/tracer"
+	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/otlpconfig"
+)
+
+//------------------------------------------------------------------------------
+
+func endpointFieldSpecs(defaultURL string) []docs.FieldSpec {
+	return []docs.FieldSpec{
+		docs.FieldString("url", "The host and port of the collector endpoint. When empty the default endpoint of the protocol (`"+defaultURL+"`) is used.", defaultURL).HasDefault(""),
+		docs.FieldBool("insecure", "Whether to connect to the collector without TLS.").HasDefault(false),
+		docs.FieldString("headers", "A map of headers to add to each export request, which is commonly used for authentication.").Map().HasDefault(map[string]interface{}{}),
+		docs.FieldString("timeout", "The maximum period of time to wait for each export request to the endpoint to complete.").Advanced().HasDefault("10s"),
+	}
+}
+
+func endpointTimeout(ep otlpconfig.EndpointConfig) (time.Duration, error) {
+	timeout, err := time.ParseDuration(ep.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse timeout of endpoint '%v': %w", ep.URL, err)
+	}
+	return timeout, nil
+}
+
+func init() {
+	_ = bundle.AllTracers.Add(NewOpenTelemetryCollector, docs.ComponentSpec{
+		Name:    "open_telemetry_collector",
+		Type:    docs.TypeTracer,
+		Status:  docs.StatusBeta,
+		Version: "4.0.0",
+		Summary: `Send tracing events to an [OpenTelemetry collector](https://opentelemetry.io/docs/collector/) using the OTLP protocol.`,
+		Description: `
+Spans can be exported over HTTP, gRPC or both, and each endpoint listed is sent every span. Spans are batched before being exported, and the batching behaviour can be tuned with the ` + "`batch`" + ` fields.
+
+All ` + "`tags`" + ` are added to spans as resource attributes. The attribute ` + "`service.name`" + ` defaults to ` + "`benthos`" + ` and can be overridden with a tag of the same name.`,
+		Config: docs.FieldObject("", "").WithChildren(
+			docs.FieldObject("http", "A list of HTTP collector endpoints to send tracing events to.").Array().WithChildren(
+				endpointFieldSpecs("localhost:4318")...,
+			).HasDefault([]interface{}{}),
+			docs.FieldObject("grpc", "A list of gRPC collector endpoints to send tracing events to.").Array().WithChildren(
+				endpointFieldSpecs("localhost:4317")...,
+			).HasDefault([]interface{}{}),
+			docs.FieldString("tags", "A map of tags to add to all tracing spans as resource attributes.").Map().Advanced().HasDefault(map[string]interface{}{}),
+			docs.FieldObject("sampling", "Settings for trace sampling. When disabled all traces are sampled.").WithChildren(
+				docs.FieldBool("enabled", "Whether to enable sampling.").HasDefault(false),
+				docs.FieldFloat("ratio", "The ratio of traces to sample, between 0 and 1. Traces with a sampled parent span are always sampled.", 0.85, 0.5).HasDefault(1.0),
+			).Advanced(),
+			docs.FieldObject("batch", "Settings for the batching of spans before they are exported.").WithChildren(
+				docs.FieldInt("max_queue_size", "The maximum number of spans to buffer before they are dropped.").HasDefault(2048),
+				docs.FieldInt("max_export_batch_size", "The maximum number of spans to export in a single request.").HasDefault(512),
+				docs.FieldString("flush_interval", "The maximum period of time to wait before exporting buffered spans.").HasDefault("5s"),
+				docs.FieldString("export_timeout", "The maximum period of time to wait for an export request to complete.").HasDefault("30s"),
+			).Advanced(),
+		),
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// OpenTelemetryCollector is a tracer with the capability to push spans to one
+// or more OpenTelemetry collectors.
+type OpenTelemetryCollector struct {
+	prov *tracesdk.TracerProvider
+}
+
+// NewOpenTelemetryCollector creates and returns a new OpenTelemetryCollector
+// object.
+func NewOpenTelemetryCollector(config tracer.Config) (tracer.Type, error) {
+	conf := config.OpenTelemetryCollector
+	if len(conf.HTTP) == 0 && len(conf.GRPC) == 0 {
+		return nil, errors.New("at least one http or grpc endpoint must be specified")
+	}
+
+	batchOpts, err := batchOptions(conf.Batch)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceNameKey.String("benthos")}
+	for k, v := range conf.Tags {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	opts := []tracesdk.TracerProviderOption{
+		tracesdk.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+	}
+	if conf.Sampling.Enabled {
+		if conf.Sampling.Ratio < 0 || conf.Sampling.Ratio > 1 {
+			return nil, fmt.Errorf("sampling ratio must be between 0 and 1, got %v", conf.Sampling.Ratio)
+		}
+		opts = append(opts, tracesdk.WithSampler(tracesdk.ParentBased(tracesdk.TraceIDRatioBased(conf.Sampling.Ratio))))
+	}
+
+	ctx := context.Background()
+	var exporters []*otlptrace.Exporter
+	closeExporters := func() {
+		for _, exp := range exporters {
+			_ = exp.Shutdown(ctx)
+		}
+	}
+
+	for _, ep := range conf.HTTP {
+		timeout, err := endpointTimeout(ep)
+		if err != nil {
+			closeExporters()
+			return nil, err
+		}
+		exp, err := otlptrace.New(ctx, newHTTPClient(ep, timeout))
+		if err != nil {
+			closeExporters()
+			return nil, fmt.Errorf("failed to create http exporter for '%v': %w", ep.URL, err)
+		}
+		exporters = append(exporters, exp)
+	}
+	for _, ep := range conf.GRPC {
+		timeout, err := endpointTimeout(ep)
+		if err != nil {
+			closeExporters()
+			return nil, err
+		}
+		exp, err := otlptrace.New(ctx, newGRPCClient(ep, timeout))
+		if err != nil {
+			closeExporters()
+			return nil, fmt.Errorf("failed to create grpc exporter for '%v': %w", ep.URL, err)
+		}
+		exporters = append(exporters, exp)
+	}
+	for _, exp := range exporters {
+		opts = append(opts, tracesdk.WithBatcher(exp, batchOpts...))
+	}
+
+	tp := tracesdk.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return &OpenTelemetryCollector{prov: tp}, nil
+}
+
+func newHTTPClient(ep otlpconfig.EndpointConfig, timeout time.Duration) otlptrace.Client {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithTimeout(timeout),
+	}
+	if ep.URL != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(ep.URL))
+	}
+	if ep.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(ep.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(ep.Headers))
+	}
+	return otlptracehttp.NewClient(opts...)
+}
+
+func newGRPCClient(ep otlpconfig.EndpointConfig, timeout time.Duration) otlptrace.Client {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithTimeout(timeout),
+	}
+	if ep.URL != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(ep.URL))
+	}
+	if ep.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if len(ep.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(ep.Headers))
+	}
+	return otlptracegrpc.NewClient(opts...)
+}
+
+func batchOptions(conf tracer.OTLPBatchConfig) ([]tracesdk.BatchSpanProcessorOption, error) {
+	var opts []tracesdk.BatchSpanProcessorOption
+	if conf.MaxQueueSize > 0 {
+		opts = append(opts, tracesdk.WithMaxQueueSize(conf.MaxQueueSize))
+	}
+	if conf.MaxExportBatchSize > 0 {
+		opts = append(opts, tracesdk.WithMaxExportBatchSize(conf.MaxExportBatchSize))
+	}
+	if i := conf.FlushInterval; len(i) > 0 {
+		flushInterval, err := time.ParseDuration(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse flush interval '%s': %v", i, err)
+		}
+		opts = append(opts, tracesdk.WithBatchTimeout(flushInterval))
+	}
+	if t := conf.ExportTimeout; len(t) > 0 {
+		exportTimeout, err := time.ParseDuration(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse export timeout '%s': %v", t, err)
+		}
+		opts = append(opts, tracesdk.WithExportTimeout(exportTimeout))
+	}
+	return opts, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Close stops the tracer, flushing any remaining spans.
+func (o *OpenTelemetryCollector) Close() error {
+	if o.prov != nil {
+		_ = o.prov.Shutdown(context.Background())
+		o.prov = nil
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package otlp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"github.com/benthosdev/benthos/v4/internal/component/tracer"
+	"github.com/benthosdev/benthos/v4/internal/otlpconfig"
+)
+
+func TestOTLPTracerConfErrors(t *testing.T) {
+	conf := tracer.NewConfig()
+	conf.Type = "open_telemetry_collector"
+
+	_, err := NewOpenTelemetryCollector(conf)
+	require.Error(t, err)
+
+	ep := otlpconfig.NewEndpointConfig()
+	ep.URL = "localhost:4318"
+	conf.OpenTelemetryCollector.HTTP = append(conf.OpenTelemetryCollector.HTTP, ep)
+	conf.OpenTelemetryCollector.Batch.FlushInterval = "not a duration"
+
+	_, err = NewOpenTelemetryCollector(conf)
+	require.Error(t, err)
+
+	conf.OpenTelemetryCollector.Batch.FlushInterval = "1s"
+	conf.OpenTelemetryCollector.Sampling.Enabled = true
+	conf.OpenTelemetryCollector.Sampling.Ratio = 1.5
+
+	_, err = NewOpenTelemetryCollector(conf)
+	require.Error(t, err)
+}
+
+func TestOTLPTracerGRPC(t *testing.T) {
+	conf := tracer.NewConfig()
+	conf.Type = "open_telemetry_collector"
+
+	ep := otlpconfig.NewEndpointConfig()
+	ep.URL = "localhost:4317"
+	ep.Insecure = true
+	conf.OpenTelemetryCollector.GRPC = append(conf.OpenTelemetryCollector.GRPC, ep)
+	conf.OpenTelemetryCollector.Sampling.Enabled = true
+	conf.OpenTelemetryCollector.Sampling.Ratio = 0.5
+
+	tr, err := NewOpenTelemetryCollector(conf)
+	require.NoError(t, err)
+	require.NoError(t, tr.Close())
+}
+
+func TestOTLPTracerHTTP(t *testing.T) {
+	var reqMut sync.Mutex
+	var paths, authHeaders []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqMut.Lock()
+		paths = append(paths, r.URL.Path)
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		reqMut.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := tracer.NewConfig()
+	conf.Type = "open_telemetry_collector"
+
+	ep := otlpconfig.NewEndpointConfig()
+	ep.URL = strings.TrimPrefix(server.URL, "http://")
+	ep.Insecure = true
+	ep.Headers["Authorization"] = "Bearer foo"
+	conf.OpenTelemetryCollector.HTTP = append(conf.OpenTelemetryCollector.HTTP, ep)
+	conf.OpenTelemetryCollector.Tags["service.name"] = "test"
+
+	tr, err := NewOpenTelemetryCollector(conf)
+	require.NoError(t, err)
+
+	_, span := otel.GetTracerProvider().Tracer("test").Start(context.Background(), "foo")
+	span.End()
+
+	// Closing the tracer flushes all pending spans.
+	require.NoError(t, tr.Close())
+
+	reqMut.Lock()
+	defer reqMut.Unlock()
+
+	require.NotEmpty(t, paths)
+	assert.Equal(t, "/v1/traces", paths[0])
+	assert.Equal(t, "Bearer foo", authHeaders[0])
+}
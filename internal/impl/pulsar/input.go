@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"sync"
 	"time"
 
@@ -245,8 +244,8 @@ func (p *pulsarReader) Read(ctx context.Context) (*service.Message, service.AckF
 
 	msg.MetaSet("pulsar_message_id", string(pulMsg.ID().Serialize()))
 	msg.MetaSet("pulsar_topic", pulMsg.Topic())
-	msg.MetaSet("pulsar_publish_time_unix", strconv.FormatInt(pulMsg.PublishTime().Unix(), 10))
-	msg.MetaSet("pulsar_redelivery_count", strconv.FormatInt(int64(pulMsg.RedeliveryCount()), 10))
+	msg.MetaSetMut("pulsar_publish_time_unix", pulMsg.PublishTime().Unix())
+	msg.MetaSetMut("pulsar_redelivery_count", int64(pulMsg.RedeliveryCount()))
 	if key := pulMsg.Key(); len(key) > 0 {
 		msg.MetaSet("pulsar_key", key)
 	}
@@ -254,7 +253,7 @@ func (p *pulsarReader) Read(ctx context.Context) (*service.Message, service.AckF
 		msg.MetaSet("pulsar_ordering_key", orderingKey)
 	}
 	if !pulMsg.EventTime().IsZero() {
-		msg.MetaSet("pulsar_event_time_unix", strconv.FormatInt(pulMsg.EventTime().Unix(), 10))
+		msg.MetaSetMut("pulsar_event_time_unix", pulMsg.EventTime().Unix())
 	}
 	if producerName := pulMsg.ProducerName(); producerName != "" {
 		msg.MetaSet("pulsar_producer_name", producerName)
@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func redisRatelimitConfig() *service.ConfigSpec {
+	spec := service.NewConfigSpec().
+		Beta().
+		Version("4.0.0").
+		Summary(`A rate limit implementation using Redis. It works by using a token bucket that is stored within Redis and updated atomically, and can therefore be shared by any number of components and running instances of Benthos that target the same key.`).
+		Description(`
+The bucket holds up to ` + "`count`" + ` tokens and is refilled continuously at a rate of ` + "`count`" + ` tokens per ` + "`interval`" + `. Each access consumes a token, and when the bucket is empty the duration returned to the caller is the time remaining until the next token becomes available.
+
+The refill rate is calculated from the clocks of the instances accessing the rate limit, and therefore the clocks of those instances should be synchronised.`)
+
+	for _, f := range clientFields() {
+		spec = spec.Field(f)
+	}
+
+	return spec.
+		Field(service.NewIntField("count").
+			Description("The maximum number of requests to allow for a given period of time.").
+			Default(1000)).
+		Field(service.NewDurationField("interval").
+			Description("The time window to limit requests by.").
+			Default("1s")).
+		Field(service.NewStringField("key").
+			Description("The key to use for the rate limit bucket. Rate limits that target the same key share the same limit.").
+			Example("benthos_rate_limit"))
+}
+
+func init() {
+	err := service.RegisterRateLimit(
+		"redis", redisRatelimitConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.RateLimit, error) {
+			return newRedisRatelimitFromConfig(conf)
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newRedisRatelimitFromConfig(conf *service.ParsedConfig) (*redisRatelimit, error) {
+	client, err := getClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	count, err := conf.FieldInt("count")
+	if err != nil {
+		return nil, err
+	}
+	interval, err := conf.FieldDuration("interval")
+	if err != nil {
+		return nil, err
+	}
+	key, err := conf.FieldString("key")
+	if err != nil {
+		return nil, err
+	}
+	return newRedisRatelimit(client, key, count, interval)
+}
+
+//------------------------------------------------------------------------------
+
+// Refills the bucket according to the time elapsed since the last access and
+// attempts to consume a token. Returns zero when a token was consumed,
+// otherwise the number of milliseconds until a token becomes available.
+var redisTokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = capacity
+  ts = now
+end
+
+if now > ts then
+  tokens = math.min(capacity, tokens + ((now - ts) * capacity / interval))
+  ts = now
+end
+
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) * interval / capacity)
+end
+
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(ts))
+redis.call("PEXPIRE", KEYS[1], interval * 2)
+return wait
+`)
+
+type redisRatelimit struct {
+	client   redis.UniversalClient
+	key      string
+	size     int
+	interval time.Duration
+
+	// Overridden in tests.
+	now func() time.Time
+}
+
+func newRedisRatelimit(client redis.UniversalClient, key string, count int, interval time.Duration) (*redisRatelimit, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be larger than zero")
+	}
+	if interval < time.Millisecond {
+		return nil, errors.New("interval must be at least one millisecond")
+	}
+	if key == "" {
+		return nil, errors.New("key must not be empty")
+	}
+	return &redisRatelimit{
+		client:   client,
+		key:      key,
+		size:     count,
+		interval: interval,
+		now:      time.Now,
+	}, nil
+}
+
+func (r *redisRatelimit) Access(ctx context.Context) (time.Duration, error) {
+	nowMillis := r.now().UnixNano() / int64(time.Millisecond)
+	waitMillis, err := redisTokenBucketScript.Run(
+		clientWithContext(ctx, r.client), []string{r.key},
+		r.size, r.interval.Milliseconds(), nowMillis,
+	).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(waitMillis) * time.Millisecond, nil
+}
+
+// clientWithContext returns a shallow copy of the client that uses the provided
+// context, so that calls are abandoned when the context is cancelled.
+func clientWithContext(ctx context.Context, client redis.UniversalClient) redis.UniversalClient {
+	switch c := client.(type) {
+	case *redis.Client:
+		return c.WithContext(ctx)
+	case *redis.ClusterClient:
+		return c.WithContext(ctx)
+	}
+	return client
+}
+
+func (r *redisRatelimit) Close(ctx context.Context) error {
+	return r.client.Close()
+}
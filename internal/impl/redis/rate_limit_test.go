@@ -0,0 +1,208 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRedisRatelimit(t *testing.T, conf string) *redisRatelimit {
+	t.Helper()
+
+	pConf, err := redisRatelimitConfig().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	rl, err := newRedisRatelimitFromConfig(pConf)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = rl.Close(context.Background())
+	})
+	return rl
+}
+
+func TestRedisRatelimitConfErrors(t *testing.T) {
+	conf, err := redisRatelimitConfig().ParseYAML(`
+url: redis://localhost:6379
+key: foo
+count: -1
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newRedisRatelimitFromConfig(conf)
+	require.Error(t, err)
+
+	conf, err = redisRatelimitConfig().ParseYAML(`
+url: redis://localhost:6379
+key: ""
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newRedisRatelimitFromConfig(conf)
+	require.Error(t, err)
+}
+
+func TestRedisRatelimitBasic(t *testing.T) {
+	s := miniredis.RunT(t)
+
+	rl := testRedisRatelimit(t, fmt.Sprintf(`
+url: redis://%v
+key: foo
+count: 10
+interval: 1s
+`, s.Addr()))
+
+	ctx := context.Background()
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		period, err := rl.Access(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), period)
+	}
+
+	period, err := rl.Access(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Millisecond*100, period)
+
+	now = now.Add(time.Millisecond * 50)
+	period, err = rl.Access(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Millisecond*50, period)
+
+	now = now.Add(time.Millisecond * 50)
+	period, err = rl.Access(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), period)
+
+	period, err = rl.Access(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Millisecond*100, period)
+}
+
+func TestRedisRatelimitRefill(t *testing.T) {
+	s := miniredis.RunT(t)
+
+	rl := testRedisRatelimit(t, fmt.Sprintf(`
+url: redis://%v
+key: foo
+count: 10
+interval: 10s
+`, s.Addr()))
+
+	ctx := context.Background()
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		period, err := rl.Access(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), period)
+	}
+
+	// The bucket never exceeds its capacity regardless of how long has
+	// passed since the last access.
+	now = now.Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		period, err := rl.Access(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), period)
+	}
+
+	period, err := rl.Access(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, period)
+}
+
+func TestRedisRatelimitShared(t *testing.T) {
+	s := miniredis.RunT(t)
+
+	conf := fmt.Sprintf(`
+url: redis://%v
+key: foo
+count: 10
+interval: 1s
+`, s.Addr())
+
+	rlOne := testRedisRatelimit(t, conf)
+	rlTwo := testRedisRatelimit(t, conf)
+
+	ctx := context.Background()
+	now := time.Now()
+	rlOne.now = func() time.Time { return now }
+	rlTwo.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		period, err := rlOne.Access(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), period)
+
+		period, err = rlTwo.Access(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), period)
+	}
+
+	period, err := rlOne.Access(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, period, time.Duration(0))
+
+	period, err = rlTwo.Access(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, period, time.Duration(0))
+
+	// A different key has its own bucket.
+	rlThree := testRedisRatelimit(t, fmt.Sprintf(`
+url: redis://%v
+key: bar
+count: 10
+interval: 1s
+`, s.Addr()))
+	rlThree.now = func() time.Time { return now }
+
+	period, err = rlThree.Access(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), period)
+}
+
+func TestRedisRatelimitContextTimeout(t *testing.T) {
+	// A server that accepts connections but never responds, which would
+	// otherwise block each access for the full read timeout of the client.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() {
+				_ = conn.Close()
+			})
+		}
+	}()
+
+	rl := testRedisRatelimit(t, fmt.Sprintf(`
+url: redis://%v
+key: foo
+count: 10
+interval: 1s
+`, ln.Addr()))
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer done()
+
+	started := time.Now()
+	_, err = rl.Access(ctx)
+	require.Error(t, err)
+	assert.Less(t, time.Since(started), time.Second)
+}
@@ -0,0 +1,23 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+// batchWithAckFn is a batch of change events read by a CDC input along with a
+// function that confirms its delivery.
+type batchWithAckFn struct {
+	onAck func(ctx context.Context) error
+	batch service.MessageBatch
+}
+
+func sendBatchWithAck(ctx context.Context, msgChan chan batchWithAckFn, batch service.MessageBatch, onAck func(context.Context) error) error {
+	select {
+	case msgChan <- batchWithAckFn{batch: batch, onAck: onAck}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,47 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+type cacheProvider interface {
+	AccessCache(ctx context.Context, name string, fn func(c service.Cache)) error
+}
+
+// checkpointCacheAccess provides access to a cache resource for a checkpoint
+// store.
+func checkpointCacheAccess(mgr cacheProvider, name string) checkpoint.CacheAccessFunc {
+	return func(ctx context.Context, fn func(c checkpoint.Cache)) error {
+		return mgr.AccessCache(ctx, name, func(c service.Cache) {
+			fn(checkpointCache{c})
+		})
+	}
+}
+
+// checkpointCache adapts a service.Cache to the errors expected by a
+// checkpoint store.
+type checkpointCache struct {
+	c service.Cache
+}
+
+func (c checkpointCache) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := c.c.Get(ctx, key)
+	if errors.Is(err, service.ErrKeyNotFound) {
+		err = component.ErrKeyNotFound
+	}
+	return b, err
+}
+
+func (c checkpointCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	return c.c.Set(ctx, key, value, ttl)
+}
+
+func (c checkpointCache) Delete(ctx context.Context, key string) error {
+	return c.c.Delete(ctx, key)
+}
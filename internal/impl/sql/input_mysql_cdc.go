@@ -0,0 +1,586 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	mysqldriver "github.com/go-sql-driver/mysql"
+
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
+	"github.com/benthosdev/benthos/v4/internal/shutdown"
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+func mysqlCDCInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		// Stable(). TODO
+		Categories("Services").
+		Version("4.0.0").
+		Summary("Streams row level changes from a MySQL database by following its binary log.").
+		Description(`
+The server must be configured with `+"`binlog_format = ROW`"+` and `+"`binlog_row_metadata = FULL`"+`, which is required in order to obtain the column names of each change. The user must have the `+"`REPLICATION SLAVE`"+` and `+"`REPLICATION CLIENT`"+` privileges.
+
+Each transaction is emitted as a batch with a message for each row that was inserted, updated or deleted. Messages are structured with the following format:
+
+`+"```json"+`
+{
+  "operation": "update",
+  "database": "shop",
+  "table": "users",
+  "key": { "id": 1 },
+  "before": { "id": 1, "name": "foo" },
+  "after": { "id": 1, "name": "bar" }
+}
+`+"```"+`
+
+The `+"`key`"+` field contains the primary key columns of the row and is omitted for tables without a primary key.
+
+### Checkpointing
+
+The binlog position of a transaction, and the GTID set when GTIDs are enabled, is written to the configured cache resource once the batch of that transaction and all batches of prior transactions have been delivered. When the input starts it resumes from the checkpoint within the cache, or from the current position of the binlog when there is none.
+
+### Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"``` text"+`
+- mysql_cdc_operation
+- mysql_cdc_database
+- mysql_cdc_table
+- mysql_cdc_binlog_file
+- mysql_cdc_binlog_pos
+`+"```"+`
+`).
+		Field(service.NewStringField("dsn").
+			Description("A Data Source Name to identify the target database, in the format `[username[:password]@][protocol[(address)]]/[dbname]`.").
+			Example("foouser:foopassword@tcp(localhost:3306)/")).
+		Field(service.NewIntField("server_id").
+			Description("A server ID to identify this input to the server as a replica, which must be unique among all replicas of the server.").
+			Default(1001)).
+		Field(service.NewStringEnumField("flavor", "mysql", "mariadb").
+			Description("The flavor of the server.").
+			Default("mysql").
+			Advanced()).
+		Field(service.NewStringListField("tables").
+			Description("An optional list of tables to capture in the format `database.table`. When empty the changes of all tables are captured.").
+			Example([]string{"shop.users", "shop.orders"}).
+			Default([]string{})).
+		Field(service.NewStringField("cache").
+			Description("A [cache resource](/docs/components/caches/about) in which to store the binlog position of delivered changes.")).
+		Field(service.NewStringField("checkpoint_key").
+			Description("The key under which the binlog position is stored within the cache.").
+			Default("mysql_cdc_position")).
+		Field(service.NewIntField("checkpoint_limit").
+			Description("The maximum number of transactions that can be processed in parallel before applying back pressure. The position of a transaction is only checkpointed once all prior transactions have also been delivered.").
+			Default(1024).
+			Advanced()).
+		Example("Capture Tables",
+			`
+Here we capture the changes of two tables into a Kafka topic, keeping track of the binlog position within a Redis cache:`,
+			`
+input:
+  mysql_cdc:
+    dsn: foouser:foopassword@tcp(localhost:3306)/
+    server_id: 1001
+    tables: [ shop.users, shop.orders ]
+    cache: checkpoints
+
+output:
+  kafka:
+    addresses: [ localhost:9092 ]
+    topic: db_changes
+    key: ${! meta("mysql_cdc_table") }
+
+cache_resources:
+  - label: checkpoints
+    redis:
+      url: tcp://localhost:6379
+`,
+		)
+}
+
+func init() {
+	err := service.RegisterBatchInput(
+		"mysql_cdc", mysqlCDCInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			i, err := newMySQLCDCInputFromConfig(conf, mgr, mgr.Logger())
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksBatched(i), nil
+		})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// mysqlCDCPosition is a position within the binlog that changes can be resumed
+// from, which is serialised as JSON when stored within a cache.
+type mysqlCDCPosition struct {
+	File string `json:"file"`
+	Pos  uint32 `json:"pos"`
+	GTID string `json:"gtid,omitempty"`
+}
+
+type mysqlCDCInput struct {
+	dsn        string
+	syncerConf replication.BinlogSyncerConfig
+	flavor     string
+	tables     []string
+	store      *checkpoint.CacheStore
+
+	msgChan atomic.Value
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+}
+
+func newMySQLCDCInputFromConfig(conf *service.ParsedConfig, mgr cacheProvider, log *service.Logger) (*mysqlCDCInput, error) {
+	m := &mysqlCDCInput{
+		log:     log,
+		shutSig: shutdown.NewSignaller(),
+	}
+
+	var err error
+	if m.dsn, err = conf.FieldString("dsn"); err != nil {
+		return nil, err
+	}
+
+	serverID, err := conf.FieldInt("server_id")
+	if err != nil {
+		return nil, err
+	}
+	if serverID < 1 {
+		return nil, errors.New("server_id must be greater than zero")
+	}
+
+	if m.flavor, err = conf.FieldString("flavor"); err != nil {
+		return nil, err
+	}
+	if m.syncerConf, err = binlogSyncerConfFromDSN(m.dsn, uint32(serverID), m.flavor); err != nil {
+		return nil, err
+	}
+
+	if m.tables, err = conf.FieldStringList("tables"); err != nil {
+		return nil, err
+	}
+
+	cacheName, err := conf.FieldString("cache")
+	if err != nil {
+		return nil, err
+	}
+	checkpointKey, err := conf.FieldString("checkpoint_key")
+	if err != nil {
+		return nil, err
+	}
+	checkpointLimit, err := conf.FieldInt("checkpoint_limit")
+	if err != nil {
+		return nil, err
+	}
+	if checkpointLimit < 1 {
+		return nil, errors.New("checkpoint_limit must be greater than zero")
+	}
+	m.store = checkpoint.NewCacheStore(checkpointCacheAccess(mgr, cacheName), checkpointKey, int64(checkpointLimit))
+	return m, nil
+}
+
+func binlogSyncerConfFromDSN(dsn string, serverID uint32, flavor string) (replication.BinlogSyncerConfig, error) {
+	dsnConf, err := mysqldriver.ParseDSN(dsn)
+	if err != nil {
+		return replication.BinlogSyncerConfig{}, fmt.Errorf("failed to parse dsn: %w", err)
+	}
+
+	host, portStr, err := net.SplitHostPort(dsnConf.Addr)
+	if err != nil {
+		return replication.BinlogSyncerConfig{}, fmt.Errorf("failed to parse dsn address: %w", err)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return replication.BinlogSyncerConfig{}, fmt.Errorf("failed to parse dsn port: %w", err)
+	}
+
+	return replication.BinlogSyncerConfig{
+		ServerID:  serverID,
+		Flavor:    flavor,
+		Host:      host,
+		Port:      uint16(port),
+		User:      dsnConf.User,
+		Password:  dsnConf.Passwd,
+		ParseTime: true,
+	}, nil
+}
+
+func (m *mysqlCDCInput) getMsgChan() chan batchWithAckFn {
+	c, _ := m.msgChan.Load().(chan batchWithAckFn)
+	return c
+}
+
+func (m *mysqlCDCInput) storeMsgChan(c chan batchWithAckFn) {
+	m.msgChan.Store(c)
+}
+
+func (m *mysqlCDCInput) loadCheckpoint(ctx context.Context) (*mysqlCDCPosition, error) {
+	posStr, exists, err := m.store.Load(ctx)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var pos mysqlCDCPosition
+	if err := json.Unmarshal([]byte(posStr), &pos); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return &pos, nil
+}
+
+// currentPosition returns the current position of the binlog of the server,
+// which is used when there is no checkpoint to resume from.
+func (m *mysqlCDCInput) currentPosition(ctx context.Context) (*mysqlCDCPosition, error) {
+	db, err := sql.Open("mysql", m.dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "SHOW MASTER STATUS")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("binary logging is not enabled on the server")
+	}
+
+	values := make([]sql.NullString, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+
+	var pos mysqlCDCPosition
+	for i, col := range columns {
+		switch col {
+		case "File":
+			pos.File = values[i].String
+		case "Position":
+			p, err := strconv.ParseUint(values[i].String, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse binlog position: %w", err)
+			}
+			pos.Pos = uint32(p)
+		case "Executed_Gtid_Set":
+			pos.GTID = strings.ReplaceAll(values[i].String, "\n", "")
+		}
+	}
+	return &pos, nil
+}
+
+func (m *mysqlCDCInput) Connect(ctx context.Context) error {
+	if m.getMsgChan() != nil {
+		return nil
+	}
+
+	if m.shutSig.ShouldCloseAtLeisure() {
+		m.shutSig.ShutdownComplete()
+		return service.ErrEndOfInput
+	}
+
+	pos, err := m.loadCheckpoint(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if pos == nil {
+		if pos, err = m.currentPosition(ctx); err != nil {
+			return fmt.Errorf("failed to obtain binlog position: %w", err)
+		}
+	}
+
+	syncer := replication.NewBinlogSyncer(m.syncerConf)
+
+	var streamer *replication.BinlogStreamer
+	if pos.GTID != "" {
+		var gset mysql.GTIDSet
+		if gset, err = mysql.ParseGTIDSet(m.flavor, pos.GTID); err == nil {
+			streamer, err = syncer.StartSyncGTID(gset)
+		}
+	} else {
+		streamer, err = syncer.StartSync(mysql.Position{Name: pos.File, Pos: pos.Pos})
+	}
+	if err != nil {
+		syncer.Close()
+		return fmt.Errorf("failed to start binlog sync: %w", err)
+	}
+
+	msgChan := make(chan batchWithAckFn)
+	go func() {
+		defer func() {
+			syncer.Close()
+			m.storeMsgChan(nil)
+			close(msgChan)
+			if m.shutSig.ShouldCloseAtLeisure() {
+				m.shutSig.ShutdownComplete()
+			}
+		}()
+
+		closeCtx, done := m.shutSig.CloseAtLeisureCtx(context.Background())
+		defer done()
+
+		if err := m.streamChanges(closeCtx, streamer, pos, msgChan); err != nil && closeCtx.Err() == nil {
+			m.log.Errorf("Binlog stream error: %v", err)
+		}
+	}()
+
+	m.storeMsgChan(msgChan)
+	m.log.Infof("Receiving changes from MySQL binlog position: %v:%v", pos.File, pos.Pos)
+	return nil
+}
+
+func (m *mysqlCDCInput) streamChanges(ctx context.Context, streamer *replication.BinlogStreamer, pos *mysqlCDCPosition, msgChan chan batchWithAckFn) error {
+	stream := newMySQLCDCStream(pos.File, m.tables)
+
+	for {
+		ev, err := streamer.GetEvent(ctx)
+		if err != nil {
+			return err
+		}
+
+		batch, endPos, err := stream.handle(ev)
+		if err != nil {
+			return err
+		}
+		if batch == nil {
+			continue
+		}
+
+		endPosBytes, err := json.Marshal(endPos)
+		if err != nil {
+			return err
+		}
+
+		resolveFn, err := m.store.Track(ctx, string(endPosBytes), 1)
+		if err != nil {
+			return err
+		}
+		if err := sendBatchWithAck(ctx, msgChan, batch, func(ctx context.Context) error {
+			if err := resolveFn(ctx); err != nil {
+				return fmt.Errorf("failed to store checkpoint: %w", err)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *mysqlCDCInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	msgChan := m.getMsgChan()
+	if msgChan == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	var bAck batchWithAckFn
+	var open bool
+	select {
+	case bAck, open = <-msgChan:
+		if !open {
+			return nil, nil, service.ErrNotConnected
+		}
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	return bAck.batch, func(ctx context.Context, res error) error {
+		// Res will always be nil because we initialize with service.AutoRetryNacksBatched
+		return bAck.onAck(ctx)
+	}, nil
+}
+
+func (m *mysqlCDCInput) Close(ctx context.Context) error {
+	go func() {
+		m.shutSig.CloseAtLeisure()
+		if m.getMsgChan() == nil {
+			// If the msg chan is already nil then we might've not been
+			// connected, so force the shutdown complete signal.
+			m.shutSig.ShutdownComplete()
+		}
+	}()
+	select {
+	case <-m.shutSig.HasClosedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// mysqlCDCStream decodes binlog events into batches of change messages, where
+// each batch contains the changes of a single transaction.
+type mysqlCDCStream struct {
+	file    string
+	tables  map[string]struct{}
+	pending service.MessageBatch
+}
+
+func newMySQLCDCStream(file string, tables []string) *mysqlCDCStream {
+	s := &mysqlCDCStream{file: file}
+	if len(tables) > 0 {
+		s.tables = make(map[string]struct{}, len(tables))
+		for _, t := range tables {
+			s.tables[t] = struct{}{}
+		}
+	}
+	return s
+}
+
+// handle processes a binlog event and returns a batch of changes along with
+// the position following the transaction once it is committed.
+func (s *mysqlCDCStream) handle(ev *replication.BinlogEvent) (service.MessageBatch, *mysqlCDCPosition, error) {
+	switch e := ev.Event.(type) {
+	case *replication.RotateEvent:
+		s.file = string(e.NextLogName)
+	case *replication.RowsEvent:
+		return nil, nil, s.handleRows(ev.Header, e)
+	case *replication.XIDEvent:
+		return s.commit(ev.Header, e.GSet)
+	case *replication.QueryEvent:
+		// Changes to non-transactional tables are committed with a query event
+		// rather than an XID event.
+		if string(e.Query) == "COMMIT" {
+			return s.commit(ev.Header, e.GSet)
+		}
+	}
+	return nil, nil, nil
+}
+
+func (s *mysqlCDCStream) commit(header *replication.EventHeader, gset mysql.GTIDSet) (service.MessageBatch, *mysqlCDCPosition, error) {
+	batch := s.pending
+	s.pending = nil
+	if len(batch) == 0 {
+		return nil, nil, nil
+	}
+
+	pos := &mysqlCDCPosition{File: s.file, Pos: header.LogPos}
+	if gset != nil {
+		pos.GTID = gset.String()
+	}
+	return batch, pos, nil
+}
+
+func (s *mysqlCDCStream) handleRows(header *replication.EventHeader, e *replication.RowsEvent) error {
+	database, table := string(e.Table.Schema), string(e.Table.Table)
+	if s.tables != nil {
+		if _, exists := s.tables[database+"."+table]; !exists {
+			return nil
+		}
+	}
+
+	if len(e.Table.ColumnName) == 0 {
+		return fmt.Errorf("column names of table %v.%v are missing from the binlog, the server must be configured with binlog_row_metadata=FULL", database, table)
+	}
+
+	var operation string
+	switch header.EventType {
+	case replication.WRITE_ROWS_EVENTv0, replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
+		operation = "insert"
+	case replication.UPDATE_ROWS_EVENTv0, replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
+		operation = "update"
+	case replication.DELETE_ROWS_EVENTv0, replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
+		operation = "delete"
+	default:
+		return nil
+	}
+
+	rowToMap := func(row []interface{}) map[string]interface{} {
+		values := make(map[string]interface{}, len(row))
+		for i, v := range row {
+			if i >= len(e.Table.ColumnName) {
+				break
+			}
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			values[string(e.Table.ColumnName[i])] = v
+		}
+		return values
+	}
+
+	keyFromMap := func(values map[string]interface{}) map[string]interface{} {
+		key := make(map[string]interface{}, len(e.Table.PrimaryKey))
+		for _, i := range e.Table.PrimaryKey {
+			if int(i) < len(e.Table.ColumnName) {
+				name := string(e.Table.ColumnName[i])
+				key[name] = values[name]
+			}
+		}
+		return key
+	}
+
+	newMsg := func(before, after map[string]interface{}) *service.Message {
+		structured := map[string]interface{}{
+			"operation": operation,
+			"database":  database,
+			"table":     table,
+		}
+		if before != nil {
+			structured["before"] = before
+		}
+		if after != nil {
+			structured["after"] = after
+		}
+		if len(e.Table.PrimaryKey) > 0 {
+			if after != nil {
+				structured["key"] = keyFromMap(after)
+			} else {
+				structured["key"] = keyFromMap(before)
+			}
+		}
+
+		msg := service.NewMessage(nil)
+		msg.SetStructured(structured)
+		msg.MetaSet("mysql_cdc_operation", operation)
+		msg.MetaSet("mysql_cdc_database", database)
+		msg.MetaSet("mysql_cdc_table", table)
+		msg.MetaSet("mysql_cdc_binlog_file", s.file)
+		msg.MetaSetMut("mysql_cdc_binlog_pos", int64(header.LogPos))
+		return msg
+	}
+
+	switch operation {
+	case "insert":
+		for _, row := range e.Rows {
+			s.pending = append(s.pending, newMsg(nil, rowToMap(row)))
+		}
+	case "update":
+		// Rows of update events alternate between before and after images.
+		for i := 0; i+1 < len(e.Rows); i += 2 {
+			s.pending = append(s.pending, newMsg(rowToMap(e.Rows[i]), rowToMap(e.Rows[i+1])))
+		}
+	case "delete":
+		for _, row := range e.Rows {
+			s.pending = append(s.pending, newMsg(rowToMap(row), nil))
+		}
+	}
+	return nil
+}
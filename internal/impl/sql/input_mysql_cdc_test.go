@@ -0,0 +1,274 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/public/service"
+)
+
+type fakeCache struct {
+	items map[string][]byte
+}
+
+func (f *fakeCache) Get(ctx context.Context, key string) ([]byte, error) {
+	v, exists := f.items[key]
+	if !exists {
+		return nil, service.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func (f *fakeCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	f.items[key] = value
+	return nil
+}
+
+func (f *fakeCache) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	if _, exists := f.items[key]; exists {
+		return service.ErrKeyAlreadyExists
+	}
+	f.items[key] = value
+	return nil
+}
+
+func (f *fakeCache) Delete(ctx context.Context, key string) error {
+	delete(f.items, key)
+	return nil
+}
+
+func (f *fakeCache) Close(ctx context.Context) error {
+	return nil
+}
+
+type fakeCacheProvider map[string]*fakeCache
+
+func (f fakeCacheProvider) AccessCache(ctx context.Context, name string, fn func(c service.Cache)) error {
+	c, exists := f[name]
+	if !exists {
+		return service.ErrKeyNotFound
+	}
+	fn(c)
+	return nil
+}
+
+func TestMySQLCDCInputConfig(t *testing.T) {
+	spec := mysqlCDCInputConfig()
+	env := service.NewEnvironment()
+
+	parsed, err := spec.ParseYAML(`
+dsn: foouser:foopass@tcp(example.com:3307)/
+cache: foocache
+`, env)
+	require.NoError(t, err)
+
+	i, err := newMySQLCDCInputFromConfig(parsed, fakeCacheProvider{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", i.syncerConf.Host)
+	assert.Equal(t, uint16(3307), i.syncerConf.Port)
+	assert.Equal(t, "foouser", i.syncerConf.User)
+	assert.Equal(t, "foopass", i.syncerConf.Password)
+	assert.Equal(t, uint32(1001), i.syncerConf.ServerID)
+	assert.Equal(t, "mysql", i.syncerConf.Flavor)
+	require.NoError(t, i.Close(context.Background()))
+
+	parsed, err = spec.ParseYAML(`
+dsn: foouser:foopass@tcp(example.com:3307)/
+cache: foocache
+server_id: 0
+`, env)
+	require.NoError(t, err)
+
+	_, err = newMySQLCDCInputFromConfig(parsed, fakeCacheProvider{}, nil)
+	require.Error(t, err)
+}
+
+func TestMySQLCDCCheckpoint(t *testing.T) {
+	spec := mysqlCDCInputConfig()
+	env := service.NewEnvironment()
+
+	parsed, err := spec.ParseYAML(`
+dsn: foouser:foopass@tcp(localhost:3306)/
+cache: foocache
+checkpoint_key: meow
+`, env)
+	require.NoError(t, err)
+
+	cache := &fakeCache{items: map[string][]byte{}}
+	i, err := newMySQLCDCInputFromConfig(parsed, fakeCacheProvider{"foocache": cache}, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	pos, err := i.loadCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, pos)
+
+	cache.items["meow"] = []byte(`{"file":"binlog.000002","pos":123}`)
+
+	pos, err = i.loadCheckpoint(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &mysqlCDCPosition{File: "binlog.000002", Pos: 123}, pos)
+
+	cache.items["meow"] = []byte(`not json`)
+	_, err = i.loadCheckpoint(ctx)
+	require.Error(t, err)
+}
+
+func TestMySQLCDCStream(t *testing.T) {
+	s := newMySQLCDCStream("binlog.000001", []string{"shop.users"})
+
+	usersTable := &replication.TableMapEvent{
+		Schema:     []byte("shop"),
+		Table:      []byte("users"),
+		ColumnName: [][]byte{[]byte("id"), []byte("name")},
+		PrimaryKey: []uint64{0},
+	}
+	ordersTable := &replication.TableMapEvent{
+		Schema:     []byte("shop"),
+		Table:      []byte("orders"),
+		ColumnName: [][]byte{[]byte("id")},
+	}
+
+	rowsEvent := func(eventType replication.EventType, pos uint32, table *replication.TableMapEvent, rows ...[]interface{}) *replication.BinlogEvent {
+		return &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: eventType, LogPos: pos},
+			Event:  &replication.RowsEvent{Table: table, Rows: rows},
+		}
+	}
+
+	handle := func(ev *replication.BinlogEvent) (service.MessageBatch, *mysqlCDCPosition) {
+		t.Helper()
+		batch, pos, err := s.handle(ev)
+		require.NoError(t, err)
+		return batch, pos
+	}
+
+	batch, _ := handle(&replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: replication.ROTATE_EVENT},
+		Event:  &replication.RotateEvent{Position: 4, NextLogName: []byte("binlog.000002")},
+	})
+	assert.Nil(t, batch)
+
+	for _, ev := range []*replication.BinlogEvent{
+		rowsEvent(replication.WRITE_ROWS_EVENTv2, 100, usersTable, []interface{}{int32(1), []byte("foo")}),
+		rowsEvent(replication.WRITE_ROWS_EVENTv2, 150, ordersTable, []interface{}{int32(5)}),
+		rowsEvent(replication.UPDATE_ROWS_EVENTv2, 200, usersTable,
+			[]interface{}{int32(1), []byte("foo")},
+			[]interface{}{int32(1), []byte("bar")},
+		),
+		rowsEvent(replication.DELETE_ROWS_EVENTv2, 300, usersTable, []interface{}{int32(1), nil}),
+	} {
+		batch, _ = handle(ev)
+		assert.Nil(t, batch)
+	}
+
+	gset, err := mysql.ParseGTIDSet("mysql", "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")
+	require.NoError(t, err)
+
+	batch, pos := handle(&replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: replication.XID_EVENT, LogPos: 400},
+		Event:  &replication.XIDEvent{XID: 10, GSet: gset},
+	})
+	assert.Equal(t, &mysqlCDCPosition{
+		File: "binlog.000002",
+		Pos:  400,
+		GTID: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5",
+	}, pos)
+	require.Len(t, batch, 3)
+
+	var docs []string
+	for _, msg := range batch {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		docs = append(docs, string(b))
+
+		v, _ := msg.MetaGet("mysql_cdc_binlog_file")
+		assert.Equal(t, "binlog.000002", v)
+	}
+	assert.Equal(t, []string{
+		`{"after":{"id":1,"name":"foo"},"database":"shop","key":{"id":1},"operation":"insert","table":"users"}`,
+		`{"after":{"id":1,"name":"bar"},"before":{"id":1,"name":"foo"},"database":"shop","key":{"id":1},"operation":"update","table":"users"}`,
+		`{"before":{"id":1,"name":null},"database":"shop","key":{"id":1},"operation":"delete","table":"users"}`,
+	}, docs)
+
+	binlogPos, _ := batch[1].MetaGetMut("mysql_cdc_binlog_pos")
+	assert.Equal(t, int64(200), binlogPos)
+
+	// Transactions without captured changes do not produce a batch.
+	batch, _ = handle(rowsEvent(replication.WRITE_ROWS_EVENTv2, 500, ordersTable, []interface{}{int32(6)}))
+	assert.Nil(t, batch)
+	batch, _ = handle(&replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: replication.QUERY_EVENT, LogPos: 600},
+		Event:  &replication.QueryEvent{Query: []byte("COMMIT")},
+	})
+	assert.Nil(t, batch)
+
+	// Column names are required.
+	_, _, err = s.handle(rowsEvent(replication.WRITE_ROWS_EVENTv2, 700, &replication.TableMapEvent{
+		Schema: []byte("shop"),
+		Table:  []byte("users"),
+	}, []interface{}{int32(1)}))
+	require.Error(t, err)
+}
+
+func TestMySQLCDCStreamNoPrimaryKey(t *testing.T) {
+	s := newMySQLCDCStream("binlog.000001", nil)
+
+	logsTable := &replication.TableMapEvent{
+		Schema:     []byte("shop"),
+		Table:      []byte("logs"),
+		ColumnName: [][]byte{[]byte("level"), []byte("message")},
+	}
+
+	for _, ev := range []*replication.BinlogEvent{
+		{
+			Header: &replication.EventHeader{EventType: replication.WRITE_ROWS_EVENTv2, LogPos: 100},
+			Event: &replication.RowsEvent{Table: logsTable, Rows: [][]interface{}{
+				{[]byte("info"), []byte("foo")},
+			}},
+		},
+		{
+			Header: &replication.EventHeader{EventType: replication.UPDATE_ROWS_EVENTv2, LogPos: 200},
+			Event: &replication.RowsEvent{Table: logsTable, Rows: [][]interface{}{
+				{[]byte("info"), []byte("foo")},
+				{[]byte("warn"), []byte("foo")},
+			}},
+		},
+		{
+			Header: &replication.EventHeader{EventType: replication.DELETE_ROWS_EVENTv2, LogPos: 300},
+			Event: &replication.RowsEvent{Table: logsTable, Rows: [][]interface{}{
+				{[]byte("warn"), []byte("foo")},
+			}},
+		},
+	} {
+		batch, _, err := s.handle(ev)
+		require.NoError(t, err)
+		assert.Nil(t, batch)
+	}
+
+	batch, _, err := s.handle(&replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: replication.XID_EVENT, LogPos: 400},
+		Event:  &replication.XIDEvent{XID: 10},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch, 3)
+
+	var docs []string
+	for _, msg := range batch {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		docs = append(docs, string(b))
+	}
+	assert.Equal(t, []string{
+		`{"after":{"level":"info","message":"foo"},"database":"shop","operation":"insert","table":"logs"}`,
+		`{"after":{"level":"warn","message":"foo"},"before":{"level":"info","message":"foo"},"database":"shop","operation":"update","table":"logs"}`,
+		`{"before":{"level":"warn","message":"foo"},"database":"shop","operation":"delete","table":"logs"}`,
+	}, docs)
+}
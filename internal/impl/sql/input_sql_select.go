@@ -3,11 +3,17 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
 	"github.com/benthosdev/benthos/v4/internal/shutdown"
 	"github.com/benthosdev/benthos/v4/public/bloblang"
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -18,7 +24,11 @@ func sqlSelectInputConfig() *service.ConfigSpec {
 		// Stable(). TODO
 		Categories("Services").
 		Summary("Executes a select query and creates a message for each row received.").
-		Description(`Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a [sequence](/docs/components/inputs/sequence) to execute).`).
+		Description(`Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a [sequence](/docs/components/inputs/sequence) to execute).
+
+### Checkpointing
+
+When a ` + "`checkpoint_cache`" + ` is configured the rows of the query are ordered by the ` + "`cursor_column`" + `, and the value of that column for the latest row to be delivered is stored within the cache. When the input is started again only rows with a cursor value greater than the stored value are selected, which allows a table to be consumed incrementally across restarts of the pipeline.`).
 		Field(driverField).
 		Field(dsnField).
 		Field(service.NewStringField("table").
@@ -42,8 +52,24 @@ func sqlSelectInputConfig() *service.ConfigSpec {
 			Optional().
 			Advanced()).
 		Field(service.NewStringField("suffix").
-			Description("An optional suffix to append to the select query.").
+			Description("An optional suffix to append to the select query. When a `checkpoint_cache` is configured the suffix must not contain `ORDER BY` or `LIMIT` clauses, as rows are ordered by the `cursor_column`.").
 			Optional().
+			Advanced()).
+		Field(service.NewStringField("cursor_column").
+			Description("An optional column with values that increase monotonically for new rows, such as an auto incrementing ID or a creation timestamp. Required when a `checkpoint_cache` is configured.").
+			Example("id").
+			Example("created_at").
+			Optional()).
+		Field(service.NewStringField("checkpoint_cache").
+			Description("An optional [cache resource](/docs/components/caches/about) in which to store the value of the `cursor_column` for the latest row to be delivered.").
+			Optional()).
+		Field(service.NewStringField("checkpoint_key").
+			Description("The key under which the cursor value is stored within the `checkpoint_cache`.").
+			Default("sql_select_cursor").
+			Advanced()).
+		Field(service.NewIntField("checkpoint_limit").
+			Description("The maximum number of rows that can be processed in parallel before applying back pressure. The cursor value of a row is only checkpointed once all prior rows have also been delivered.").
+			Default(1024).
 			Advanced())
 
 	for _, f := range connFields() {
@@ -76,7 +102,7 @@ func init() {
 	err := service.RegisterInput(
 		"sql_select", sqlSelectInputConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
-			i, err := newSQLSelectInputFromConfig(conf, mgr.Logger())
+			i, err := newSQLSelectInputFromConfig(conf, mgr, mgr.Logger())
 			if err != nil {
 				return nil, err
 			}
@@ -101,13 +127,16 @@ type sqlSelectInput struct {
 	where       string
 	argsMapping *bloblang.Executor
 
+	cursorColumn string
+	store        *checkpoint.CacheStore
+
 	connSettings connSettings
 
 	logger  *service.Logger
 	shutSig *shutdown.Signaller
 }
 
-func newSQLSelectInputFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*sqlSelectInput, error) {
+func newSQLSelectInputFromConfig(conf *service.ParsedConfig, mgr cacheProvider, logger *service.Logger) (*sqlSelectInput, error) {
 	s := &sqlSelectInput{
 		logger:  logger,
 		shutSig: shutdown.NewSignaller(),
@@ -158,14 +187,46 @@ func newSQLSelectInputFromConfig(conf *service.ParsedConfig, logger *service.Log
 		s.builder = s.builder.Prefix(prefixStr)
 	}
 
+	var suffixStr string
 	if conf.Contains("suffix") {
-		suffixStr, err := conf.FieldString("suffix")
-		if err != nil {
+		if suffixStr, err = conf.FieldString("suffix"); err != nil {
 			return nil, err
 		}
 		s.builder = s.builder.Suffix(suffixStr)
 	}
 
+	if conf.Contains("cursor_column") {
+		if s.cursorColumn, err = conf.FieldString("cursor_column"); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains("checkpoint_cache") {
+		if s.cursorColumn == "" {
+			return nil, errors.New("a cursor_column must be specified when a checkpoint_cache is configured")
+		}
+		if orderedSuffixRegexp.MatchString(suffixStr) {
+			return nil, errors.New("the suffix must not contain ORDER BY or LIMIT clauses when a checkpoint_cache is configured")
+		}
+		s.builder = s.builder.OrderBy(s.cursorColumn)
+		cacheName, err := conf.FieldString("checkpoint_cache")
+		if err != nil {
+			return nil, err
+		}
+		checkpointKey, err := conf.FieldString("checkpoint_key")
+		if err != nil {
+			return nil, err
+		}
+		checkpointLimit, err := conf.FieldInt("checkpoint_limit")
+		if err != nil {
+			return nil, err
+		}
+		if checkpointLimit < 1 {
+			return nil, fmt.Errorf("checkpoint_limit must be greater than zero, got %v", checkpointLimit)
+		}
+		s.store = checkpoint.NewCacheStore(checkpointCacheAccess(mgr, cacheName), checkpointKey, int64(checkpointLimit))
+	}
+
 	if s.connSettings, err = connSettingsFromParsed(conf); err != nil {
 		return nil, err
 	}
@@ -210,6 +271,22 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 	if s.where != "" {
 		queryBuilder = queryBuilder.Where(s.where, args...)
 	}
+	if s.store != nil {
+		var cursorStr string
+		var exists bool
+		if cursorStr, exists, err = s.store.Load(ctx); err != nil {
+			err = fmt.Errorf("failed to load checkpoint: %w", err)
+			return
+		}
+		if exists {
+			var cursor interface{}
+			if cursor, err = decodeCursor(cursorStr); err != nil {
+				err = fmt.Errorf("failed to decode checkpoint: %w", err)
+				return
+			}
+			queryBuilder = queryBuilder.Where(squirrel.Gt{s.cursorColumn: cursor})
+		}
+	}
 	var rows *sql.Rows
 	if rows, err = queryBuilder.RunWith(db).Query(); err != nil {
 		return
@@ -267,13 +344,103 @@ func (s *sqlSelectInput) Read(ctx context.Context) (*service.Message, service.Ac
 
 	msg := service.NewMessage(nil)
 	msg.SetStructured(obj)
+
+	if s.store == nil {
+		return msg, func(ctx context.Context, err error) error {
+			// Nacks are handled by AutoRetryNacks because we don't have an
+			// explicit ack mechanism right now.
+			return nil
+		}, nil
+	}
+
+	cursor, exists := obj[s.cursorColumn]
+	if !exists {
+		return nil, nil, fmt.Errorf("cursor column %v was not found within selected row", s.cursorColumn)
+	}
+	cursorStr, err := encodeCursor(cursor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	resolveFn, err := s.store.Track(ctx, cursorStr, 1)
+	if err != nil {
+		return nil, nil, err
+	}
 	return msg, func(ctx context.Context, err error) error {
-		// Nacks are handled by AutoRetryNacks because we don't have an explicit
-		// ack mechanism right now.
-		return nil
+		// Nacks are handled by AutoRetryNacks, and therefore only successful
+		// deliveries reach this point.
+		return resolveFn(ctx)
 	}, nil
 }
 
+// orderedSuffixRegexp matches suffixes that would conflict with the ordering of
+// rows by the cursor column.
+var orderedSuffixRegexp = regexp.MustCompile(`(?i)\b(order\s+by|limit)\b`)
+
+// sqlCursor is the representation of a cursor column value stored within the
+// checkpoint cache. The type of the value is recorded so that it can be bound
+// as a query argument of the same type as the column when the input restarts,
+// as drivers such as MySQL fail to compare temporal columns against arbitrary
+// string representations.
+type sqlCursor struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+const (
+	sqlCursorString    = "string"
+	sqlCursorInt       = "int"
+	sqlCursorFloat     = "float"
+	sqlCursorTimestamp = "timestamp"
+)
+
+// encodeCursor converts a cursor column value into a form that can be stored
+// within a cache and provided as an argument to a later query.
+func encodeCursor(v interface{}) (string, error) {
+	c := sqlCursor{Type: sqlCursorString}
+	switch t := v.(type) {
+	case string:
+		c.Value = t
+	case []byte:
+		c.Value = string(t)
+	case int64:
+		c.Type, c.Value = sqlCursorInt, strconv.FormatInt(t, 10)
+	case float64:
+		c.Type, c.Value = sqlCursorFloat, strconv.FormatFloat(t, 'g', -1, 64)
+	case time.Time:
+		c.Type, c.Value = sqlCursorTimestamp, t.Format(time.RFC3339Nano)
+	default:
+		c.Value = fmt.Sprintf("%v", v)
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// decodeCursor converts a stored cursor back into a value of the type it was
+// read as.
+func decodeCursor(s string) (interface{}, error) {
+	var c sqlCursor
+	if err := json.Unmarshal([]byte(s), &c); err != nil {
+		return nil, err
+	}
+	if c.Type == "" {
+		return nil, errors.New("cursor type is missing")
+	}
+	switch c.Type {
+	case sqlCursorString:
+		return c.Value, nil
+	case sqlCursorInt:
+		return strconv.ParseInt(c.Value, 10, 64)
+	case sqlCursorFloat:
+		return strconv.ParseFloat(c.Value, 64)
+	case sqlCursorTimestamp:
+		return time.Parse(time.RFC3339Nano, c.Value)
+	}
+	return nil, fmt.Errorf("unrecognised cursor type: %v", c.Type)
+}
+
 func (s *sqlSelectInput) Close(ctx context.Context) error {
 	s.shutSig.CloseNow()
 	s.dbMut.Lock()
@@ -3,7 +3,10 @@ package sql
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/benthosdev/benthos/v4/public/service"
@@ -25,7 +28,128 @@ args_mapping: 'root = [ this.id ]'
 	selectConfig, err := spec.ParseYAML(conf, env)
 	require.NoError(t, err)
 
-	selectInput, err := newSQLSelectInputFromConfig(selectConfig, nil)
+	selectInput, err := newSQLSelectInputFromConfig(selectConfig, fakeCacheProvider{}, nil)
 	require.NoError(t, err)
 	require.NoError(t, selectInput.Close(context.Background()))
 }
+
+func TestSQLSelectInputCheckpointConfig(t *testing.T) {
+	spec := sqlSelectInputConfig()
+	env := service.NewEnvironment()
+
+	selectConfig, err := spec.ParseYAML(`
+driver: meow
+dsn: woof
+table: quack
+columns: [ foo, bar, baz ]
+checkpoint_cache: foocache
+`, env)
+	require.NoError(t, err)
+
+	_, err = newSQLSelectInputFromConfig(selectConfig, fakeCacheProvider{}, nil)
+	require.Error(t, err)
+
+	selectConfig, err = spec.ParseYAML(`
+driver: meow
+dsn: woof
+table: quack
+columns: [ foo, bar, baz ]
+cursor_column: foo
+checkpoint_cache: foocache
+`, env)
+	require.NoError(t, err)
+
+	selectInput, err := newSQLSelectInputFromConfig(selectConfig, fakeCacheProvider{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, selectInput.store)
+
+	query, _, err := selectInput.builder.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT foo, bar, baz FROM quack ORDER BY foo", query)
+	require.NoError(t, selectInput.Close(context.Background()))
+
+	selectConfig, err = spec.ParseYAML(`
+driver: meow
+dsn: woof
+table: quack
+columns: [ foo, bar, baz ]
+cursor_column: foo
+`, env)
+	require.NoError(t, err)
+
+	selectInput, err = newSQLSelectInputFromConfig(selectConfig, fakeCacheProvider{}, nil)
+	require.NoError(t, err)
+	require.Nil(t, selectInput.store)
+
+	query, _, err = selectInput.builder.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT foo, bar, baz FROM quack", query)
+	require.NoError(t, selectInput.Close(context.Background()))
+
+	for _, suffix := range []string{"ORDER BY bar", "limit 10", "order  by bar desc"} {
+		selectConfig, err = spec.ParseYAML(`
+driver: meow
+dsn: woof
+table: quack
+columns: [ foo, bar, baz ]
+suffix: `+suffix+`
+cursor_column: foo
+checkpoint_cache: foocache
+`, env)
+		require.NoError(t, err)
+
+		_, err = newSQLSelectInputFromConfig(selectConfig, fakeCacheProvider{}, nil)
+		require.Error(t, err, suffix)
+	}
+}
+
+func TestSQLSelectCursorEncoding(t *testing.T) {
+	ts := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	for _, test := range []struct {
+		input    interface{}
+		encoded  string
+		expected interface{}
+	}{
+		{input: "foo", encoded: `{"type":"string","value":"foo"}`, expected: "foo"},
+		{input: []byte("bar"), encoded: `{"type":"string","value":"bar"}`, expected: "bar"},
+		{input: int64(10), encoded: `{"type":"int","value":"10"}`, expected: int64(10)},
+		{input: 1.5, encoded: `{"type":"float","value":"1.5"}`, expected: 1.5},
+		{input: ts, encoded: `{"type":"timestamp","value":"2022-01-02T03:04:05.000000006Z"}`, expected: ts},
+	} {
+		encoded, err := encodeCursor(test.input)
+		require.NoError(t, err)
+		assert.Equal(t, test.encoded, encoded)
+
+		decoded, err := decodeCursor(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, decoded)
+	}
+
+	_, err := decodeCursor("2022-01-02T03:04:05Z")
+	require.Error(t, err)
+
+	_, err = decodeCursor(`{"value":"10"}`)
+	require.Error(t, err)
+
+	_, err = decodeCursor(`{"type":"nope","value":"10"}`)
+	require.Error(t, err)
+}
+
+func TestSQLSelectTimestampCursorArgs(t *testing.T) {
+	ts := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	encoded, err := encodeCursor(ts)
+	require.NoError(t, err)
+
+	cursor, err := decodeCursor(encoded)
+	require.NoError(t, err)
+
+	query, args, err := squirrel.Select("id", "created_at").
+		From("foo").
+		Where(squirrel.Gt{"created_at": cursor}).
+		OrderBy("created_at").
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, created_at FROM foo WHERE created_at > ? ORDER BY created_at", query)
+	assert.Equal(t, []interface{}{ts}, args)
+}
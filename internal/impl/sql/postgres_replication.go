@@ -0,0 +1,373 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+)
+
+// This file contains the small subset of the PostgreSQL streaming replication
+// protocol and the pgoutput logical decoding format that is needed by the
+// postgres_cdc input. Message formats are described at:
+// https://www.postgresql.org/docs/current/protocol-replication.html
+// https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html
+
+// postgresLSN is a log sequence number, a position within the write ahead log.
+type postgresLSN uint64
+
+// String formats the LSN in the same way as the server, e.g. 16/B374D848.
+func (l postgresLSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(l>>32), uint32(l))
+}
+
+// postgresQuoteIdentifier quotes an identifier so that it can be safely
+// embedded within a statement.
+func postgresQuoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// postgresQuoteLiteral quotes a string literal so that it can be safely
+// embedded within a statement.
+func postgresQuoteLiteral(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `''`) + `'`
+}
+
+//------------------------------------------------------------------------------
+
+// createPostgresReplicationSlot creates a logical replication slot using the
+// pgoutput plugin, and returns the name of the snapshot exported at the point
+// of creation when exportSnapshot is true.
+func createPostgresReplicationSlot(ctx context.Context, conn *pgconn.PgConn, slotName string, temporary, exportSnapshot bool) (string, error) {
+	var sql strings.Builder
+	sql.WriteString("CREATE_REPLICATION_SLOT ")
+	sql.WriteString(postgresQuoteIdentifier(slotName))
+	if temporary {
+		sql.WriteString(" TEMPORARY")
+	}
+	sql.WriteString(" LOGICAL pgoutput")
+	if exportSnapshot {
+		sql.WriteString(" EXPORT_SNAPSHOT")
+	} else {
+		sql.WriteString(" NOEXPORT_SNAPSHOT")
+	}
+
+	results, err := conn.Exec(ctx, sql.String()).ReadAll()
+	if err != nil {
+		return "", err
+	}
+	if len(results) != 1 || len(results[0].Rows) != 1 || len(results[0].Rows[0]) < 3 {
+		return "", errors.New("unexpected response to CREATE_REPLICATION_SLOT")
+	}
+	return string(results[0].Rows[0][2]), nil
+}
+
+// dropPostgresReplicationSlot drops a replication slot that is not active.
+func dropPostgresReplicationSlot(ctx context.Context, conn *pgconn.PgConn, slotName string) error {
+	_, err := conn.Exec(ctx, "DROP_REPLICATION_SLOT "+postgresQuoteIdentifier(slotName)).ReadAll()
+	return err
+}
+
+// startPostgresReplication begins streaming changes of a logical replication
+// slot from the last confirmed position of the slot.
+func startPostgresReplication(ctx context.Context, conn *pgconn.PgConn, slotName, publication string) error {
+	sql := fmt.Sprintf(
+		"START_REPLICATION SLOT %v LOGICAL 0/0 (proto_version '1', publication_names %v)",
+		postgresQuoteIdentifier(slotName),
+		postgresQuoteLiteral(postgresQuoteIdentifier(publication)),
+	)
+	if err := conn.SendBytes(ctx, (&pgproto3.Query{String: sql}).Encode(nil)); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := conn.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		switch m := msg.(type) {
+		case *pgproto3.CopyBothResponse:
+			return nil
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(m)
+		case *pgproto3.NoticeResponse:
+		default:
+			return fmt.Errorf("unexpected response to START_REPLICATION: %T", msg)
+		}
+	}
+}
+
+// postgresEpoch is the point in time from which the replication protocol
+// measures timestamps.
+var postgresEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// sendPostgresStandbyStatus confirms to the server that all changes up to and
+// including an LSN have been processed.
+func sendPostgresStandbyStatus(ctx context.Context, conn *pgconn.PgConn, lsn postgresLSN) error {
+	data := make([]byte, 0, 34)
+	data = append(data, 'r')
+	for i := 0; i < 3; i++ {
+		// The write, flush and apply positions are all the same.
+		data = appendUint64(data, uint64(lsn))
+	}
+	data = appendUint64(data, uint64(time.Since(postgresEpoch).Microseconds()))
+	data = append(data, 0)
+	return conn.SendBytes(ctx, (&pgproto3.CopyData{Data: data}).Encode(nil))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	postgresKeepaliveByteID = 'k'
+	postgresXLogDataByteID  = 'w'
+)
+
+// postgresKeepalive is sent periodically by the server, and may request an
+// immediate status update.
+type postgresKeepalive struct {
+	serverWALEnd   postgresLSN
+	replyRequested bool
+}
+
+func parsePostgresKeepalive(data []byte) (postgresKeepalive, error) {
+	if len(data) != 17 {
+		return postgresKeepalive{}, fmt.Errorf("keepalive message length %v, expected 17", len(data))
+	}
+	return postgresKeepalive{
+		serverWALEnd:   postgresLSN(binary.BigEndian.Uint64(data)),
+		replyRequested: data[16] != 0,
+	}, nil
+}
+
+// postgresXLogData contains a logical replication message along with the
+// position of the change within the write ahead log.
+type postgresXLogData struct {
+	walStart postgresLSN
+	walData  []byte
+}
+
+func parsePostgresXLogData(data []byte) (postgresXLogData, error) {
+	if len(data) < 24 {
+		return postgresXLogData{}, fmt.Errorf("xlog data message length %v, expected at least 24", len(data))
+	}
+	return postgresXLogData{
+		walStart: postgresLSN(binary.BigEndian.Uint64(data)),
+		walData:  data[24:],
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+type postgresRelationColumn struct {
+	name     string
+	dataType uint32
+	key      bool
+}
+
+type postgresRelation struct {
+	id        uint32
+	namespace string
+	name      string
+	columns   []postgresRelationColumn
+}
+
+const (
+	postgresTupleNull   = 'n'
+	postgresTupleToast  = 'u'
+	postgresTupleText   = 't'
+	postgresTupleBinary = 'b'
+)
+
+type postgresTupleColumn struct {
+	kind byte
+	data []byte
+}
+
+type postgresBegin struct{}
+
+type postgresCommit struct {
+	endLSN postgresLSN
+}
+
+type postgresInsert struct {
+	relationID uint32
+	tuple      []postgresTupleColumn
+}
+
+// When oldKeyOnly is set the old tuple only contains the values of columns
+// that are part of the replica identity, with all other columns sent as null.
+type postgresUpdate struct {
+	relationID uint32
+	oldTuple   []postgresTupleColumn
+	oldKeyOnly bool
+	newTuple   []postgresTupleColumn
+}
+
+type postgresDelete struct {
+	relationID uint32
+	oldTuple   []postgresTupleColumn
+	oldKeyOnly bool
+}
+
+// pgoutputReader consumes the fields of a pgoutput message, where the first
+// error encountered is retained and all subsequent reads return zero values.
+type pgoutputReader struct {
+	data []byte
+	err  error
+}
+
+func (r *pgoutputReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errors.New("message is truncated")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *pgoutputReader) readByte() byte {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *pgoutputReader) readUint16() uint16 {
+	if b := r.take(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *pgoutputReader) readUint32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *pgoutputReader) readUint64() uint64 {
+	if b := r.take(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *pgoutputReader) readString() string {
+	if r.err != nil {
+		return ""
+	}
+	i := bytes.IndexByte(r.data, 0)
+	if i < 0 {
+		r.err = errors.New("string is not null terminated")
+		return ""
+	}
+	s := string(r.data[:i])
+	r.data = r.data[i+1:]
+	return s
+}
+
+func (r *pgoutputReader) readTuple() []postgresTupleColumn {
+	n := int(r.readUint16())
+	var cols []postgresTupleColumn
+	for i := 0; i < n && r.err == nil; i++ {
+		col := postgresTupleColumn{kind: r.readByte()}
+		switch col.kind {
+		case postgresTupleNull, postgresTupleToast:
+		case postgresTupleText, postgresTupleBinary:
+			col.data = r.take(int(r.readUint32()))
+		default:
+			if r.err == nil {
+				r.err = fmt.Errorf("unknown tuple column kind %q", col.kind)
+			}
+		}
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// parsePgoutputMessage parses a message of the pgoutput plugin, returning nil
+// for message types that are not needed by the input.
+func parsePgoutputMessage(data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, errors.New("message is empty")
+	}
+
+	r := &pgoutputReader{data: data[1:]}
+
+	var msg interface{}
+	switch data[0] {
+	case 'B':
+		msg = postgresBegin{}
+	case 'C':
+		_ = r.readByte()   // Flags
+		_ = r.readUint64() // Commit LSN
+		msg = postgresCommit{endLSN: postgresLSN(r.readUint64())}
+	case 'R':
+		rel := postgresRelation{id: r.readUint32()}
+		rel.namespace = r.readString()
+		rel.name = r.readString()
+		_ = r.readByte() // Replica identity
+		n := int(r.readUint16())
+		for i := 0; i < n && r.err == nil; i++ {
+			flags := r.readByte()
+			col := postgresRelationColumn{key: flags&1 == 1, name: r.readString()}
+			col.dataType = r.readUint32()
+			_ = r.readUint32() // Type modifier
+			rel.columns = append(rel.columns, col)
+		}
+		msg = rel
+	case 'I':
+		ins := postgresInsert{relationID: r.readUint32()}
+		if kind := r.readByte(); kind != 'N' && r.err == nil {
+			return nil, fmt.Errorf("unexpected insert tuple type %q", kind)
+		}
+		ins.tuple = r.readTuple()
+		msg = ins
+	case 'U':
+		upd := postgresUpdate{relationID: r.readUint32()}
+		kind := r.readByte()
+		if kind == 'K' || kind == 'O' {
+			upd.oldTuple = r.readTuple()
+			upd.oldKeyOnly = kind == 'K'
+			kind = r.readByte()
+		}
+		if kind != 'N' && r.err == nil {
+			return nil, fmt.Errorf("unexpected update tuple type %q", kind)
+		}
+		upd.newTuple = r.readTuple()
+		msg = upd
+	case 'D':
+		del := postgresDelete{relationID: r.readUint32()}
+		kind := r.readByte()
+		if kind != 'K' && kind != 'O' && r.err == nil {
+			return nil, fmt.Errorf("unexpected delete tuple type %q", kind)
+		}
+		del.oldTuple = r.readTuple()
+		del.oldKeyOnly = kind == 'K'
+		msg = del
+	default:
+		// Origin, type, truncate and generic messages are ignored.
+		return nil, nil
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to parse message of type %q: %w", data[0], r.err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,178 @@
+package sql
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pgoutputBuilder []byte
+
+func (b pgoutputBuilder) byte(v byte) pgoutputBuilder {
+	return append(b, v)
+}
+
+func (b pgoutputBuilder) uint16(v uint16) pgoutputBuilder {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func (b pgoutputBuilder) uint32(v uint32) pgoutputBuilder {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func (b pgoutputBuilder) uint64(v uint64) pgoutputBuilder {
+	return appendUint64(b, v)
+}
+
+func (b pgoutputBuilder) string(v string) pgoutputBuilder {
+	return append(append(b, v...), 0)
+}
+
+func (b pgoutputBuilder) text(v string) pgoutputBuilder {
+	return append(b.byte('t').uint32(uint32(len(v))), v...)
+}
+
+func TestPostgresLSN(t *testing.T) {
+	assert.Equal(t, "0/0", postgresLSN(0).String())
+	assert.Equal(t, "16/B374D848", postgresLSN(0x16B374D848).String())
+}
+
+func TestPostgresQuoting(t *testing.T) {
+	assert.Equal(t, `"foo"`, postgresQuoteIdentifier("foo"))
+	assert.Equal(t, `"fo""o"`, postgresQuoteIdentifier(`fo"o`))
+	assert.Equal(t, `'foo'`, postgresQuoteLiteral("foo"))
+	assert.Equal(t, `'fo''o'`, postgresQuoteLiteral("fo'o"))
+	assert.Equal(t, `'"it''s"'`, postgresQuoteLiteral(postgresQuoteIdentifier("it's")))
+}
+
+func TestPostgresReplicationMessages(t *testing.T) {
+	keepalive, err := parsePostgresKeepalive(pgoutputBuilder{}.uint64(20).uint64(0).byte(1))
+	require.NoError(t, err)
+	assert.Equal(t, postgresKeepalive{serverWALEnd: 20, replyRequested: true}, keepalive)
+
+	_, err = parsePostgresKeepalive(pgoutputBuilder{}.uint64(20))
+	require.Error(t, err)
+
+	xld, err := parsePostgresXLogData(pgoutputBuilder{}.uint64(10).uint64(20).uint64(0).byte('B'))
+	require.NoError(t, err)
+	assert.Equal(t, postgresLSN(10), xld.walStart)
+	assert.Equal(t, []byte("B"), xld.walData)
+
+	_, err = parsePostgresXLogData(pgoutputBuilder{}.uint64(10))
+	require.Error(t, err)
+}
+
+func TestPgoutputParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    pgoutputBuilder
+		expected interface{}
+	}{
+		{
+			name:     "begin",
+			input:    pgoutputBuilder{}.byte('B').uint64(20).uint64(0).uint32(7),
+			expected: postgresBegin{},
+		},
+		{
+			name:     "commit",
+			input:    pgoutputBuilder{}.byte('C').byte(0).uint64(15).uint64(20).uint64(0),
+			expected: postgresCommit{endLSN: 20},
+		},
+		{
+			name: "relation",
+			input: pgoutputBuilder{}.byte('R').uint32(5).string("public").string("users").byte('d').uint16(2).
+				byte(1).string("id").uint32(23).uint32(0).
+				byte(0).string("name").uint32(25).uint32(0),
+			expected: postgresRelation{
+				id:        5,
+				namespace: "public",
+				name:      "users",
+				columns: []postgresRelationColumn{
+					{name: "id", dataType: 23, key: true},
+					{name: "name", dataType: 25},
+				},
+			},
+		},
+		{
+			name:  "insert",
+			input: pgoutputBuilder{}.byte('I').uint32(5).byte('N').uint16(2).text("1").byte('n'),
+			expected: postgresInsert{
+				relationID: 5,
+				tuple: []postgresTupleColumn{
+					{kind: postgresTupleText, data: []byte("1")},
+					{kind: postgresTupleNull},
+				},
+			},
+		},
+		{
+			name:  "update with old tuple",
+			input: pgoutputBuilder{}.byte('U').uint32(5).byte('K').uint16(1).text("1").byte('N').uint16(2).text("1").byte('u'),
+			expected: postgresUpdate{
+				relationID: 5,
+				oldTuple: []postgresTupleColumn{
+					{kind: postgresTupleText, data: []byte("1")},
+				},
+				oldKeyOnly: true,
+				newTuple: []postgresTupleColumn{
+					{kind: postgresTupleText, data: []byte("1")},
+					{kind: postgresTupleToast},
+				},
+			},
+		},
+		{
+			name:  "update without old tuple",
+			input: pgoutputBuilder{}.byte('U').uint32(5).byte('N').uint16(1).text(""),
+			expected: postgresUpdate{
+				relationID: 5,
+				newTuple: []postgresTupleColumn{
+					{kind: postgresTupleText, data: []byte{}},
+				},
+			},
+		},
+		{
+			name:  "delete",
+			input: pgoutputBuilder{}.byte('D').uint32(5).byte('O').uint16(1).text("1"),
+			expected: postgresDelete{
+				relationID: 5,
+				oldTuple: []postgresTupleColumn{
+					{kind: postgresTupleText, data: []byte("1")},
+				},
+			},
+		},
+		{
+			name:     "ignored",
+			input:    pgoutputBuilder{}.byte('O').uint64(10).string("foo"),
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			msg, err := parsePgoutputMessage(test.input)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, msg)
+		})
+	}
+}
+
+func TestPgoutputParseErrors(t *testing.T) {
+	for _, input := range []pgoutputBuilder{
+		{},
+		pgoutputBuilder{}.byte('C').byte(0).uint64(15),
+		pgoutputBuilder{}.byte('R').uint32(5).byte('p'),
+		pgoutputBuilder{}.byte('I').uint32(5).byte('X'),
+		pgoutputBuilder{}.byte('I').uint32(5).byte('N').uint16(1).byte('t').uint32(1000).byte('a'),
+		pgoutputBuilder{}.byte('I').uint32(5).byte('N').uint16(1).byte('z'),
+		pgoutputBuilder{}.byte('D').uint32(5).byte('N').uint16(0),
+	} {
+		_, err := parsePgoutputMessage(input)
+		assert.Error(t, err, "%q", []byte(input))
+	}
+}
@@ -22,6 +22,7 @@ type Manager interface {
 	ForStream(id string) Manager
 	IntoPath(segments ...string) Manager
 	WithAddedMetrics(m metrics.Type) Manager
+	WithLogger(l log.Modular) Manager
 
 	Path() []string
 	Label() string
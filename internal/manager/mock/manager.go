@@ -56,6 +56,9 @@ func (m *Manager) Path() []string { return nil }
 // WithAddedMetrics returns the same mock manager.
 func (m *Manager) WithAddedMetrics(m2 metrics.Type) interop.Manager { return m }
 
+// WithLogger returns the same mock manager.
+func (m *Manager) WithLogger(l log.Modular) interop.Manager { return m }
+
 // Label always returns empty.
 func (m *Manager) Label() string { return "" }
 
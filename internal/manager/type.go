@@ -283,6 +283,14 @@ func (t *Type) WithAddedMetrics(m metrics.Type) interop.Manager {
 	return &newT
 }
 
+// WithLogger returns a modified version of the manager where the logger is
+// replaced with the provided one.
+func (t *Type) WithLogger(l log.Modular) interop.Manager {
+	newT := *t
+	newT.logger = l
+	return &newT
+}
+
 //------------------------------------------------------------------------------
 
 // RegisterEndpoint registers a server wide HTTP endpoint.
@@ -22,7 +22,7 @@ func init() {
 type rwData struct {
 	rawBytes  []byte
 	jsonCache interface{}
-	metadata  map[string]string
+	metadata  map[string]interface{}
 }
 
 // Part represents a single Benthos message.
@@ -45,9 +45,9 @@ func NewPart(data []byte) *Part {
 
 // Copy creates a shallow copy of the message part.
 func (p *Part) Copy() *Part {
-	var clonedMeta map[string]string
+	var clonedMeta map[string]interface{}
 	if p.data.metadata != nil {
-		clonedMeta = make(map[string]string, len(p.data.metadata))
+		clonedMeta = make(map[string]interface{}, len(p.data.metadata))
 		for k, v := range p.data.metadata {
 			clonedMeta[k] = v
 		}
@@ -64,10 +64,13 @@ func (p *Part) Copy() *Part {
 
 // DeepCopy creates a new deep copy of the message part.
 func (p *Part) DeepCopy() *Part {
-	var clonedMeta map[string]string
+	var clonedMeta map[string]interface{}
 	if p.data.metadata != nil {
-		clonedMeta = make(map[string]string, len(p.data.metadata))
+		clonedMeta = make(map[string]interface{}, len(p.data.metadata))
 		for k, v := range p.data.metadata {
+			if cv, err := cloneGeneric(v); err == nil {
+				v = cv
+			}
 			clonedMeta[k] = v
 		}
 	}
@@ -191,17 +194,41 @@ func (p *Part) SetJSON(jObj interface{}) {
 //------------------------------------------------------------------------------
 
 // MetaGet returns a metadata value if a key exists, otherwise an empty string.
+// Values that are not strings are converted into their string representation.
 func (p *Part) MetaGet(key string) string {
 	if p.data.metadata == nil {
 		return ""
 	}
-	return p.data.metadata[key]
+	v, exists := p.data.metadata[key]
+	if !exists {
+		return ""
+	}
+	return metaToString(v)
+}
+
+// MetaGetMut returns a metadata value in its original type if a key exists,
+// along with a boolean indicating whether the key was found. The returned value
+// must not be mutated.
+func (p *Part) MetaGetMut(key string) (interface{}, bool) {
+	if p.data.metadata == nil {
+		return nil, false
+	}
+	v, exists := p.data.metadata[key]
+	return v, exists
 }
 
 // MetaSet sets the value of a metadata key.
 func (p *Part) MetaSet(key, value string) {
+	p.MetaSetMut(key, value)
+}
+
+// MetaSetMut sets the value of a metadata key to a value of any type, which is
+// preserved for consumers reading the value with MetaGetMut. Supported types
+// are strings, byte slices, numbers, booleans, timestamps and the structured
+// types []interface{} and map[string]interface{}.
+func (p *Part) MetaSetMut(key string, value interface{}) {
 	if p.data.metadata == nil {
-		p.data.metadata = map[string]string{
+		p.data.metadata = map[string]interface{}{
 			key: value,
 		}
 		return
@@ -217,12 +244,21 @@ func (p *Part) MetaDelete(key string) {
 	delete(p.data.metadata, key)
 }
 
-// MetaIter iterates each metadata key/value pair.
+// MetaIter iterates each metadata key/value pair, where values that are not
+// strings are converted into their string representation.
 func (p *Part) MetaIter(f func(k, v string) error) error {
+	return p.MetaIterMut(func(k string, v interface{}) error {
+		return f(k, metaToString(v))
+	})
+}
+
+// MetaIterMut iterates each metadata key/value pair with values in their
+// original type. The values must not be mutated.
+func (p *Part) MetaIterMut(f func(k string, v interface{}) error) error {
 	if p.data.metadata == nil {
 		// Warning: If we remove this we need to compensate with a way to force
 		// initialisation
-		p.data.metadata = map[string]string{}
+		p.data.metadata = map[string]interface{}{}
 		return nil
 	}
 	for ak, av := range p.data.metadata {
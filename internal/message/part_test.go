@@ -3,6 +3,7 @@ package message
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestPartBasic(t *testing.T) {
@@ -57,6 +58,59 @@ func TestPartBasic(t *testing.T) {
 	}
 }
 
+func TestPartTypedMetadata(t *testing.T) {
+	ts := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	p := NewPart(nil)
+	p.MetaSetMut("str", "hello")
+	p.MetaSetMut("int", int64(42))
+	p.MetaSetMut("float", 1.5)
+	p.MetaSetMut("bool", true)
+	p.MetaSetMut("ts", ts)
+	p.MetaSetMut("obj", map[string]interface{}{"foo": "bar"})
+
+	for k, exp := range map[string]interface{}{
+		"str":   "hello",
+		"int":   int64(42),
+		"float": 1.5,
+		"bool":  true,
+		"ts":    ts,
+		"obj":   map[string]interface{}{"foo": "bar"},
+	} {
+		act, exists := p.MetaGetMut(k)
+		if !exists {
+			t.Errorf("Key %v not found", k)
+		}
+		if !reflect.DeepEqual(exp, act) {
+			t.Errorf("Wrong result for %v: %v != %v", k, act, exp)
+		}
+	}
+
+	for k, exp := range map[string]string{
+		"str":   "hello",
+		"int":   "42",
+		"float": "1.5",
+		"bool":  "true",
+		"ts":    "2022-03-04T05:06:07Z",
+		"obj":   `{"foo":"bar"}`,
+		"nope":  "",
+	} {
+		if act := p.MetaGet(k); exp != act {
+			t.Errorf("Wrong string result for %v: %v != %v", k, act, exp)
+		}
+	}
+
+	p2 := p.DeepCopy()
+	obj, _ := p2.MetaGetMut("obj")
+	obj.(map[string]interface{})["foo"] = "changed"
+	if exp, act := `{"foo":"bar"}`, p.MetaGet("obj"); exp != act {
+		t.Errorf("Deep copy mutated original: %v != %v", act, exp)
+	}
+	if exp, act := ts, p2.data.metadata["ts"]; exp != act {
+		t.Errorf("Wrong copied timestamp: %v != %v", act, exp)
+	}
+}
+
 func TestPartShallowCopy(t *testing.T) {
 	p := NewPart([]byte(`{"hello":"world"}`))
 	p.MetaSet("foo", "bar")
@@ -83,6 +137,24 @@ func TestPartShallowCopy(t *testing.T) {
 	}
 }
 
+func TestPartDeepCopyBytesMetadata(t *testing.T) {
+	p := NewPart(nil)
+	p.MetaSetMut("foo", []byte("bar"))
+
+	p2 := p.DeepCopy()
+
+	v, _ := p2.MetaGetMut("foo")
+	v.([]byte)[0] = 'c'
+	if exp, act := "car", string(v.([]byte)); exp != act {
+		t.Errorf("Wrong copied metadata: %v != %v", act, exp)
+	}
+
+	v, _ = p.MetaGetMut("foo")
+	if exp, act := "bar", string(v.([]byte)); exp != act {
+		t.Errorf("Metadata changed after deep copy: %v != %v", act, exp)
+	}
+}
+
 func TestPartCopyDirtyJSON(t *testing.T) {
 	p := NewPart(nil)
 	dirtyObj := map[string]int{
@@ -3,6 +3,8 @@ package message
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 )
 
 //------------------------------------------------------------------------------
@@ -47,6 +49,39 @@ func GetAllBytesLen(m *Batch) int {
 
 //------------------------------------------------------------------------------
 
+// metaToString converts a typed metadata value into the string representation
+// given to consumers of metadata that only support strings.
+func metaToString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case int:
+		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case uint64:
+		return strconv.FormatUint(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(t)
+	case json.Number:
+		return t.String()
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case nil:
+		return "null"
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+//------------------------------------------------------------------------------
+
 func cloneMap(oldMap map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	newMap := make(map[string]interface{}, len(oldMap))
@@ -90,7 +125,11 @@ func cloneGeneric(root interface{}) (interface{}, error) {
 		return cloneCheekyMap(t)
 	case []interface{}:
 		return cloneSlice(t)
-	case string, []byte, json.Number, uint64, int, int64, float64, bool, json.RawMessage:
+	case []byte:
+		tCopy := make([]byte, len(t))
+		copy(tCopy, t)
+		return tCopy, nil
+	case string, json.Number, uint64, int, int64, float64, bool, json.RawMessage, time.Time:
 		return t, nil
 	default:
 		// Oops, this means we have 'dirty' types within the JSON object. Our
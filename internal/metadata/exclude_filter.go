@@ -54,3 +54,16 @@ func (f *ExcludeFilter) Iter(m *message.Part, fn func(k, v string) error) error
 		return fn(k, v)
 	})
 }
+
+// IterMut applies a function to each metadata key value pair that passes the
+// filter, with values in their original type.
+func (f *ExcludeFilter) IterMut(m *message.Part, fn func(k string, v interface{}) error) error {
+	return m.MetaIterMut(func(k string, v interface{}) error {
+		for _, prefix := range f.excludePrefixes {
+			if strings.HasPrefix(k, prefix) {
+				return nil
+			}
+		}
+		return fn(k, v)
+	})
+}
@@ -96,3 +96,14 @@ func (f *IncludeFilter) Iter(m *message.Part, fn func(k, v string) error) error
 		return fn(k, v)
 	})
 }
+
+// IterMut applies a function to each metadata key value pair that passes the
+// filter, with values in their original type.
+func (f *IncludeFilter) IterMut(m *message.Part, fn func(k string, v interface{}) error) error {
+	return m.MetaIterMut(func(k string, v interface{}) error {
+		if !f.Match(k) {
+			return nil
+		}
+		return fn(k, v)
+	})
+}
@@ -128,3 +128,26 @@ func TestMetadataFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestIncludeFilterIterMut(t *testing.T) {
+	part := message.NewPart(nil)
+	part.MetaSetMut("foo_num", int64(5))
+	part.MetaSetMut("foo_bool", true)
+	part.MetaSetMut("bar", "nope")
+
+	filter, err := IncludeFilterConfig{
+		IncludePrefixes: []string{"foo"},
+	}.CreateFilter()
+	require.NoError(t, err)
+
+	outputMeta := map[string]interface{}{}
+	require.NoError(t, filter.IterMut(part, func(k string, v interface{}) error {
+		outputMeta[k] = v
+		return nil
+	}))
+
+	assert.Equal(t, map[string]interface{}{
+		"foo_num":  int64(5),
+		"foo_bool": true,
+	}, outputMeta)
+}
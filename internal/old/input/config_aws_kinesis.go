@@ -32,6 +32,7 @@ type AWSKinesisConfig struct {
 	session.Config  `json:",inline" yaml:",inline"`
 	Streams         []string                 `json:"streams" yaml:"streams"`
 	DynamoDB        DynamoDBCheckpointConfig `json:"dynamodb" yaml:"dynamodb"`
+	CheckpointCache string                   `json:"checkpoint_cache" yaml:"checkpoint_cache"`
 	CheckpointLimit int                      `json:"checkpoint_limit" yaml:"checkpoint_limit"`
 	CommitPeriod    string                   `json:"commit_period" yaml:"commit_period"`
 	LeasePeriod     string                   `json:"lease_period" yaml:"lease_period"`
@@ -46,6 +47,7 @@ func NewAWSKinesisConfig() AWSKinesisConfig {
 		Config:          session.NewConfig(),
 		Streams:         []string{},
 		DynamoDB:        NewDynamoDBCheckpointConfig(),
+		CheckpointCache: "",
 		CheckpointLimit: 1024,
 		CommitPeriod:    "5s",
 		LeasePeriod:     "30s",
@@ -3,11 +3,14 @@ package input
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/benthosdev/benthos/v4/internal/checkpoint"
 	"github.com/benthosdev/benthos/v4/internal/codec"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/input"
@@ -32,8 +35,13 @@ Consumes data from files on disk, emitting messages according to a chosen codec.
 			codec.ReaderDocs,
 			docs.FieldInt("max_buffer", "The largest token size expected when consuming delimited files.").Advanced(),
 			docs.FieldBool("delete_on_finish", "Whether to delete consumed files from the disk once they are fully consumed.").Advanced(),
+			docs.FieldString("checkpoint_cache", "An optional [cache resource](/docs/components/caches/about) in which to store the progress of each file, allowing consumption to resume from the last delivered message after a restart.").Advanced(),
 		),
 		Description: `
+### Checkpointing
+
+When a ` + "`checkpoint_cache`" + ` is configured the number of messages (or batches when the codec produces them) delivered from each file is stored within the cache under the path of the file. When a file is opened again the messages that have already been delivered are skipped. The checkpoint of a file is removed when it is deleted with ` + "`delete_on_finish`" + `.
+
 ### Metadata
 
 This input adds the following metadata fields to each message:
@@ -66,19 +74,21 @@ input:
 
 // FileConfig contains configuration values for the File input type.
 type FileConfig struct {
-	Paths          []string `json:"paths" yaml:"paths"`
-	Codec          string   `json:"codec" yaml:"codec"`
-	MaxBuffer      int      `json:"max_buffer" yaml:"max_buffer"`
-	DeleteOnFinish bool     `json:"delete_on_finish" yaml:"delete_on_finish"`
+	Paths           []string `json:"paths" yaml:"paths"`
+	Codec           string   `json:"codec" yaml:"codec"`
+	MaxBuffer       int      `json:"max_buffer" yaml:"max_buffer"`
+	DeleteOnFinish  bool     `json:"delete_on_finish" yaml:"delete_on_finish"`
+	CheckpointCache string   `json:"checkpoint_cache" yaml:"checkpoint_cache"`
 }
 
 // NewFileConfig creates a new FileConfig with default values.
 func NewFileConfig() FileConfig {
 	return FileConfig{
-		Paths:          []string{},
-		Codec:          "lines",
-		MaxBuffer:      1000000,
-		DeleteOnFinish: false,
+		Paths:           []string{},
+		Codec:           "lines",
+		MaxBuffer:       1000000,
+		DeleteOnFinish:  false,
+		CheckpointCache: "",
 	}
 }
 
@@ -86,7 +96,7 @@ func NewFileConfig() FileConfig {
 
 // NewFile creates a new File input type.
 func NewFile(conf Config, mgr interop.Manager, log log.Modular, stats metrics.Type) (input.Streamed, error) {
-	rdr, err := newFileConsumer(conf.File, log)
+	rdr, err := newFileConsumer(conf.File, mgr, log)
 	if err != nil {
 		return nil, err
 	}
@@ -95,8 +105,13 @@ func NewFile(conf Config, mgr interop.Manager, log log.Modular, stats metrics.Ty
 
 //------------------------------------------------------------------------------
 
+// fileCheckpointLimit is the maximum number of messages of a file that can be
+// in flight before back pressure is applied when checkpointing is enabled.
+const fileCheckpointLimit = 1024
+
 type fileConsumer struct {
 	log log.Modular
+	mgr interop.Manager
 
 	paths       []string
 	scannerCtor codec.ReaderConstructor
@@ -105,10 +120,18 @@ type fileConsumer struct {
 	scanner     codec.Reader
 	currentPath string
 
+	// When checkpointing the number of batches consumed from the current file
+	// is tracked, along with the number of batches that were already delivered
+	// before and should therefore be skipped.
+	checkpointCache string
+	store           *checkpoint.CacheStore
+	consumed        int64
+	skip            int64
+
 	delete bool
 }
 
-func newFileConsumer(conf FileConfig, log log.Modular) (*fileConsumer, error) {
+func newFileConsumer(conf FileConfig, mgr interop.Manager, log log.Modular) (*fileConsumer, error) {
 	expandedPaths, err := filepath.Globs(conf.Paths)
 	if err != nil {
 		return nil, err
@@ -121,11 +144,17 @@ func newFileConsumer(conf FileConfig, log log.Modular) (*fileConsumer, error) {
 		return nil, err
 	}
 
+	if conf.CheckpointCache != "" && !mgr.ProbeCache(conf.CheckpointCache) {
+		return nil, fmt.Errorf("cache resource '%v' was not found", conf.CheckpointCache)
+	}
+
 	return &fileConsumer{
-		log:         log,
-		scannerCtor: ctor,
-		paths:       expandedPaths,
-		delete:      conf.DeleteOnFinish,
+		log:             log,
+		mgr:             mgr,
+		scannerCtor:     ctor,
+		paths:           expandedPaths,
+		checkpointCache: conf.CheckpointCache,
+		delete:          conf.DeleteOnFinish,
 	}, nil
 }
 
@@ -149,6 +178,22 @@ func (f *fileConsumer) getReader(ctx context.Context) (codec.Reader, string, err
 
 	nextPath := f.paths[0]
 
+	var store *checkpoint.CacheStore
+	var skip int64
+	if f.checkpointCache != "" {
+		store = checkpoint.NewCacheStore(checkpoint.ManagerCacheAccess(f.mgr, f.checkpointCache), nextPath, fileCheckpointLimit)
+
+		consumedStr, exists, err := store.Load(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		if exists {
+			if skip, err = strconv.ParseInt(consumedStr, 10, 64); err != nil {
+				return nil, "", fmt.Errorf("failed to parse checkpoint: %w", err)
+			}
+		}
+	}
+
 	file, err := os.Open(nextPath)
 	if err != nil {
 		return nil, "", err
@@ -156,6 +201,11 @@ func (f *fileConsumer) getReader(ctx context.Context) (codec.Reader, string, err
 
 	if f.scanner, err = f.scannerCtor(nextPath, file, func(ctx context.Context, err error) error {
 		if err == nil && f.delete {
+			if store != nil {
+				if err := store.Delete(ctx); err != nil {
+					return err
+				}
+			}
 			return os.Remove(nextPath)
 		}
 		return nil
@@ -166,6 +216,9 @@ func (f *fileConsumer) getReader(ctx context.Context) (codec.Reader, string, err
 
 	f.currentPath = nextPath
 	f.paths = f.paths[1:]
+	f.store = store
+	f.consumed = 0
+	f.skip = skip
 
 	f.log.Infof("Consuming from file '%v'\n", nextPath)
 	return f.scanner, f.currentPath, nil
@@ -195,6 +248,20 @@ func (f *fileConsumer) ReadWithContext(ctx context.Context) (*message.Batch, rea
 			return nil, nil, err
 		}
 
+		var resolveFn func(context.Context) error
+		if f.store != nil {
+			f.consumed++
+			if f.consumed <= f.skip {
+				// This batch was delivered before the file was last closed.
+				_ = codecAckFn(ctx, nil)
+				continue
+			}
+			if resolveFn, err = f.store.Track(ctx, strconv.FormatInt(f.consumed, 10), 1); err != nil {
+				_ = codecAckFn(ctx, err)
+				return nil, nil, err
+			}
+		}
+
 		msg := message.QuickBatch(nil)
 		for _, part := range parts {
 			if len(part.Get()) > 0 {
@@ -203,11 +270,21 @@ func (f *fileConsumer) ReadWithContext(ctx context.Context) (*message.Batch, rea
 			}
 		}
 		if msg.Len() == 0 {
+			if resolveFn != nil {
+				_ = resolveFn(ctx)
+			}
 			_ = codecAckFn(ctx, nil)
 			return nil, nil, component.ErrTimeout
 		}
 
 		return msg, func(rctx context.Context, res error) error {
+			// The checkpoint must be resolved before the codec ack as the
+			// final ack of a file may delete the checkpoint.
+			if res == nil && resolveFn != nil {
+				if err := resolveFn(rctx); err != nil {
+					return err
+				}
+			}
 			return codecAckFn(rctx, res)
 		}, nil
 	}
@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
 )
 
 func TestFileDirectory(t *testing.T) {
@@ -51,7 +54,7 @@ func TestFileDirectory(t *testing.T) {
 	}
 	conf.Codec = "all-bytes"
 
-	f, err := newFileConsumer(conf, log.Noop())
+	f, err := newFileConsumer(conf, mock.NewManager(), log.Noop())
 	require.NoError(t, err)
 
 	err = f.ConnectWithContext(context.Background())
@@ -84,3 +87,49 @@ func TestFileDirectory(t *testing.T) {
 		t.Errorf("Wrong result: %v != %v", act, exp)
 	}
 }
+
+func TestFileCheckpoint(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	path := filepath.Join(tmpDir, "foo.txt")
+	require.NoError(t, os.WriteFile(path, []byte("foo\nbar\nbaz\n"), 0o644))
+
+	mgr := mock.NewManager()
+	mgr.Caches["foocache"] = map[string]mock.CacheItem{}
+
+	conf := NewFileConfig()
+	conf.Paths = []string{path}
+	conf.CheckpointCache = "foocache"
+
+	f, err := newFileConsumer(conf, mgr, log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, f.ConnectWithContext(context.Background()))
+
+	msg, aFn, err := f.ReadWithContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(msg.Get(0).Get()))
+	require.NoError(t, aFn(context.Background(), nil))
+	assert.Equal(t, "1", mgr.Caches["foocache"][path].Value)
+
+	msg, _, err = f.ReadWithContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(msg.Get(0).Get()))
+
+	f.CloseAsync()
+	require.NoError(t, f.WaitForClose(time.Second))
+
+	// Unacknowledged messages are consumed again.
+	f, err = newFileConsumer(conf, mgr, log.Noop())
+	require.NoError(t, err)
+	require.NoError(t, f.ConnectWithContext(context.Background()))
+
+	msg, aFn, err = f.ReadWithContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(msg.Get(0).Get()))
+	require.NoError(t, aFn(context.Background(), nil))
+	assert.Equal(t, "2", mgr.Caches["foocache"][path].Value)
+
+	conf.CheckpointCache = "barcache"
+	_, err = newFileConsumer(conf, mgr, log.Noop())
+	require.Error(t, err)
+}
@@ -457,11 +457,11 @@ func dataToPart(highestOffset int64, data *sarama.ConsumerMessage) *message.Part
 	}
 
 	part.MetaSet("kafka_key", string(data.Key))
-	part.MetaSet("kafka_partition", strconv.Itoa(int(data.Partition)))
+	part.MetaSetMut("kafka_partition", int64(data.Partition))
 	part.MetaSet("kafka_topic", data.Topic)
-	part.MetaSet("kafka_offset", strconv.Itoa(int(data.Offset)))
-	part.MetaSet("kafka_lag", strconv.FormatInt(lag, 10))
-	part.MetaSet("kafka_timestamp_unix", strconv.FormatInt(data.Timestamp.Unix(), 10))
+	part.MetaSetMut("kafka_offset", data.Offset)
+	part.MetaSetMut("kafka_lag", lag)
+	part.MetaSetMut("kafka_timestamp_unix", data.Timestamp.Unix())
 
 	return part
 }
@@ -3,7 +3,6 @@ package reader
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -253,11 +252,11 @@ func (m *MQTT) ReadWithContext(ctx context.Context) (*message.Batch, AsyncAckFn,
 		message := message.QuickBatch([][]byte{msg.Payload()})
 
 		p := message.Get(0)
-		p.MetaSet("mqtt_duplicate", strconv.FormatBool(msg.Duplicate()))
-		p.MetaSet("mqtt_qos", strconv.Itoa(int(msg.Qos())))
-		p.MetaSet("mqtt_retained", strconv.FormatBool(msg.Retained()))
+		p.MetaSetMut("mqtt_duplicate", msg.Duplicate())
+		p.MetaSetMut("mqtt_qos", int64(msg.Qos()))
+		p.MetaSetMut("mqtt_retained", msg.Retained())
 		p.MetaSet("mqtt_topic", msg.Topic())
-		p.MetaSet("mqtt_message_id", strconv.Itoa(int(msg.MessageID())))
+		p.MetaSetMut("mqtt_message_id", int64(msg.MessageID()))
 
 		return message, func(ctx context.Context, res error) error {
 			if res == nil {
@@ -1,6 +1,8 @@
 package output
 
 import (
+	"github.com/benthosdev/benthos/v4/internal/batch/policy"
+	"github.com/benthosdev/benthos/v4/internal/codec"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/component/output"
 	"github.com/benthosdev/benthos/v4/internal/docs"
@@ -24,7 +26,8 @@ Only one authentication method is required, ` + "`storage_connection_string`" +
 In order to have a different path for each object you should use function
 interpolations described [here](/docs/configuration/interpolation#bloblang-queries), which are
 calculated per message of a batch.`,
-		Async: true,
+		Async:   true,
+		Batches: true,
 		Config: docs.FieldComponent().WithChildren(
 			docs.FieldString(
 				"storage_account",
@@ -58,7 +61,9 @@ calculated per message of a batch.`,
 			docs.FieldString("blob_type", "Block and Append blobs are comprised of blocks, and each blob can support up to 50,000 blocks. The default value is `+\"`BLOCK`\"+`.`").HasOptions(
 				"BLOCK", "APPEND",
 			).IsInterpolated().Advanced(),
+			codec.ObjectWriterDocs,
 			docs.FieldInt("max_in_flight", "The maximum number of messages to have in flight at a given time. Increase this to improve throughput."),
+			policy.FieldSpec(),
 		),
 		Categories: []string{
 			"Services",
@@ -81,5 +86,8 @@ func NewAzureBlobStorage(conf Config, mgr interop.Manager, log log.Modular, stat
 	if err != nil {
 		return nil, err
 	}
-	return OnlySinglePayloads(a), nil
+	if !blobStorage.WritesBatches() {
+		a = OnlySinglePayloads(a)
+	}
+	return NewBatcherFromConfig(conf.AzureBlobStorage.Batching, a, mgr, log, stats)
 }
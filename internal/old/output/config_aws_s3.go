@@ -21,6 +21,7 @@ type AmazonS3Config struct {
 	WebsiteRedirectLocation string                       `json:"website_redirect_location" yaml:"website_redirect_location"`
 	Metadata                metadata.ExcludeFilterConfig `json:"metadata" yaml:"metadata"`
 	StorageClass            string                       `json:"storage_class" yaml:"storage_class"`
+	Codec                   string                       `json:"codec" yaml:"codec"`
 	Timeout                 string                       `json:"timeout" yaml:"timeout"`
 	KMSKeyID                string                       `json:"kms_key_id" yaml:"kms_key_id"`
 	ServerSideEncryption    string                       `json:"server_side_encryption" yaml:"server_side_encryption"`
@@ -44,6 +45,7 @@ func NewAmazonS3Config() AmazonS3Config {
 		WebsiteRedirectLocation: "",
 		Metadata:                metadata.NewExcludeFilterConfig(),
 		StorageClass:            "STANDARD",
+		Codec:                   "all-bytes",
 		Timeout:                 "5s",
 		KMSKeyID:                "",
 		ServerSideEncryption:    "",
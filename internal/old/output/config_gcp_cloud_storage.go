@@ -28,6 +28,7 @@ type GCPCloudStorageConfig struct {
 	ContentType     string        `json:"content_type" yaml:"content_type"`
 	ContentEncoding string        `json:"content_encoding" yaml:"content_encoding"`
 	ChunkSize       int           `json:"chunk_size" yaml:"chunk_size"`
+	Codec           string        `json:"codec" yaml:"codec"`
 	MaxInFlight     int           `json:"max_in_flight" yaml:"max_in_flight"`
 	Batching        policy.Config `json:"batching" yaml:"batching"`
 	CollisionMode   string        `json:"collision_mode" yaml:"collision_mode"`
@@ -41,6 +42,7 @@ func NewGCPCloudStorageConfig() GCPCloudStorageConfig {
 		ContentType:     "application/octet-stream",
 		ContentEncoding: "",
 		ChunkSize:       googleapi.DefaultUploadChunkSize,
+		Codec:           "all-bytes",
 		MaxInFlight:     64,
 		Batching:        policy.NewConfig(),
 		CollisionMode:   GCPCloudStorageOverwriteCollisionMode,
@@ -169,9 +169,20 @@ func (w *fileWriter) WriteWithContext(ctx context.Context, msg *message.Batch) e
 		}
 		return nil
 	})
+
+	w.handleMut.Lock()
+	defer w.handleMut.Unlock()
 	if err != nil {
+		// Messages of the failed batch that were already written must not be
+		// flushed along with the batch that follows, which is likely a retry.
+		if ab, ok := w.handle.(codec.BatchAborter); ok {
+			ab.AbortBatch(ctx)
+		}
 		return err
 	}
+	if bw, ok := w.handle.(codec.BatchWriter); ok {
+		return bw.EndBatch(ctx)
+	}
 	return nil
 }
 
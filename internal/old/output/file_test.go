@@ -0,0 +1,135 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/codec"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestFileParquetBatchRetry(t *testing.T) {
+	dir := t.TempDir()
+
+	schemaPath := filepath.Join(dir, "schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{
+  "Tag": "name=root, repetitiontype=REQUIRED",
+  "Fields": [
+    {"Tag": "name=name, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REQUIRED"}
+  ]
+}`), 0o644))
+
+	outPath := filepath.Join(dir, "out.parquet")
+	w, err := newFileWriter(outPath, "parquet:"+schemaPath, mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, w.ConnectWithContext(ctx))
+
+	// The first attempt fails on its second message, and the retry that
+	// follows must not duplicate the rows written by the first attempt.
+	require.Error(t, w.WriteWithContext(ctx, message.QuickBatch([][]byte{
+		[]byte(`{"name":"foo"}`),
+		[]byte(`not json`),
+	})))
+	require.NoError(t, w.WriteWithContext(ctx, message.QuickBatch([][]byte{
+		[]byte(`{"name":"foo"}`),
+		[]byte(`{"name":"bar"}`),
+	})))
+
+	w.CloseAsync()
+	require.NoError(t, w.WaitForClose(time.Second*5))
+
+	f, err := os.Open(outPath)
+	require.NoError(t, err)
+
+	rCtor, err := codec.GetReader("parquet", codec.NewReaderConfig())
+	require.NoError(t, err)
+
+	r, err := rCtor(outPath, f, func(ctx context.Context, err error) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	var rows []string
+	for {
+		parts, ackFn, err := r.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		for _, p := range parts {
+			rows = append(rows, string(p.Get()))
+		}
+		require.NoError(t, ackFn(ctx, nil))
+	}
+	assert.Equal(t, []string{`{"name":"foo"}`, `{"name":"bar"}`}, rows)
+	require.NoError(t, r.Close(ctx))
+}
+
+func TestFileAvroOCFBatchRetry(t *testing.T) {
+	dir := t.TempDir()
+
+	schemaPath := filepath.Join(dir, "schema.avsc")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(`{
+  "type": "record",
+  "name": "person",
+  "fields": [{"name": "name", "type": "string"}]
+}`), 0o644))
+
+	outPath := filepath.Join(dir, "out.avro")
+	w, err := newFileWriter(outPath, "avro-ocf:"+schemaPath, mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, w.ConnectWithContext(ctx))
+
+	require.Error(t, w.WriteWithContext(ctx, message.QuickBatch([][]byte{
+		[]byte(`{"name":"foo"}`),
+		[]byte(`{"nope":true}`),
+	})))
+	require.NoError(t, w.WriteWithContext(ctx, message.QuickBatch([][]byte{
+		[]byte(`{"name":"foo"}`),
+		[]byte(`{"name":"bar"}`),
+	})))
+
+	w.CloseAsync()
+	require.NoError(t, w.WaitForClose(time.Second*5))
+
+	f, err := os.Open(outPath)
+	require.NoError(t, err)
+
+	rCtor, err := codec.GetReader("avro-ocf", codec.NewReaderConfig())
+	require.NoError(t, err)
+
+	r, err := rCtor(outPath, f, func(ctx context.Context, err error) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	var rows []string
+	for {
+		parts, ackFn, err := r.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		for _, p := range parts {
+			rows = append(rows, string(p.Get()))
+		}
+		require.NoError(t, ackFn(ctx, nil))
+	}
+	assert.Equal(t, []string{`{"name":"foo"}`, `{"name":"bar"}`}, rows)
+	require.NoError(t, r.Close(ctx))
+}
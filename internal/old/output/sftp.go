@@ -155,7 +155,7 @@ func (s *sftpWriter) WriteWithContext(ctx context.Context, msg *message.Batch) e
 		return component.ErrNotConnected
 	}
 
-	return writer.IterateBatchedSend(msg, func(i int, p *message.Part) error {
+	err := writer.IterateBatchedSend(msg, func(i int, p *message.Part) error {
 		path := s.path.String(i, msg)
 
 		s.handleMut.Lock()
@@ -206,6 +206,21 @@ func (s *sftpWriter) WriteWithContext(ctx context.Context, msg *message.Batch) e
 		}
 		return nil
 	})
+
+	s.handleMut.Lock()
+	defer s.handleMut.Unlock()
+	if err != nil {
+		// Messages of the failed batch that were already written must not be
+		// flushed along with the batch that follows, which is likely a retry.
+		if ab, ok := s.handle.(codec.BatchAborter); ok {
+			ab.AbortBatch(ctx)
+		}
+		return err
+	}
+	if bw, ok := s.handle.(codec.BatchWriter); ok {
+		return bw.EndBatch(ctx)
+	}
+	return nil
 }
 
 // CloseAsync begins cleaning up resources used by this reader asynchronously.
@@ -2,6 +2,7 @@ package output
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
@@ -70,10 +71,13 @@ type stdoutWriter struct {
 }
 
 func newStdoutWriter(codecStr string, log log.Modular, stats metrics.Type) (*stdoutWriter, error) {
-	codec, _, err := codec.GetWriter(codecStr)
+	codec, codecConf, err := codec.GetWriter(codecStr)
 	if err != nil {
 		return nil, err
 	}
+	if codecConf.Batched {
+		return nil, fmt.Errorf("codec %v is not supported by the stdout output", codecStr)
+	}
 
 	handle, err := codec(os.Stdout)
 	if err != nil {
@@ -16,6 +16,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure"
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang/field"
+	"github.com/benthosdev/benthos/v4/internal/codec"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/interop"
 	"github.com/benthosdev/benthos/v4/internal/log"
@@ -32,6 +33,7 @@ type AzureBlobStorage struct {
 	path        *field.Expression
 	blobType    *field.Expression
 	accessLevel *field.Expression
+	codec       codec.WriterConstructor
 	client      storage.BlobStorageClient
 	log         log.Modular
 	stats       metrics.Type
@@ -87,9 +89,25 @@ func NewAzureBlobStorageV2(
 	if a.accessLevel, err = mgr.BloblEnvironment().NewField(conf.PublicAccessLevel); err != nil {
 		return nil, fmt.Errorf("failed to parse public access level expression: %v", err)
 	}
+
+	ctor, codecConf, err := codec.GetWriter(conf.Codec)
+	if err != nil {
+		return nil, err
+	}
+	// Codecs that close after each message write a blob per message, which is
+	// the default behaviour, otherwise each batch is written as a single blob.
+	if !codecConf.CloseAfter {
+		a.codec = ctor
+	}
 	return a, nil
 }
 
+// WritesBatches returns true when the configured codec writes each batch of
+// messages as a single blob.
+func (a *AzureBlobStorage) WritesBatches() bool {
+	return a.codec != nil
+}
+
 // ConnectWithContext attempts to establish a connection to the target Blob Storage Account.
 func (a *AzureBlobStorage) ConnectWithContext(ctx context.Context) error {
 	return a.Connect()
@@ -133,27 +151,41 @@ func (a *AzureBlobStorage) createContainer(c *storage.Container, accessLevel str
 }
 
 // WriteWithContext attempts to write message contents to a target storage account as files.
-func (a *AzureBlobStorage) WriteWithContext(_ context.Context, msg *message.Batch) error {
-	return IterateBatchedSend(msg, func(i int, p *message.Part) error {
-		c := a.client.GetContainerReference(a.container.String(i, msg))
-		b := c.GetBlobReference(a.path.String(i, msg))
-		if err := a.uploadBlob(b, a.blobType.String(i, msg), p.Get()); err != nil {
-			if containerNotFound(err) {
-				if cerr := a.createContainer(c, a.accessLevel.String(i, msg)); cerr != nil {
-					a.log.Debugf("error creating container: %v.", cerr)
-					return cerr
-				}
-				err = a.uploadBlob(b, a.blobType.String(i, msg), p.Get())
-				if err != nil {
-					a.log.Debugf("error retrying to upload  blob: %v.", err)
-				}
-			}
+func (a *AzureBlobStorage) WriteWithContext(ctx context.Context, msg *message.Batch) error {
+	if a.codec != nil {
+		payload, encErr := EncodeBatch(ctx, a.codec, msg)
+		if payload == nil {
+			return encErr
+		}
+		if err := a.writeBlob(0, msg, payload); err != nil {
 			return err
 		}
-		return nil
+		return encErr
+	}
+	return IterateBatchedSend(msg, func(i int, p *message.Part) error {
+		return a.writeBlob(i, msg, p.Get())
 	})
 }
 
+func (a *AzureBlobStorage) writeBlob(i int, msg *message.Batch, payload []byte) error {
+	c := a.client.GetContainerReference(a.container.String(i, msg))
+	b := c.GetBlobReference(a.path.String(i, msg))
+	if err := a.uploadBlob(b, a.blobType.String(i, msg), payload); err != nil {
+		if containerNotFound(err) {
+			if cerr := a.createContainer(c, a.accessLevel.String(i, msg)); cerr != nil {
+				a.log.Debugf("error creating container: %v.", cerr)
+				return cerr
+			}
+			err = a.uploadBlob(b, a.blobType.String(i, msg), payload)
+			if err != nil {
+				a.log.Debugf("error retrying to upload  blob: %v.", err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
 func containerNotFound(err error) bool {
 	if serr, ok := err.(storage.AzureStorageServiceError); ok {
 		return serr.Code == "ContainerNotFound"
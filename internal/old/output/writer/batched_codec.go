@@ -0,0 +1,72 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/internal/codec"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+type bufferWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (b bufferWriteCloser) Close() error {
+	return nil
+}
+
+var _ io.WriteCloser = bufferWriteCloser{}
+
+// EncodeBatch writes each message of a batch through a codec into a single
+// payload, allowing outputs that upload whole objects to support codecs that
+// span multiple messages.
+//
+// Messages that the codec fails to write are omitted from the payload and are
+// reported with a batch error in order to support index specific error
+// handling, in which case the payload of the remaining messages is still
+// returned alongside the error. When no messages could be written a nil payload
+// is returned.
+func EncodeBatch(ctx context.Context, ctor codec.WriterConstructor, msg *message.Batch) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := ctor(bufferWriteCloser{&buf})
+	if err != nil {
+		return nil, err
+	}
+
+	var batchErr *batch.Error
+	_ = msg.Iter(func(i int, p *message.Part) error {
+		if err := w.Write(ctx, p); err != nil {
+			if batchErr == nil {
+				batchErr = batch.NewError(msg, err)
+			}
+			batchErr.Failed(i, err)
+		}
+		return nil
+	})
+
+	if batchErr != nil && batchErr.IndexedErrors() == msg.Len() {
+		_ = w.Close(ctx)
+		if msg.Len() == 1 {
+			return nil, batchErr.Unwrap()
+		}
+		return nil, batchErr
+	}
+
+	if bw, ok := w.(codec.BatchWriter); ok {
+		if err := bw.EndBatch(ctx); err != nil {
+			_ = w.Close(ctx)
+			return nil, err
+		}
+	}
+	if err := w.Close(ctx); err != nil {
+		return nil, err
+	}
+
+	if batchErr != nil {
+		return buf.Bytes(), batchErr
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,90 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/batch"
+	"github.com/benthosdev/benthos/v4/internal/codec"
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+type rejectingCodecWriter struct {
+	w      io.WriteCloser
+	reject string
+	ended  bool
+}
+
+func (r *rejectingCodecWriter) Write(ctx context.Context, p *message.Part) error {
+	if string(p.Get()) == r.reject {
+		return errors.New("rejected")
+	}
+	_, err := r.w.Write(p.Get())
+	return err
+}
+
+func (r *rejectingCodecWriter) EndBatch(ctx context.Context) error {
+	r.ended = true
+	return nil
+}
+
+func (r *rejectingCodecWriter) Close(ctx context.Context) error {
+	return r.w.Close()
+}
+
+func TestEncodeBatchLines(t *testing.T) {
+	ctor, _, err := codec.GetWriter("lines")
+	require.NoError(t, err)
+
+	payload, err := EncodeBatch(context.Background(), ctor, message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("bar"), []byte("baz"),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "foo\nbar\nbaz\n", string(payload))
+}
+
+func TestEncodeBatchPartialErrors(t *testing.T) {
+	var cw *rejectingCodecWriter
+	ctor := func(w io.WriteCloser) (codec.Writer, error) {
+		cw = &rejectingCodecWriter{w: w, reject: "bar"}
+		return cw, nil
+	}
+
+	msg := message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("bar"), []byte("baz"),
+	})
+
+	payload, err := EncodeBatch(context.Background(), ctor, msg)
+	assert.Equal(t, "foobaz", string(payload))
+	assert.True(t, cw.ended)
+
+	var bErr *batch.Error
+	require.True(t, errors.As(err, &bErr))
+	assert.Equal(t, 1, bErr.IndexedErrors())
+
+	var failed []int
+	bErr.WalkParts(func(i int, _ *message.Part, err error) bool {
+		if err != nil {
+			failed = append(failed, i)
+		}
+		return true
+	})
+	assert.Equal(t, []int{1}, failed)
+}
+
+func TestEncodeBatchAllErrors(t *testing.T) {
+	ctor := func(w io.WriteCloser) (codec.Writer, error) {
+		return &rejectingCodecWriter{w: w, reject: "foo"}, nil
+	}
+
+	payload, err := EncodeBatch(context.Background(), ctor, message.QuickBatch([][]byte{
+		[]byte("foo"),
+	}))
+	assert.Nil(t, payload)
+	assert.EqualError(t, err, "rejected")
+}
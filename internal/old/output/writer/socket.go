@@ -66,6 +66,9 @@ func NewSocket(
 	if err != nil {
 		return nil, err
 	}
+	if codecConf.Batched {
+		return nil, fmt.Errorf("codec %v is not supported by this output", conf.Codec)
+	}
 	t := Socket{
 		network:   conf.Network,
 		address:   conf.Address,
@@ -455,3 +455,15 @@ func TestSocketCustomDelimeter(t *testing.T) {
 
 	conn.Close()
 }
+
+func TestSocketBatchedCodec(t *testing.T) {
+	conf := NewSocketConfig()
+	conf.Network = "tcp"
+	conf.Address = "localhost:0"
+	conf.Codec = "parquet:./schema.json"
+
+	_, err := NewSocket(conf, mock.NewManager(), log.Noop(), metrics.Noop())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,35 @@
+package otlpconfig
+
+import (
+	yaml "gopkg.in/yaml.v3"
+)
+
+// EndpointConfig describes a single OpenTelemetry collector endpoint.
+type EndpointConfig struct {
+	URL      string            `json:"url" yaml:"url"`
+	Insecure bool              `json:"insecure" yaml:"insecure"`
+	Headers  map[string]string `json:"headers" yaml:"headers"`
+	Timeout  string            `json:"timeout" yaml:"timeout"`
+}
+
+// NewEndpointConfig creates an EndpointConfig struct with default values.
+func NewEndpointConfig() EndpointConfig {
+	return EndpointConfig{
+		URL:      "",
+		Insecure: false,
+		Headers:  map[string]string{},
+		Timeout:  "10s",
+	}
+}
+
+// UnmarshalYAML ensures that when parsing endpoints within a slice the default
+// values are still applied.
+func (o *EndpointConfig) UnmarshalYAML(value *yaml.Node) error {
+	type confAlias EndpointConfig
+	aliased := confAlias(NewEndpointConfig())
+	if err := value.Decode(&aliased); err != nil {
+		return err
+	}
+	*o = EndpointConfig(aliased)
+	return nil
+}
@@ -0,0 +1,3 @@
+// Package otlpconfig provides configuration fields shared by the components
+// that export to an OpenTelemetry collector using the OTLP protocol.
+package otlpconfig
@@ -0,0 +1,56 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// Counts contains the number of messages that have passed through a stream.
+type Counts struct {
+	// Received is the number of messages consumed from the input.
+	Received int64
+
+	// Delivered is the number of messages consumed from the input that have
+	// been acknowledged as successfully delivered.
+	Delivered int64
+
+	// InFlight is the number of messages consumed from the input that have
+	// not yet been acknowledged.
+	InFlight int64
+}
+
+// messageCounter counts the messages of transactions consumed from the input
+// of a stream, along with the outcome of their acknowledgements.
+type messageCounter struct {
+	received  int64
+	delivered int64
+	inFlight  int64
+}
+
+func (c *messageCounter) track(tran message.Transaction) message.Transaction {
+	n := int64(tran.Payload.Len())
+	atomic.AddInt64(&c.received, n)
+	atomic.AddInt64(&c.inFlight, n)
+	var ackOnce sync.Once
+	return message.NewTransactionFunc(tran.Payload, func(ctx context.Context, err error) (ackErr error) {
+		ackOnce.Do(func() {
+			atomic.AddInt64(&c.inFlight, -n)
+			if err == nil {
+				atomic.AddInt64(&c.delivered, n)
+			}
+			ackErr = tran.Ack(ctx, err)
+		})
+		return
+	})
+}
+
+func (c *messageCounter) counts() Counts {
+	return Counts{
+		Received:  atomic.LoadInt64(&c.received),
+		Delivered: atomic.LoadInt64(&c.delivered),
+		InFlight:  atomic.LoadInt64(&c.inFlight),
+	}
+}
@@ -0,0 +1,32 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+func TestMessageCounterAckOnce(t *testing.T) {
+	var c messageCounter
+
+	var acks []error
+	tran := c.track(message.NewTransactionFunc(message.QuickBatch([][]byte{
+		[]byte("foo"), []byte("bar"),
+	}), func(ctx context.Context, err error) error {
+		acks = append(acks, err)
+		return nil
+	}))
+	assert.Equal(t, Counts{Received: 2, InFlight: 2}, c.counts())
+
+	require.NoError(t, tran.Ack(context.Background(), nil))
+	require.NoError(t, tran.Ack(context.Background(), nil))
+	require.NoError(t, tran.Ack(context.Background(), errors.New("nope")))
+
+	assert.Equal(t, Counts{Received: 2, Delivered: 2}, c.counts())
+	assert.Equal(t, []error{nil}, acks)
+}
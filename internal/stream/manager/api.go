@@ -1,11 +1,13 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -31,7 +33,7 @@ import (
 func (m *Type) registerEndpoints(enableCrud bool) {
 	m.manager.RegisterEndpoint(
 		"/ready",
-		"Returns 200 OK if the inputs and outputs of all running streams are connected, otherwise a 503 is returned. If there are no active streams 200 is returned.",
+		"Returns 200 OK if the inputs and outputs of all running streams are connected and none are paused, otherwise a 503 is returned. If there are no active streams 200 is returned.",
 		m.HandleStreamReady,
 	)
 	if !enableCrud {
@@ -56,6 +58,21 @@ func (m *Type) registerEndpoints(enableCrud bool) {
 		"GET a structured JSON object containing metrics for the stream.",
 		m.HandleStreamStats,
 	)
+	m.manager.RegisterEndpoint(
+		"/streams/{id}/status",
+		"GET a structured JSON object describing the health of the stream, including the connection state of its input and output, its throughput and the last error of each component.",
+		m.HandleStreamStatus,
+	)
+	m.manager.RegisterEndpoint(
+		"/streams/{id}/pause",
+		"POST: Pause a stream, which stops it from consuming data from its input without closing any connections.",
+		m.HandleStreamPause,
+	)
+	m.manager.RegisterEndpoint(
+		"/streams/{id}/resume",
+		"POST: Resume a paused stream.",
+		m.HandleStreamResume,
+	)
 	m.manager.RegisterEndpoint(
 		"/resources/{type}/{id}",
 		"POST: Create or replace a given resource configuration of a specified type. Types supported are `cache`, `input`, `output`, `processor` and `rate_limit`.",
@@ -75,6 +92,9 @@ func (c ConfigSet) UnmarshalYAML(value *yaml.Node) error {
 		return err
 	}
 	for k, v := range tmpSet {
+		if err := config.ExpandStreamTemplates(&v); err != nil {
+			return err
+		}
 		conf := stream.NewConfig()
 		if err := v.Decode(&conf); err != nil {
 			return err
@@ -84,8 +104,26 @@ func (c ConfigSet) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
-func lintStreamConfigNode(node *yaml.Node) (lints []string) {
-	for _, dLint := range stream.Spec().LintYAML(docs.NewLintContext(), node) {
+// parseStreamConfig parses a stream config, expanding any stream template that
+// it uses, and returns both the resulting config and its YAML node along with
+// linting errors for the fields of the stream template.
+func parseStreamConfig(confBytes []byte) (conf stream.Config, node yaml.Node, tmplLints []docs.Lint, err error) {
+	conf = stream.NewConfig()
+	if err = yaml.Unmarshal(confBytes, &node); err != nil {
+		return
+	}
+	if node.Kind == 0 {
+		return
+	}
+	if tmplLints, err = config.ExpandStreamTemplatesLinted(docs.NewLintContext(), &node); err != nil {
+		return
+	}
+	err = node.Decode(&conf)
+	return
+}
+
+func lintStreamConfigNode(tmplLints []docs.Lint, node *yaml.Node) (lints []string) {
+	for _, dLint := range append(tmplLints, stream.Spec().LintYAML(docs.NewLintContext(), node)...) {
 		lints = append(lints, fmt.Sprintf("line %v: %v", dLint.Line, dLint.What))
 	}
 	return
@@ -112,20 +150,42 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
+	type lastErrInfo struct {
+		Path string `json:"path"`
+		ComponentError
+	}
 	type confInfo struct {
-		Active    bool    `json:"active"`
-		Uptime    float64 `json:"uptime"`
-		UptimeStr string  `json:"uptime_str"`
+		Active          bool         `json:"active"`
+		Paused          bool         `json:"paused"`
+		Uptime          float64      `json:"uptime"`
+		UptimeStr       string       `json:"uptime_str"`
+		InputConnected  bool         `json:"input_connected"`
+		OutputConnected bool         `json:"output_connected"`
+		ReceivedPerSec  float64      `json:"received_per_second"`
+		DeliveredPerSec float64      `json:"delivered_per_second"`
+		InFlight        int64        `json:"in_flight"`
+		LastError       *lastErrInfo `json:"last_error,omitempty"`
 	}
 	infos := map[string]confInfo{}
 
 	m.lock.Lock()
 	for id, strInfo := range m.streams {
-		infos[id] = confInfo{
-			Active:    strInfo.IsRunning(),
-			Uptime:    strInfo.Uptime().Seconds(),
-			UptimeStr: strInfo.Uptime().String(),
-		}
+		tp := strInfo.Throughput()
+		info := confInfo{
+			Active:          strInfo.IsRunning(),
+			Paused:          strInfo.IsPaused(),
+			Uptime:          strInfo.Uptime().Seconds(),
+			UptimeStr:       strInfo.Uptime().String(),
+			InputConnected:  strInfo.InputConnected(),
+			OutputConnected: strInfo.OutputConnected(),
+			ReceivedPerSec:  tp.ReceivedPerSecond,
+			DeliveredPerSec: tp.DeliveredPerSecond,
+			InFlight:        tp.InFlight,
+		}
+		if path, lastErr, exists := strInfo.LastError(); exists {
+			info.LastError = &lastErrInfo{Path: path, ComponentError: lastErr}
+		}
+		infos[id] = info
 	}
 	m.lock.Unlock()
 
@@ -148,14 +208,22 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	nodeSet := map[string]yaml.Node{}
+	if requestErr = yaml.Unmarshal(setBytes, &nodeSet); requestErr != nil {
+		return
+	}
+
 	if r.URL.Query().Get("chilled") != "true" {
-		nodeSet := map[string]yaml.Node{}
-		if requestErr = yaml.Unmarshal(setBytes, &nodeSet); requestErr != nil {
-			return
-		}
 		var lints []string
 		for k, n := range nodeSet {
-			for _, l := range lintStreamConfigNode(&n) {
+			// Templates are expanded into a copy of the node so that the set
+			// is persisted as it was written.
+			var tmplLints []docs.Lint
+			if tmplLints, requestErr = config.ExpandStreamTemplatesLinted(docs.NewLintContext(), &n); requestErr != nil {
+				requestErr = fmt.Errorf("stream '%v': %w", k, requestErr)
+				return
+			}
+			for _, l := range lintStreamConfigNode(tmplLints, &n) {
 				keyLint := fmt.Sprintf("stream '%v': %v", k, l)
 				lints = append(lints, keyLint)
 				m.manager.Logger().Debugf("Streams request linting error: %v\n", keyLint)
@@ -208,29 +276,43 @@ func (m *Type) HandleStreamsCRUD(w http.ResponseWriter, r *http.Request) {
 	// TODO: Replace with context
 	tmpTimeout := time.Second * 5
 
+	rawNode := func(id string) ([]byte, error) {
+		node := nodeSet[id]
+		return yaml.Marshal(&node)
+	}
+
+	ctx := r.Context()
 	for i, id := range toDelete {
 		go func(sid string, j int) {
-			errDelete[j] = m.Delete(sid, tmpTimeout)
+			errDelete[j] = m.deletePersisted(ctx, sid, tmpTimeout)
 			wg.Done()
 		}(id, i)
 	}
-	i := 0
+	updateIDs := make([]string, 0, len(toUpdate))
 	for id, conf := range toUpdate {
 		newConf := conf
 		go func(sid string, sconf *stream.Config, j int) {
-			errUpdate[j] = m.Update(sid, *sconf, tmpTimeout)
+			rawConf, err := rawNode(sid)
+			if err == nil {
+				err = m.updatePersisted(ctx, sid, *sconf, rawConf, tmpTimeout)
+			}
+			errUpdate[j] = err
 			wg.Done()
-		}(id, &newConf, i)
-		i++
+		}(id, &newConf, len(updateIDs))
+		updateIDs = append(updateIDs, id)
 	}
-	i = 0
+	createIDs := make([]string, 0, len(toCreate))
 	for id, conf := range toCreate {
 		newConf := conf
 		go func(sid string, sconf *stream.Config, j int) {
-			errCreate[j] = m.Create(sid, *sconf)
+			rawConf, err := rawNode(sid)
+			if err == nil {
+				err = m.createPersisted(ctx, sid, *sconf, rawConf, tmpTimeout)
+			}
+			errCreate[j] = err
 			wg.Done()
-		}(id, &newConf, i)
-		i++
+		}(id, &newConf, len(createIDs))
+		createIDs = append(createIDs, id)
 	}
 
 	wg.Wait()
@@ -283,30 +365,25 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	readConfig := func() (confOut stream.Config, lints []string, err error) {
-		var confBytes []byte
-		if confBytes, err = io.ReadAll(r.Body); err != nil {
+	readConfig := func() (confOut stream.Config, rawBytes []byte, lints []string, err error) {
+		if rawBytes, err = io.ReadAll(r.Body); err != nil {
+			return
+		}
+		var node yaml.Node
+		var tmplLints []docs.Lint
+		if confOut, node, tmplLints, err = parseStreamConfig(config.ReplaceEnvVariables(rawBytes)); err != nil {
 			return
 		}
-		confBytes = config.ReplaceEnvVariables(confBytes)
 
 		if r.URL.Query().Get("chilled") != "true" {
-			var node yaml.Node
-			if err = yaml.Unmarshal(confBytes, &node); err != nil {
-				return
-			}
-			lints = lintStreamConfigNode(&node)
+			lints = lintStreamConfigNode(tmplLints, &node)
 			for _, l := range lints {
 				m.manager.Logger().Infof("Stream '%v' config: %v\n", id, l)
 			}
 		}
-
-		confOut = stream.NewConfig()
-		err = yaml.Unmarshal(confBytes, &confOut)
 		return
 	}
-	patchConfig := func(confIn stream.Config) (confOut stream.Config, err error) {
-		var patchBytes []byte
+	patchConfig := func(confIn stream.Config) (confOut stream.Config, patchBytes []byte, err error) {
 		if patchBytes, err = io.ReadAll(r.Body); err != nil {
 			return
 		}
@@ -343,10 +420,11 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 	tmpTimeout := time.Second * 5
 
 	var conf stream.Config
+	var rawConf []byte
 	var lints []string
 	switch r.Method {
 	case "POST":
-		if conf, lints, requestErr = readConfig(); requestErr != nil {
+		if conf, rawConf, lints, requestErr = readConfig(); requestErr != nil {
 			return
 		}
 		if len(lints) > 0 {
@@ -359,7 +437,7 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write(errBytes)
 			return
 		}
-		serverErr = m.Create(id, conf)
+		serverErr = m.createPersisted(r.Context(), id, conf, rawConf, tmpTimeout)
 	case "GET":
 		var info *StreamStatus
 		if info, serverErr = m.Read(id); serverErr == nil {
@@ -368,11 +446,13 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			var bodyBytes []byte
 			if bodyBytes, serverErr = json.Marshal(struct {
 				Active    bool        `json:"active"`
+				Paused    bool        `json:"paused"`
 				Uptime    float64     `json:"uptime"`
 				UptimeStr string      `json:"uptime_str"`
 				Config    interface{} `json:"config"`
 			}{
 				Active:    info.IsRunning(),
+				Paused:    info.IsPaused(),
 				Uptime:    info.Uptime().Seconds(),
 				UptimeStr: info.Uptime().String(),
 				Config:    sanit,
@@ -384,7 +464,7 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write(bodyBytes)
 		}
 	case "PUT":
-		if conf, lints, requestErr = readConfig(); requestErr != nil {
+		if conf, rawConf, lints, requestErr = readConfig(); requestErr != nil {
 			return
 		}
 		if len(lints) > 0 {
@@ -397,16 +477,34 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write(errBytes)
 			return
 		}
-		serverErr = m.Update(id, conf, tmpTimeout)
+		serverErr = m.updatePersisted(r.Context(), id, conf, rawConf, tmpTimeout)
 	case "DELETE":
-		serverErr = m.Delete(id, tmpTimeout)
+		serverErr = m.deletePersisted(r.Context(), id, tmpTimeout)
 	case "PATCH":
 		var info *StreamStatus
 		if info, serverErr = m.Read(id); serverErr == nil {
-			if conf, requestErr = patchConfig(info.Config()); requestErr != nil {
+			var patchBytes []byte
+			if conf, patchBytes, requestErr = patchConfig(info.Config()); requestErr != nil {
 				return
 			}
-			serverErr = m.Update(id, conf, tmpTimeout)
+			if m.store == nil {
+				serverErr = m.Update(id, conf, tmpTimeout)
+			} else {
+				// The patch is applied to the raw config that was persisted so
+				// that environment variables remain unresolved within the
+				// store. Streams that were never persisted fall back to their
+				// running config.
+				prevRaw, exists := m.rawConf(StoreKindStream, id)
+				if !exists {
+					if prevRaw, serverErr = yaml.Marshal(info.Config()); serverErr != nil {
+						return
+					}
+				}
+				if rawConf, requestErr = patchRawStreamConfig(prevRaw, patchBytes, conf); requestErr != nil {
+					return
+				}
+				serverErr = m.updatePersisted(r.Context(), id, conf, rawConf, tmpTimeout)
+			}
 		}
 	default:
 		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
@@ -424,6 +522,74 @@ func (m *Type) HandleStreamCRUD(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// patchRawStreamConfig applies a patch to a raw stream config by merging the
+// fields of the patch into it. The result is rejected unless it produces the
+// same config as conf, which is the result of the patch applied to the running
+// config of the stream.
+func patchRawStreamConfig(rawConf, patch []byte, conf stream.Config) ([]byte, error) {
+	var rawNode, patchNode yaml.Node
+	if err := yaml.Unmarshal(rawConf, &rawNode); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(patch, &patchNode); err != nil {
+		return nil, err
+	}
+	mergeYAMLNodes(&rawNode, &patchNode)
+
+	patched, err := yaml.Marshal(&rawNode)
+	if err != nil {
+		return nil, err
+	}
+
+	patchedConf, _, _, err := parseStreamConfig(config.ReplaceEnvVariables(patched))
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patch to the persisted config: %w", err)
+	}
+	exp, err := conf.Sanitised()
+	if err != nil {
+		return nil, err
+	}
+	act, err := patchedConf.Sanitised()
+	if err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(exp, act) {
+		return nil, errors.New("the patch could not be applied to the persisted config, replace the stream with PUT instead")
+	}
+	return patched, nil
+}
+
+// mergeYAMLNodes merges the fields of a patch into a YAML node, where object
+// fields that exist within both are merged recursively and all other values of
+// the patch replace those of the node.
+func mergeYAMLNodes(node, patch *yaml.Node) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if patch.Kind == yaml.DocumentNode && len(patch.Content) > 0 {
+		patch = patch.Content[0]
+	}
+	if node.Kind != yaml.MappingNode || patch.Kind != yaml.MappingNode {
+		*node = *patch
+		return
+	}
+
+	for i := 0; i < len(patch.Content)-1; i += 2 {
+		key, value := patch.Content[i], patch.Content[i+1]
+		merged := false
+		for j := 0; j < len(node.Content)-1; j += 2 {
+			if node.Content[j].Value == key.Value {
+				mergeYAMLNodes(node.Content[j+1], value)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			node.Content = append(node.Content, key, value)
+		}
+	}
+}
+
 // HandleResourceCRUD is an http.HandleFunc for performing CRUD operations on
 // resource components.
 func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
@@ -457,63 +623,21 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	var storeFn func(*yaml.Node)
-
 	docType := docs.Type(mux.Vars(r)["type"])
-	switch docType {
-	case docs.TypeCache:
-		storeFn = func(n *yaml.Node) {
-			cacheConf := cache.NewConfig()
-			if requestErr = n.Decode(&cacheConf); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreCache(ctx, id, cacheConf)
-		}
-	case docs.TypeInput:
-		storeFn = func(n *yaml.Node) {
-			inputConf := input.NewConfig()
-			if requestErr = n.Decode(&inputConf); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreInput(ctx, id, inputConf)
-		}
-	case docs.TypeOutput:
-		storeFn = func(n *yaml.Node) {
-			outputConf := output.NewConfig()
-			if requestErr = n.Decode(&outputConf); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreOutput(ctx, id, outputConf)
-		}
-	case docs.TypeProcessor:
-		storeFn = func(n *yaml.Node) {
-			procConf := processor.NewConfig()
-			if requestErr = n.Decode(&procConf); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreProcessor(ctx, id, procConf)
-		}
-	case docs.TypeRateLimit:
-		storeFn = func(n *yaml.Node) {
-			rlConf := ratelimit.NewConfig()
-			if requestErr = n.Decode(&rlConf); requestErr != nil {
-				return
-			}
-			serverErr = m.manager.StoreRateLimit(ctx, id, rlConf)
-		}
-	default:
+	decodeFn := m.resourceDecodeFn(ctx, docType, id)
+	if decodeFn == nil {
 		http.Error(w, "Var `type` must be set to one of `cache`, `input`, `output`, `processor` or `rate_limit`", http.StatusBadRequest)
 		return
 	}
 
 	var confNode *yaml.Node
+	var rawConf []byte
 	var lints []string
 	{
-		var confBytes []byte
-		if confBytes, requestErr = io.ReadAll(r.Body); requestErr != nil {
+		if rawConf, requestErr = io.ReadAll(r.Body); requestErr != nil {
 			return
 		}
-		confBytes = config.ReplaceEnvVariables(confBytes)
+		confBytes := config.ReplaceEnvVariables(rawConf)
 
 		var node yaml.Node
 		if requestErr = yaml.Unmarshal(confBytes, &node); requestErr != nil {
@@ -539,7 +663,83 @@ func (m *Type) HandleResourceCRUD(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	storeFn(confNode)
+	var storeFn func() error
+	if storeFn, requestErr = decodeFn(confNode); requestErr != nil {
+		return
+	}
+
+	// The config is persisted before the resource is stored so that a
+	// resource is never live without being persisted, and the store is
+	// restored when the resource fails to be stored.
+	kind := StoreKind(docType)
+	prevRaw, hadRaw := m.rawConf(kind, id)
+	if serverErr = m.persist(ctx, kind, id, rawConf); serverErr != nil {
+		return
+	}
+	if serverErr = storeFn(); serverErr != nil {
+		if rErr := m.restorePersisted(ctx, kind, id, prevRaw, hadRaw); rErr != nil {
+			m.manager.Logger().Errorf("Failed to restore persisted %v '%v' after failing to store it: %v\n", kind, id, rErr)
+		}
+	}
+}
+
+// resourceDecodeFn returns a closure that decodes a resource config of a given
+// type and id, and returns a closure that stores the decoded resource, or nil
+// if the type is not supported.
+func (m *Type) resourceDecodeFn(ctx context.Context, docType docs.Type, id string) func(n *yaml.Node) (func() error, error) {
+	switch docType {
+	case docs.TypeCache:
+		return func(n *yaml.Node) (func() error, error) {
+			cacheConf := cache.NewConfig()
+			if err := n.Decode(&cacheConf); err != nil {
+				return nil, err
+			}
+			return func() error {
+				return m.manager.StoreCache(ctx, id, cacheConf)
+			}, nil
+		}
+	case docs.TypeInput:
+		return func(n *yaml.Node) (func() error, error) {
+			inputConf := input.NewConfig()
+			if err := n.Decode(&inputConf); err != nil {
+				return nil, err
+			}
+			return func() error {
+				return m.manager.StoreInput(ctx, id, inputConf)
+			}, nil
+		}
+	case docs.TypeOutput:
+		return func(n *yaml.Node) (func() error, error) {
+			outputConf := output.NewConfig()
+			if err := n.Decode(&outputConf); err != nil {
+				return nil, err
+			}
+			return func() error {
+				return m.manager.StoreOutput(ctx, id, outputConf)
+			}, nil
+		}
+	case docs.TypeProcessor:
+		return func(n *yaml.Node) (func() error, error) {
+			procConf := processor.NewConfig()
+			if err := n.Decode(&procConf); err != nil {
+				return nil, err
+			}
+			return func() error {
+				return m.manager.StoreProcessor(ctx, id, procConf)
+			}, nil
+		}
+	case docs.TypeRateLimit:
+		return func(n *yaml.Node) (func() error, error) {
+			rlConf := ratelimit.NewConfig()
+			if err := n.Decode(&rlConf); err != nil {
+				return nil, err
+			}
+			return func() error {
+				return m.manager.StoreRateLimit(ctx, id, rlConf)
+			}, nil
+		}
+	}
+	return nil
 }
 
 // HandleStreamStats is an http.HandleFunc for obtaining metrics for a stream.
@@ -608,24 +808,149 @@ func (m *Type) HandleStreamStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleStreamStatus is an http.HandleFunc for obtaining the health of a
+// stream.
+func (m *Type) HandleStreamStatus(w http.ResponseWriter, r *http.Request) {
+	var serverErr, requestErr error
+	defer func() {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		if serverErr != nil {
+			m.manager.Logger().Errorf("Stream status Error: %v\n", serverErr)
+			http.Error(w, fmt.Sprintf("Error: %v", serverErr), http.StatusBadGateway)
+			return
+		}
+		if requestErr != nil {
+			m.manager.Logger().Debugf("Stream request status Error: %v\n", requestErr)
+			http.Error(w, fmt.Sprintf("Error: %v", requestErr), http.StatusBadRequest)
+			return
+		}
+	}()
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "Var `id` must be set", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		var info *StreamStatus
+		if info, serverErr = m.Read(id); serverErr == nil {
+			tp := info.Throughput()
+			jBytes, err := json.Marshal(struct {
+				Active          bool                      `json:"active"`
+				Paused          bool                      `json:"paused"`
+				InputConnected  bool                      `json:"input_connected"`
+				OutputConnected bool                      `json:"output_connected"`
+				ReceivedPerSec  float64                   `json:"received_per_second"`
+				DeliveredPerSec float64                   `json:"delivered_per_second"`
+				InFlight        int64                     `json:"in_flight"`
+				Errors          map[string]ComponentError `json:"errors"`
+			}{
+				Active:          info.IsRunning(),
+				Paused:          info.IsPaused(),
+				InputConnected:  info.InputConnected(),
+				OutputConnected: info.OutputConnected(),
+				ReceivedPerSec:  tp.ReceivedPerSecond,
+				DeliveredPerSec: tp.DeliveredPerSecond,
+				InFlight:        tp.InFlight,
+				Errors:          info.Errors(),
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(jBytes)
+		}
+	default:
+		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
+	}
+	if serverErr == ErrStreamDoesNotExist {
+		serverErr = nil
+		http.Error(w, "Stream not found", http.StatusNotFound)
+		return
+	}
+}
+
+// HandleStreamPause is an http.HandleFunc for pausing a stream.
+func (m *Type) HandleStreamPause(w http.ResponseWriter, r *http.Request) {
+	m.handleStreamPauseToggle(w, r, m.Pause)
+}
+
+// HandleStreamResume is an http.HandleFunc for resuming a paused stream.
+func (m *Type) HandleStreamResume(w http.ResponseWriter, r *http.Request) {
+	m.handleStreamPauseToggle(w, r, m.Resume)
+}
+
+func (m *Type) handleStreamPauseToggle(w http.ResponseWriter, r *http.Request, fn func(id string) error) {
+	var serverErr, requestErr error
+	defer func() {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		if serverErr != nil {
+			m.manager.Logger().Errorf("Stream pause Error: %v\n", serverErr)
+			http.Error(w, fmt.Sprintf("Error: %v", serverErr), http.StatusBadGateway)
+			return
+		}
+		if requestErr != nil {
+			m.manager.Logger().Debugf("Stream request pause Error: %v\n", requestErr)
+			http.Error(w, fmt.Sprintf("Error: %v", requestErr), http.StatusBadRequest)
+			return
+		}
+	}()
+
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		http.Error(w, "Var `id` must be set", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case "POST":
+		serverErr = fn(id)
+	default:
+		requestErr = fmt.Errorf("verb not supported: %v", r.Method)
+	}
+	if serverErr == ErrStreamDoesNotExist {
+		serverErr = nil
+		http.Error(w, "Stream not found", http.StatusNotFound)
+		return
+	}
+}
+
 // HandleStreamReady is an http.HandleFunc for providing a ready check across
 // all streams.
 func (m *Type) HandleStreamReady(w http.ResponseWriter, r *http.Request) {
-	var notReady []string
+	var notReady, paused []string
 
 	m.lock.Lock()
 	for k, v := range m.streams {
-		if !v.IsReady() {
+		if v.IsPaused() {
+			paused = append(paused, k)
+		} else if !v.IsReady() {
 			notReady = append(notReady, k)
 		}
 	}
 	m.lock.Unlock()
 
-	if len(notReady) == 0 {
+	if len(notReady) == 0 && len(paused) == 0 {
 		_, _ = w.Write([]byte("OK"))
 		return
 	}
 
+	sort.Strings(notReady)
+	sort.Strings(paused)
+
 	w.WriteHeader(http.StatusServiceUnavailable)
-	fmt.Fprintf(w, "streams %v are not connected\n", strings.Join(notReady, ", "))
+	if len(notReady) > 0 {
+		fmt.Fprintf(w, "streams %v are not connected\n", strings.Join(notReady, ", "))
+	}
+	if len(paused) > 0 {
+		fmt.Fprintf(w, "streams %v are paused\n", strings.Join(paused, ", "))
+	}
 }
@@ -886,3 +886,184 @@ file:
 	require.NoError(t, err)
 	assert.Equal(t, `{"id":"second","content":"hello world 2"}`, string(file2Bytes))
 }
+
+func TestTypeAPIPauseResume(t *testing.T) {
+	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	tChan := make(chan message.Transaction)
+	bmgr.SetPipe("feed_in", tChan)
+
+	mgr := manager.New(bmgr)
+	r := router(mgr)
+	r.HandleFunc("/streams/{id}/pause", mgr.HandleStreamPause)
+	r.HandleFunc("/streams/{id}/resume", mgr.HandleStreamResume)
+
+	request := genYAMLRequest("POST", "/streams/foo", `
+input:
+  inproc: feed_in
+output:
+  drop: {}
+`)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	sendMsg := func(content string) chan error {
+		t.Helper()
+		resChan := make(chan error)
+		select {
+		case tChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte(content)}), resChan):
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+		return resChan
+	}
+
+	select {
+	case err := <-sendMsg("first"):
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+
+	request = genRequest("POST", "/streams/foo/pause", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	request = genRequest("GET", "/streams/foo", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Contains(t, response.Body.String(), `"paused":true`)
+
+	response = httptest.NewRecorder()
+	mgr.HandleStreamReady(response, genRequest("GET", "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, "streams foo are paused\n", response.Body.String())
+
+	// The input may consume a message, but it is not delivered whilst paused.
+	resChan := sendMsg("second")
+	select {
+	case <-resChan:
+		t.Fatal("message delivered whilst paused")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	request = genRequest("POST", "/streams/foo/resume", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	select {
+	case err := <-resChan:
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out")
+	}
+
+	status, err := mgr.Read("foo")
+	require.NoError(t, err)
+	assert.False(t, status.IsPaused())
+
+	request = genRequest("POST", "/streams/bar/pause", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	request = genRequest("GET", "/streams/foo/pause", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Updating a paused stream keeps it paused.
+	require.NoError(t, mgr.Pause("foo"))
+
+	newConf := status.Config()
+	newConf.Output.Type = "stdout"
+	require.NoError(t, mgr.Update("foo", newConf, time.Second*5))
+
+	status, err = mgr.Read("foo")
+	require.NoError(t, err)
+	assert.True(t, status.IsPaused())
+	assert.Equal(t, "stdout", status.Config().Output.Type)
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
+
+func TestTypeAPIStreamStatus(t *testing.T) {
+	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	tChan := make(chan message.Transaction)
+	bmgr.SetPipe("feed_in", tChan)
+
+	mgr := manager.New(bmgr)
+	r := router(mgr)
+	r.HandleFunc("/streams/{id}/status", mgr.HandleStreamStatus)
+
+	request := genYAMLRequest("POST", "/streams/foo", `
+input:
+  inproc: feed_in
+output:
+  drop: {}
+`)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	for i := 0; i < 3; i++ {
+		resChan := make(chan error)
+		select {
+		case tChan <- message.NewTransaction(message.QuickBatch([][]byte{[]byte("hello")}), resChan):
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+		select {
+		case err := <-resChan:
+			require.NoError(t, err)
+		case <-time.After(time.Second * 5):
+			t.Fatal("timed out")
+		}
+	}
+
+	status, err := mgr.Read("foo")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), status.Throughput().InFlight)
+
+	request = genRequest("GET", "/streams/foo/status", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var statusObj map[string]interface{}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &statusObj))
+	assert.Equal(t, true, statusObj["active"])
+	assert.Equal(t, false, statusObj["paused"])
+	assert.Equal(t, true, statusObj["input_connected"])
+	assert.Equal(t, true, statusObj["output_connected"])
+	assert.Equal(t, float64(0), statusObj["in_flight"])
+	assert.Contains(t, statusObj, "received_per_second")
+	assert.Contains(t, statusObj, "delivered_per_second")
+	assert.Equal(t, map[string]interface{}{}, statusObj["errors"])
+
+	request = genRequest("GET", "/streams", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var listObj map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listObj))
+	assert.Equal(t, true, listObj["foo"]["input_connected"])
+	assert.Equal(t, true, listObj["foo"]["output_connected"])
+	assert.Equal(t, float64(0), listObj["foo"]["in_flight"])
+	assert.NotContains(t, listObj["foo"], "last_error")
+
+	request = genRequest("GET", "/streams/bar/status", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
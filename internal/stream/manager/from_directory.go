@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/stream"
 )
 
@@ -58,7 +59,7 @@ func LoadStreamConfigsFromDirectory(replaceEnvVars bool, dir string) (map[string
 		}
 
 		conf := config.New()
-		if _, readerr := config.ReadFileLinted(path, false, &conf); readerr != nil {
+		if _, readerr := config.ReadFileLinted(path, docs.NewLintContext(), &conf); readerr != nil {
 			// TODO: Read and report linting errors.
 			return readerr
 		}
@@ -0,0 +1,211 @@
+package manager
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+)
+
+// ComponentError describes the last error logged by a component of a stream.
+type ComponentError struct {
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// errorRecorder keeps track of the last error logged by each component of a
+// stream, keyed by the path of the component.
+type errorRecorder struct {
+	mut    sync.Mutex
+	errors map[string]ComponentError
+}
+
+func newErrorRecorder() *errorRecorder {
+	return &errorRecorder{
+		errors: map[string]ComponentError{},
+	}
+}
+
+func (e *errorRecorder) record(path, message string) {
+	e.mut.Lock()
+	e.errors[path] = ComponentError{
+		Message: message,
+		Time:    time.Now(),
+	}
+	e.mut.Unlock()
+}
+
+// Errors returns the last error logged by each component, keyed by path.
+func (e *errorRecorder) Errors() map[string]ComponentError {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	errs := make(map[string]ComponentError, len(e.errors))
+	for k, v := range e.errors {
+		errs[k] = v
+	}
+	return errs
+}
+
+// Last returns the most recent error logged by any component, and the path of
+// that component. Returns false if no errors have been logged.
+func (e *errorRecorder) Last() (string, ComponentError, bool) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	var lastPath string
+	var last ComponentError
+	for k, v := range e.errors {
+		if v.Time.After(last.Time) {
+			lastPath, last = k, v
+		}
+	}
+	return lastPath, last, lastPath != ""
+}
+
+//------------------------------------------------------------------------------
+
+// errorRecordingLogger wraps a logger in order to record the errors logged by
+// each component of a stream, which is identified by the path field that the
+// logger is branched with.
+type errorRecordingLogger struct {
+	log.Modular
+
+	path     string
+	recorder *errorRecorder
+}
+
+func newErrorRecordingLogger(l log.Modular, recorder *errorRecorder) *errorRecordingLogger {
+	return &errorRecordingLogger{
+		Modular:  l,
+		path:     "root",
+		recorder: recorder,
+	}
+}
+
+func (l *errorRecordingLogger) WithFields(fields map[string]string) log.Modular {
+	path := l.path
+	if p, exists := fields["path"]; exists {
+		path = p
+	}
+	return &errorRecordingLogger{
+		Modular:  l.Modular.WithFields(fields),
+		path:     path,
+		recorder: l.recorder,
+	}
+}
+
+func (l *errorRecordingLogger) With(keyValues ...interface{}) log.Modular {
+	path := l.path
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		if k, ok := keyValues[i].(string); ok && k == "path" {
+			path = fmt.Sprintf("%v", keyValues[i+1])
+		}
+	}
+	return &errorRecordingLogger{
+		Modular:  l.Modular.With(keyValues...),
+		path:     path,
+		recorder: l.recorder,
+	}
+}
+
+func (l *errorRecordingLogger) Errorf(format string, v ...interface{}) {
+	l.recorder.record(l.path, fmt.Sprintf(format, v...))
+	l.Modular.Errorf(format, v...)
+}
+
+func (l *errorRecordingLogger) Errorln(message string) {
+	l.recorder.record(l.path, message)
+	l.Modular.Errorln(message)
+}
+
+//------------------------------------------------------------------------------
+
+// Throughput describes the rate at which messages pass through a stream.
+type Throughput struct {
+	ReceivedPerSecond  float64
+	DeliveredPerSecond float64
+	InFlight           int64
+}
+
+// throughputSampleInterval is the period between samples of the counts of a
+// stream from which throughput rates are calculated.
+var throughputSampleInterval = time.Second
+
+// throughputSampler calculates the per second rates of messages received and
+// delivered by a stream from the difference between samples of its counts.
+// Samples are taken at a fixed interval in the background so that the rates
+// are unaffected by how often, and by how many callers, they are requested.
+type throughputSampler struct {
+	countsFn func() stream.Counts
+
+	mut        sync.Mutex
+	lastCounts stream.Counts
+	lastTime   time.Time
+
+	receivedRate  float64
+	deliveredRate float64
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+func newThroughputSampler(countsFn func() stream.Counts) *throughputSampler {
+	return &throughputSampler{
+		countsFn:   countsFn,
+		lastCounts: countsFn(),
+		lastTime:   time.Now(),
+		closeChan:  make(chan struct{}),
+	}
+}
+
+// loop samples the counts of the stream at each interval until the sampler is
+// closed.
+func (s *throughputSampler) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.record(s.countsFn(), now)
+		case <-s.closeChan:
+			return
+		}
+	}
+}
+
+func (s *throughputSampler) record(counts stream.Counts, now time.Time) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	elapsed := now.Sub(s.lastTime).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	s.receivedRate = float64(counts.Received-s.lastCounts.Received) / elapsed
+	s.deliveredRate = float64(counts.Delivered-s.lastCounts.Delivered) / elapsed
+	s.lastCounts = counts
+	s.lastTime = now
+}
+
+// throughput returns the rates calculated from the latest sample along with
+// the current number of messages in-flight.
+func (s *throughputSampler) throughput() Throughput {
+	inFlight := s.countsFn().InFlight
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return Throughput{
+		ReceivedPerSecond:  s.receivedRate,
+		DeliveredPerSecond: s.deliveredRate,
+		InFlight:           inFlight,
+	}
+}
+
+func (s *throughputSampler) close() {
+	s.closeOnce.Do(func() {
+		close(s.closeChan)
+	})
+}
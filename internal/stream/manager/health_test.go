@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+)
+
+func TestErrorRecordingLogger(t *testing.T) {
+	recorder := newErrorRecorder()
+	logger := newErrorRecordingLogger(log.Noop(), recorder)
+
+	_, _, exists := recorder.Last()
+	assert.False(t, exists)
+
+	logger.Errorln("root error")
+	logger.WithFields(map[string]string{"path": "root.input"}).Errorf("input error: %v", "nope")
+	logger.WithFields(map[string]string{"path": "root.output"}).With("label", "foo").Warnf("not recorded")
+
+	outLogger := logger.With("path", "root.output")
+	outLogger.Errorf("first output error")
+	outLogger.Errorf("second output error")
+
+	errs := recorder.Errors()
+	require.Len(t, errs, 3)
+	assert.Equal(t, "root error", errs["root"].Message)
+	assert.Equal(t, "input error: nope", errs["root.input"].Message)
+	assert.Equal(t, "second output error", errs["root.output"].Message)
+
+	path, lastErr, exists := recorder.Last()
+	require.True(t, exists)
+	assert.Equal(t, "root.output", path)
+	assert.Equal(t, "second output error", lastErr.Message)
+}
+
+func TestThroughputSampler(t *testing.T) {
+	counts := stream.Counts{Received: 10, Delivered: 5, InFlight: 3}
+	sampler := newThroughputSampler(func() stream.Counts {
+		return counts
+	})
+
+	assert.Equal(t, Throughput{InFlight: 3}, sampler.throughput())
+
+	counts = stream.Counts{Received: 20, Delivered: 10, InFlight: 2}
+	sampler.record(counts, sampler.lastTime.Add(time.Second*2))
+
+	// Concurrent callers observe the same rates regardless of how often the
+	// throughput is requested.
+	for i := 0; i < 3; i++ {
+		tp := sampler.throughput()
+		assert.InDelta(t, 5, tp.ReceivedPerSecond, 0.1)
+		assert.InDelta(t, 2.5, tp.DeliveredPerSecond, 0.1)
+		assert.Equal(t, int64(2), tp.InFlight)
+	}
+
+	counts = stream.Counts{Received: 30, Delivered: 30}
+	sampler.record(counts, sampler.lastTime.Add(time.Second))
+	tp := sampler.throughput()
+	assert.InDelta(t, 10, tp.ReceivedPerSecond, 0.1)
+	assert.InDelta(t, 20, tp.DeliveredPerSecond, 0.1)
+	assert.Equal(t, int64(0), tp.InFlight)
+}
+
+func TestThroughputSamplerLoop(t *testing.T) {
+	var mut sync.Mutex
+	counts := stream.Counts{}
+	sampler := newThroughputSampler(func() stream.Counts {
+		mut.Lock()
+		defer mut.Unlock()
+		return counts
+	})
+	go sampler.loop(time.Millisecond * 10)
+	defer sampler.close()
+
+	mut.Lock()
+	counts = stream.Counts{Received: 100, Delivered: 100}
+	mut.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return sampler.throughput().ReceivedPerSecond > 0
+	}, time.Second, time.Millisecond*10)
+}
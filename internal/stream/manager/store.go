@@ -0,0 +1,381 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/benthosdev/benthos/v4/internal/component"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+)
+
+// StoreKind describes the kind of a config persisted within a Store, which is
+// either a stream or the type of a resource (cache, input, etc).
+type StoreKind string
+
+// StoreKindStream is the kind of persisted stream configs.
+const StoreKindStream StoreKind = "stream"
+
+// Store persists the configs of streams and resources that are created via the
+// streams mode HTTP API so that they can be reloaded after a restart.
+type Store interface {
+	// Set persists the raw config of a stream or resource, replacing any
+	// existing config of the same kind and id.
+	Set(ctx context.Context, kind StoreKind, id string, conf []byte) error
+
+	// Delete removes a persisted config, returns nil if it does not exist.
+	Delete(ctx context.Context, kind StoreKind, id string) error
+
+	// Walk calls a closure for each persisted config, in no particular order.
+	Walk(ctx context.Context, fn func(kind StoreKind, id string, conf []byte) error) error
+}
+
+//------------------------------------------------------------------------------
+
+// DirectoryStore is a Store that persists each config as a YAML file within a
+// directory, at the path <dir>/<kind>/<id>.yaml.
+type DirectoryStore struct {
+	dir string
+	mut sync.Mutex
+}
+
+// NewDirectoryStore returns a Store that persists configs within a directory.
+// The directory, and a subdirectory for each kind, are created when the first
+// config of that kind is persisted.
+func NewDirectoryStore(dir string) *DirectoryStore {
+	return &DirectoryStore{dir: filepath.Clean(dir)}
+}
+
+func (d *DirectoryStore) path(kind StoreKind, id string) string {
+	return filepath.Join(d.dir, url.PathEscape(string(kind)), url.PathEscape(id)+".yaml")
+}
+
+// Set persists the raw config of a stream or resource.
+func (d *DirectoryStore) Set(ctx context.Context, kind StoreKind, id string, conf []byte) error {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	path := d.path(kind, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a failed write never leaves a
+	// partial config behind.
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, conf, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Delete removes a persisted config.
+func (d *DirectoryStore) Delete(ctx context.Context, kind StoreKind, id string) error {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	if err := os.Remove(d.path(kind, id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Walk calls a closure for each persisted config.
+func (d *DirectoryStore) Walk(ctx context.Context, fn func(kind StoreKind, id string, conf []byte) error) error {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	kindDirs, err := os.ReadDir(d.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, kindDir := range kindDirs {
+		if !kindDir.IsDir() {
+			continue
+		}
+		kind, err := url.PathUnescape(kindDir.Name())
+		if err != nil {
+			return fmt.Errorf("unexpected directory %v: %w", kindDir.Name(), err)
+		}
+
+		files, err := os.ReadDir(filepath.Join(d.dir, kindDir.Name()))
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if f.IsDir() || !strings.HasSuffix(f.Name(), ".yaml") {
+				continue
+			}
+			id, err := url.PathUnescape(strings.TrimSuffix(f.Name(), ".yaml"))
+			if err != nil {
+				return fmt.Errorf("unexpected file %v: %w", f.Name(), err)
+			}
+			conf, err := os.ReadFile(filepath.Join(d.dir, kindDir.Name(), f.Name()))
+			if err != nil {
+				return err
+			}
+			if err := fn(StoreKind(kind), id, conf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// CacheManager is a component manager that provides access to cache resources.
+type CacheManager interface {
+	AccessCache(ctx context.Context, name string, fn func(cache.V1)) error
+}
+
+// CacheStore is a Store that persists configs within a cache resource. Since
+// caches are unable to list their keys an index of all persisted configs is
+// stored alongside them.
+//
+// Modifications of the index are serialised across all instances sharing the
+// cache with a lock key that is acquired with the Add method of the cache, and
+// therefore the cache must implement Add atomically (as the memory, redis and
+// memcached caches do) in order for multiple instances to write concurrently.
+// The lock expires after a period of time in case an instance fails to release
+// it, and holds a token unique to each acquisition so that an instance never
+// releases a lock that has since been acquired by another.
+type CacheStore struct {
+	mgr       CacheManager
+	name      string
+	keyPrefix string
+	mut       sync.Mutex
+}
+
+// NewCacheStore returns a Store that persists configs within a cache resource
+// of a manager, where each key is prefixed with keyPrefix.
+func NewCacheStore(mgr CacheManager, name, keyPrefix string) *CacheStore {
+	return &CacheStore{
+		mgr:       mgr,
+		name:      name,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (c *CacheStore) indexKey() string {
+	return c.keyPrefix + "index"
+}
+
+func (c *CacheStore) indexLockKey() string {
+	return c.keyPrefix + "index_lock"
+}
+
+func (c *CacheStore) confKey(entry string) string {
+	return c.keyPrefix + "config/" + entry
+}
+
+func cacheStoreEntry(kind StoreKind, id string) string {
+	return url.PathEscape(string(kind)) + "/" + url.PathEscape(id)
+}
+
+func (c *CacheStore) access(ctx context.Context, fn func(cache.V1) error) error {
+	var cErr error
+	if err := c.mgr.AccessCache(ctx, c.name, func(ca cache.V1) {
+		cErr = fn(ca)
+	}); err != nil {
+		return err
+	}
+	return cErr
+}
+
+var (
+	cacheStoreLockTTL   = time.Second * 30
+	cacheStoreLockRetry = time.Millisecond * 50
+)
+
+// lockIndex acquires the index lock, blocking until it is either acquired or
+// the context is cancelled. The returned func releases the lock if it is still
+// held with the same token.
+func (c *CacheStore) lockIndex(ctx context.Context, ca cache.V1) (func(), error) {
+	u4, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	token := []byte(u4.String())
+
+	ttl := cacheStoreLockTTL
+	for {
+		err := ca.Add(ctx, c.indexLockKey(), token, &ttl)
+		if err == nil {
+			return func() {
+				if current, err := ca.Get(context.Background(), c.indexLockKey()); err == nil && string(current) == string(token) {
+					_ = ca.Delete(context.Background(), c.indexLockKey())
+				}
+			}, nil
+		}
+		if !errors.Is(err, component.ErrKeyAlreadyExists) {
+			return nil, fmt.Errorf("failed to lock store index: %w", err)
+		}
+		select {
+		case <-time.After(cacheStoreLockRetry):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *CacheStore) readIndex(ctx context.Context, ca cache.V1) ([]string, error) {
+	indexBytes, err := ca.Get(ctx, c.indexKey())
+	if errors.Is(err, component.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var index []string
+	if err := json.Unmarshal(indexBytes, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse store index: %w", err)
+	}
+	return index, nil
+}
+
+func (c *CacheStore) writeIndex(ctx context.Context, ca cache.V1, index []string) error {
+	sort.Strings(index)
+	indexBytes, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return ca.Set(ctx, c.indexKey(), indexBytes, nil)
+}
+
+// Set persists the raw config of a stream or resource.
+func (c *CacheStore) Set(ctx context.Context, kind StoreKind, id string, conf []byte) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	entry := cacheStoreEntry(kind, id)
+	return c.access(ctx, func(ca cache.V1) error {
+		if err := ca.Set(ctx, c.confKey(entry), conf, nil); err != nil {
+			return err
+		}
+		unlock, err := c.lockIndex(ctx, ca)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		index, err := c.readIndex(ctx, ca)
+		if err != nil {
+			return err
+		}
+		for _, e := range index {
+			if e == entry {
+				return nil
+			}
+		}
+		return c.writeIndex(ctx, ca, append(index, entry))
+	})
+}
+
+// Delete removes a persisted config.
+func (c *CacheStore) Delete(ctx context.Context, kind StoreKind, id string) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	entry := cacheStoreEntry(kind, id)
+	return c.access(ctx, func(ca cache.V1) error {
+		unlock, err := c.lockIndex(ctx, ca)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		index, err := c.readIndex(ctx, ca)
+		if err != nil {
+			return err
+		}
+		newIndex := make([]string, 0, len(index))
+		for _, e := range index {
+			if e != entry {
+				newIndex = append(newIndex, e)
+			}
+		}
+		if len(newIndex) != len(index) {
+			if err := c.writeIndex(ctx, ca, newIndex); err != nil {
+				return err
+			}
+		}
+		if err := ca.Delete(ctx, c.confKey(entry)); err != nil && !errors.Is(err, component.ErrKeyNotFound) {
+			return err
+		}
+		return nil
+	})
+}
+
+// Walk calls a closure for each persisted config.
+func (c *CacheStore) Walk(ctx context.Context, fn func(kind StoreKind, id string, conf []byte) error) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	type storeItem struct {
+		kind StoreKind
+		id   string
+		conf []byte
+	}
+	var items []storeItem
+	if err := c.access(ctx, func(ca cache.V1) error {
+		index, err := c.readIndex(ctx, ca)
+		if err != nil {
+			return err
+		}
+		for _, entry := range index {
+			conf, err := ca.Get(ctx, c.confKey(entry))
+			if errors.Is(err, component.ErrKeyNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			kind, id, err := parseCacheStoreEntry(entry)
+			if err != nil {
+				return err
+			}
+			items = append(items, storeItem{kind: kind, id: id, conf: conf})
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// The closure is called outside of the cache access as it may itself
+	// require access to resources.
+	for _, item := range items {
+		if err := fn(item.kind, item.id, item.conf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseCacheStoreEntry(entry string) (kind StoreKind, id string, err error) {
+	i := strings.Index(entry, "/")
+	if i < 0 {
+		return "", "", fmt.Errorf("malformed store index entry: %v", entry)
+	}
+	var kindStr string
+	if kindStr, err = url.PathUnescape(entry[:i]); err != nil {
+		return
+	}
+	if id, err = url.PathUnescape(entry[i+1:]); err != nil {
+		return
+	}
+	return StoreKind(kindStr), id, nil
+}
@@ -0,0 +1,355 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/benthosdev/benthos/v4/internal/bundle/mock"
+	"github.com/benthosdev/benthos/v4/internal/component/cache"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	bmanager "github.com/benthosdev/benthos/v4/internal/manager"
+	mmock "github.com/benthosdev/benthos/v4/internal/manager/mock"
+	"github.com/benthosdev/benthos/v4/internal/stream"
+	"github.com/benthosdev/benthos/v4/internal/stream/manager"
+)
+
+func storeContents(t *testing.T, s manager.Store) map[string]string {
+	t.Helper()
+
+	contents := map[string]string{}
+	require.NoError(t, s.Walk(context.Background(), func(kind manager.StoreKind, id string, conf []byte) error {
+		contents[string(kind)+":"+id] = string(conf)
+		return nil
+	}))
+	return contents
+}
+
+func testStore(t *testing.T, s manager.Store) {
+	t.Helper()
+
+	ctx := context.Background()
+	assert.Equal(t, map[string]string{}, storeContents(t, s))
+
+	require.NoError(t, s.Set(ctx, manager.StoreKindStream, "foo", []byte("foo: 1")))
+	require.NoError(t, s.Set(ctx, manager.StoreKindStream, "bar/baz", []byte("bar: 1")))
+	require.NoError(t, s.Set(ctx, "cache", "foo", []byte("cache: 1")))
+	assert.Equal(t, map[string]string{
+		"stream:foo":     "foo: 1",
+		"stream:bar/baz": "bar: 1",
+		"cache:foo":      "cache: 1",
+	}, storeContents(t, s))
+
+	require.NoError(t, s.Set(ctx, manager.StoreKindStream, "foo", []byte("foo: 2")))
+	require.NoError(t, s.Delete(ctx, manager.StoreKindStream, "bar/baz"))
+	require.NoError(t, s.Delete(ctx, manager.StoreKindStream, "does not exist"))
+	assert.Equal(t, map[string]string{
+		"stream:foo": "foo: 2",
+		"cache:foo":  "cache: 1",
+	}, storeContents(t, s))
+}
+
+func TestDirectoryStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+	testStore(t, manager.NewDirectoryStore(dir))
+
+	confBytes, err := os.ReadFile(filepath.Join(dir, "stream", "foo.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo: 2", string(confBytes))
+}
+
+func TestCacheStore(t *testing.T) {
+	mgr := mmock.NewManager()
+	mgr.Caches["foocache"] = map[string]mmock.CacheItem{}
+
+	testStore(t, manager.NewCacheStore(mgr, "foocache", "prefix_"))
+
+	assert.Contains(t, mgr.Caches["foocache"], "prefix_index")
+	assert.Contains(t, mgr.Caches["foocache"], "prefix_config/stream/foo")
+	assert.NotContains(t, mgr.Caches["foocache"], "prefix_index_lock")
+
+	// Another instance holding the index lock blocks modifications.
+	mgr.Caches["foocache"]["prefix_index_lock"] = mmock.CacheItem{Value: "locked"}
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer done()
+	err := manager.NewCacheStore(mgr, "foocache", "prefix_").Set(ctx, manager.StoreKindStream, "bar", []byte("bar: 1"))
+	require.Error(t, err)
+	assert.NotContains(t, storeContents(t, manager.NewCacheStore(mgr, "foocache", "prefix_")), "stream:bar")
+	delete(mgr.Caches["foocache"], "prefix_index_lock")
+
+	err = manager.NewCacheStore(mgr, "nope", "").Set(context.Background(), manager.StoreKindStream, "foo", nil)
+	require.Error(t, err)
+}
+
+// lockStealingCache replaces the index lock with that of another instance
+// whenever the index is written, as if the lock had expired and been acquired
+// elsewhere during the write.
+type lockStealingCache struct {
+	*mmock.Cache
+}
+
+func (c lockStealingCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	if key == "prefix_index" {
+		c.Values["prefix_index_lock"] = mmock.CacheItem{Value: "other"}
+	}
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+type cacheManagerFunc func(ctx context.Context, name string, fn func(cache.V1)) error
+
+func (f cacheManagerFunc) AccessCache(ctx context.Context, name string, fn func(cache.V1)) error {
+	return f(ctx, name, fn)
+}
+
+func TestCacheStoreLockTaken(t *testing.T) {
+	c := lockStealingCache{Cache: &mmock.Cache{Values: map[string]mmock.CacheItem{}}}
+	store := manager.NewCacheStore(cacheManagerFunc(func(ctx context.Context, name string, fn func(cache.V1)) error {
+		fn(c)
+		return nil
+	}), "foocache", "prefix_")
+
+	require.NoError(t, store.Set(context.Background(), manager.StoreKindStream, "foo", []byte("foo: 1")))
+
+	// The lock of the other instance must not be released.
+	assert.Equal(t, "other", c.Values["prefix_index_lock"].Value)
+}
+
+func TestTypeAPIStorePersistence(t *testing.T) {
+	store := manager.NewDirectoryStore(t.TempDir())
+
+	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(bmgr, manager.OptSetStore(store))
+	r := router(mgr)
+
+	request := genYAMLRequest("POST", "/resources/cache/foocache", `
+memory: {}
+`)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	streamConf := `
+input:
+  generate:
+    mapping: 'root = "hello world"'
+    interval: 1h
+output:
+  cache:
+    target: foocache
+    key: foo
+`
+
+	for _, id := range []string{"foo", "bar"} {
+		request = genYAMLRequest("POST", "/streams/"+id, streamConf)
+		response = httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	}
+
+	request = genYAMLRequest("DELETE", "/streams/bar", nil)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	assert.Equal(t, map[string]string{
+		"cache:foocache": "\nmemory: {}\n",
+		"stream:foo":     streamConf,
+	}, storeContents(t, store))
+
+	request = genYAMLRequest("PATCH", "/streams/foo", `
+output:
+  cache:
+    key: bar
+`)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	assert.Contains(t, storeContents(t, store)["stream:foo"], "key: bar")
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+
+	// Load the persisted configs into a fresh manager.
+	bmgr, err = bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr = manager.New(bmgr, manager.OptSetStore(store))
+	require.NoError(t, mgr.LoadFromStore(context.Background(), time.Second*5))
+
+	assert.True(t, bmgr.ProbeCache("foocache"))
+
+	status, err := mgr.Read("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", status.Config().Output.Cache.Key)
+
+	_, err = mgr.Read("bar")
+	assert.Equal(t, manager.ErrStreamDoesNotExist, err)
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
+
+type failingStore struct {
+	manager.Store
+}
+
+func (f failingStore) Set(ctx context.Context, kind manager.StoreKind, id string, conf []byte) error {
+	return errors.New("nope")
+}
+
+func TestTypeAPIStoreRollback(t *testing.T) {
+	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(bmgr, manager.OptSetStore(failingStore{Store: manager.NewDirectoryStore(t.TempDir())}))
+	r := router(mgr)
+
+	request := genYAMLRequest("POST", "/streams/foo", `
+input:
+  generate:
+    mapping: 'root = "hello world"'
+    interval: 1h
+output:
+  drop: {}
+`)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusBadGateway, response.Code, response.Body.String())
+
+	_, err = mgr.Read("foo")
+	assert.Equal(t, manager.ErrStreamDoesNotExist, err)
+
+	request = genYAMLRequest("POST", "/resources/cache/foocache", `
+memory: {}
+`)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusBadGateway, response.Code, response.Body.String())
+
+	assert.False(t, bmgr.ProbeCache("foocache"))
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
+
+func TestTypeAPIStorePatchEnvVars(t *testing.T) {
+	store := manager.NewDirectoryStore(t.TempDir())
+
+	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(bmgr, manager.OptSetStore(store))
+	r := router(mgr)
+
+	request := genYAMLRequest("POST", "/streams/foo", `
+input:
+  generate:
+    mapping: 'root = "${BENTHOS_TEST_STORE_SECRET:hunter2}"'
+    interval: 1h
+output:
+  drop: {}
+`)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	request = genYAMLRequest("PATCH", "/streams/foo", `
+input:
+  generate:
+    interval: 2h
+`)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	status, err := mgr.Read("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "2h", status.Config().Input.Generate.Interval)
+	assert.Equal(t, `root = "hunter2"`, status.Config().Input.Generate.Mapping)
+
+	persisted := storeContents(t, store)["stream:foo"]
+	assert.Contains(t, persisted, "${BENTHOS_TEST_STORE_SECRET:hunter2}")
+	assert.Contains(t, persisted, "interval: 2h")
+	assert.NotContains(t, persisted, `root = "hunter2"`)
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
+
+func TestTypeAPIStoreResourceRollback(t *testing.T) {
+	store := manager.NewDirectoryStore(t.TempDir())
+
+	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(bmgr, manager.OptSetStore(store))
+	r := router(mgr)
+
+	request := genYAMLRequest("POST", "/resources/rate_limit/foorl", `
+local:
+  count: 10
+`)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	for _, id := range []string{"foorl", "barrl"} {
+		request = genYAMLRequest("POST", "/resources/rate_limit/"+id, `
+local:
+  count: 0
+`)
+		response = httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		require.Equal(t, http.StatusBadGateway, response.Code, response.Body.String())
+	}
+
+	assert.True(t, bmgr.ProbeRateLimit("foorl"))
+	assert.False(t, bmgr.ProbeRateLimit("barrl"))
+	assert.Equal(t, map[string]string{
+		"rate_limit:foorl": "\nlocal:\n  count: 10\n",
+	}, storeContents(t, store))
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
+
+func TestTypeAPIStorePatchNotPersisted(t *testing.T) {
+	store := manager.NewDirectoryStore(t.TempDir())
+
+	bmgr, err := bmanager.NewV2(bmanager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	mgr := manager.New(bmgr, manager.OptSetStore(store))
+	r := router(mgr)
+
+	conf := stream.NewConfig()
+	conf.Input.Type = "generate"
+	conf.Input.Generate.Mapping = `root = "hello world"`
+	conf.Input.Generate.Interval = "1h"
+	conf.Output.Type = "drop"
+	require.NoError(t, mgr.Create("foo", conf))
+
+	request := genYAMLRequest("PATCH", "/streams/foo", `
+input:
+  generate:
+    interval: 2h
+`)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	status, err := mgr.Read("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "2h", status.Config().Input.Generate.Interval)
+
+	assert.Contains(t, storeContents(t, store)["stream:foo"], "interval: 2h")
+
+	require.NoError(t, mgr.Stop(time.Second*5))
+}
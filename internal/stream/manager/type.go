@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -8,10 +9,14 @@ import (
 	"sync/atomic"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/benthosdev/benthos/v4/internal/bundle"
 	"github.com/benthosdev/benthos/v4/internal/component"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/component/processor"
+	"github.com/benthosdev/benthos/v4/internal/config"
+	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/stream"
 )
@@ -24,6 +29,9 @@ type StreamStatus struct {
 	logger       log.Modular
 	metrics      *metrics.Local
 	createdAt    time.Time
+
+	errors     *errorRecorder
+	throughput *throughputSampler
 }
 
 // NewStreamStatus creates a new StreamStatus.
@@ -33,12 +41,28 @@ func NewStreamStatus(
 	logger log.Modular,
 	stats *metrics.Local,
 ) *StreamStatus {
+	return newStreamStatus(conf, strm, logger, stats, newErrorRecorder())
+}
+
+func newStreamStatus(
+	conf stream.Config,
+	strm *stream.Type,
+	logger log.Modular,
+	stats *metrics.Local,
+	recorder *errorRecorder,
+) *StreamStatus {
+	sampler := newThroughputSampler(strm.Counts)
+	go sampler.loop(throughputSampleInterval)
+
 	return &StreamStatus{
 		config:    conf,
 		strm:      strm,
 		logger:    logger,
 		metrics:   stats,
 		createdAt: time.Now(),
+
+		errors:     recorder,
+		throughput: sampler,
 	}
 }
 
@@ -54,6 +78,43 @@ func (s *StreamStatus) IsReady() bool {
 	return s.strm.IsReady()
 }
 
+// IsPaused returns a boolean indicating whether the stream is paused, in which
+// case it is not consuming data from its input.
+func (s *StreamStatus) IsPaused() bool {
+	return s.strm.IsPaused()
+}
+
+// InputConnected returns a boolean indicating whether the input of the stream
+// is connected.
+func (s *StreamStatus) InputConnected() bool {
+	return s.strm.InputConnected()
+}
+
+// OutputConnected returns a boolean indicating whether the output of the
+// stream is connected.
+func (s *StreamStatus) OutputConnected() bool {
+	return s.strm.OutputConnected()
+}
+
+// Throughput returns the rates at which messages are being received and
+// delivered by the stream, and the number of messages currently in-flight.
+func (s *StreamStatus) Throughput() Throughput {
+	return s.throughput.throughput()
+}
+
+// Errors returns the last error logged by each component of the stream, keyed
+// by the path of the component.
+func (s *StreamStatus) Errors() map[string]ComponentError {
+	return s.errors.Errors()
+}
+
+// LastError returns the most recent error logged by any component of the
+// stream along with the path of the component, or false if no errors have been
+// logged.
+func (s *StreamStatus) LastError() (string, ComponentError, bool) {
+	return s.errors.Last()
+}
+
 // Uptime returns a time.Duration indicating the current uptime of the stream.
 func (s *StreamStatus) Uptime() time.Duration {
 	if stoppedAfter := atomic.LoadInt64(&s.stoppedAfter); stoppedAfter > 0 {
@@ -75,6 +136,7 @@ func (s *StreamStatus) Metrics() *metrics.Local {
 // setClosed sets the flag indicating that the stream is closed.
 func (s *StreamStatus) setClosed() {
 	atomic.SwapInt64(&s.stoppedAfter, int64(time.Since(s.createdAt)))
+	s.throughput.close()
 }
 
 //------------------------------------------------------------------------------
@@ -93,6 +155,13 @@ type Type struct {
 
 	manager    bundle.NewManagement
 	apiEnabled bool
+	store      Store
+
+	// The raw configs of persisted streams and resources, prior to the
+	// resolution of environment variables, which are used in order to persist
+	// patches and to restore the store when a change fails.
+	rawConfs   map[rawConfKey][]byte
+	rawConfMut sync.Mutex
 
 	lock sync.Mutex
 }
@@ -103,6 +172,7 @@ func New(mgr bundle.NewManagement, opts ...func(*Type)) *Type {
 		streams:    map[string]*StreamStatus{},
 		apiEnabled: true,
 		manager:    mgr,
+		rawConfs:   map[rawConfKey][]byte{},
 	}
 	for _, opt := range opts {
 		opt(t)
@@ -121,6 +191,15 @@ func OptAPIEnabled(b bool) func(*Type) {
 	}
 }
 
+// OptSetStore sets a store that the configs of streams and resources created,
+// updated or deleted via the API are written through to. By default API
+// changes are not persisted.
+func OptSetStore(s Store) func(*Type) {
+	return func(t *Type) {
+		t.store = s
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Errors specifically returned by a stream manager.
@@ -134,6 +213,10 @@ var (
 // Create attempts to construct and run a new stream under a unique ID. If the
 // ID already exists an error is returned.
 func (m *Type) Create(id string, conf stream.Config) error {
+	return m.create(id, conf, false)
+}
+
+func (m *Type) create(id string, conf stream.Config, paused bool) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
@@ -148,15 +231,20 @@ func (m *Type) Create(id string, conf stream.Config) error {
 	strmFlatMetrics := metrics.NewLocal()
 	sMgr := m.manager.ForStream(id).WithAddedMetrics(strmFlatMetrics).(bundle.NewManagement)
 
+	// Errors logged by the components of the stream are recorded so that they
+	// can be reported by the status endpoint.
+	errRecorder := newErrorRecorder()
+	sMgr = sMgr.WithLogger(newErrorRecordingLogger(sMgr.Logger(), errRecorder)).(bundle.NewManagement)
+
 	var wrapper *StreamStatus
 	strm, err := stream.New(conf, sMgr, stream.OptOnClose(func() {
 		wrapper.setClosed()
-	}))
+	}), stream.OptPausable(), stream.OptStartPaused(paused))
 	if err != nil {
 		return err
 	}
 
-	wrapper = NewStreamStatus(conf, strm, sMgr.Logger(), strmFlatMetrics)
+	wrapper = newStreamStatus(conf, strm, sMgr.Logger(), strmFlatMetrics, errRecorder)
 	m.streams[id] = wrapper
 	return nil
 }
@@ -180,7 +268,8 @@ func (m *Type) Read(id string) (*StreamStatus, error) {
 }
 
 // Update attempts to stop an existing stream and replace it with a new version
-// of the same stream.
+// of the same stream. If the existing stream is paused then the new version is
+// also paused.
 func (m *Type) Update(id string, conf stream.Config, timeout time.Duration) error {
 	m.lock.Lock()
 	wrapper, exists := m.streams[id]
@@ -198,10 +287,11 @@ func (m *Type) Update(id string, conf stream.Config, timeout time.Duration) erro
 		return nil
 	}
 
+	paused := wrapper.IsPaused()
 	if err := m.Delete(id, timeout); err != nil {
 		return err
 	}
-	return m.Create(id, conf)
+	return m.create(id, conf, paused)
 }
 
 // Delete attempts to stop and remove a stream by its ID. Returns an error if
@@ -231,6 +321,192 @@ func (m *Type) Delete(id string, timeout time.Duration) error {
 	return nil
 }
 
+// Pause stops a stream from consuming data from its input without closing any
+// of its components, allowing in-flight data to be delivered. Pausing a stream
+// that is already paused has no effect.
+func (m *Type) Pause(id string) error {
+	wrapper, err := m.Read(id)
+	if err != nil {
+		return err
+	}
+	return wrapper.strm.Pause()
+}
+
+// Resume continues consuming data from the input of a paused stream. Resuming
+// a stream that is not paused has no effect.
+func (m *Type) Resume(id string) error {
+	wrapper, err := m.Read(id)
+	if err != nil {
+		return err
+	}
+	return wrapper.strm.Resume()
+}
+
+//------------------------------------------------------------------------------
+
+func (m *Type) persist(ctx context.Context, kind StoreKind, id string, conf []byte) error {
+	if m.store == nil {
+		return nil
+	}
+	if err := m.store.Set(ctx, kind, id, conf); err != nil {
+		return fmt.Errorf("failed to persist %v '%v': %w", kind, id, err)
+	}
+	m.setRawConf(kind, id, conf)
+	return nil
+}
+
+func (m *Type) unpersist(ctx context.Context, kind StoreKind, id string) error {
+	if m.store == nil {
+		return nil
+	}
+	if err := m.store.Delete(ctx, kind, id); err != nil {
+		return fmt.Errorf("failed to remove persisted %v '%v': %w", kind, id, err)
+	}
+	m.rawConfMut.Lock()
+	delete(m.rawConfs, rawConfKey{kind: kind, id: id})
+	m.rawConfMut.Unlock()
+	return nil
+}
+
+// restorePersisted returns the persisted config of a given kind and id to a
+// prior state, either the raw config previously persisted or nothing at all.
+func (m *Type) restorePersisted(ctx context.Context, kind StoreKind, id string, prevRaw []byte, hadRaw bool) error {
+	if hadRaw {
+		return m.persist(ctx, kind, id, prevRaw)
+	}
+	return m.unpersist(ctx, kind, id)
+}
+
+type rawConfKey struct {
+	kind StoreKind
+	id   string
+}
+
+func (m *Type) setRawConf(kind StoreKind, id string, conf []byte) {
+	m.rawConfMut.Lock()
+	m.rawConfs[rawConfKey{kind: kind, id: id}] = conf
+	m.rawConfMut.Unlock()
+}
+
+func (m *Type) rawConf(kind StoreKind, id string) ([]byte, bool) {
+	m.rawConfMut.Lock()
+	defer m.rawConfMut.Unlock()
+	conf, exists := m.rawConfs[rawConfKey{kind: kind, id: id}]
+	return conf, exists
+}
+
+// createPersisted creates a stream and persists its raw config. When the
+// config cannot be persisted the stream is removed again so that the running
+// streams never diverge from those persisted.
+func (m *Type) createPersisted(ctx context.Context, id string, conf stream.Config, rawConf []byte, timeout time.Duration) error {
+	if err := m.Create(id, conf); err != nil {
+		return err
+	}
+	if err := m.persist(ctx, StoreKindStream, id, rawConf); err != nil {
+		if rErr := m.Delete(id, timeout); rErr != nil {
+			m.manager.Logger().Errorf("Failed to remove stream '%v' after failing to persist it: %v\n", id, rErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// updatePersisted updates a stream and persists its raw config. When the
+// config cannot be persisted the stream is reverted to its prior config.
+func (m *Type) updatePersisted(ctx context.Context, id string, conf stream.Config, rawConf []byte, timeout time.Duration) error {
+	prev, err := m.Read(id)
+	if err != nil {
+		return err
+	}
+	if err := m.Update(id, conf, timeout); err != nil {
+		return err
+	}
+	if err := m.persist(ctx, StoreKindStream, id, rawConf); err != nil {
+		if rErr := m.Update(id, prev.Config(), timeout); rErr != nil {
+			m.manager.Logger().Errorf("Failed to revert stream '%v' after failing to persist it: %v\n", id, rErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// deletePersisted removes a persisted stream config and then deletes the
+// stream. When the stream fails to be deleted its config is persisted again.
+func (m *Type) deletePersisted(ctx context.Context, id string, timeout time.Duration) error {
+	rawConf, hadRaw := m.rawConf(StoreKindStream, id)
+	if err := m.unpersist(ctx, StoreKindStream, id); err != nil {
+		return err
+	}
+	if err := m.Delete(id, timeout); err != nil {
+		if hadRaw && !errors.Is(err, ErrStreamDoesNotExist) {
+			if pErr := m.persist(ctx, StoreKindStream, id, rawConf); pErr != nil {
+				m.manager.Logger().Errorf("Failed to restore persisted stream '%v' after failing to delete it: %v\n", id, pErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// LoadFromStore creates the resources and streams persisted within the store
+// of the manager, if one has been set. Resources are loaded before streams, and
+// streams that already exist are updated to their persisted config.
+func (m *Type) LoadFromStore(ctx context.Context, timeout time.Duration) error {
+	if m.store == nil {
+		return nil
+	}
+
+	type storedConf struct {
+		kind StoreKind
+		id   string
+		conf []byte
+	}
+	var resources, streams []storedConf
+	if err := m.store.Walk(ctx, func(kind StoreKind, id string, conf []byte) error {
+		if kind == StoreKindStream {
+			streams = append(streams, storedConf{kind: kind, id: id, conf: conf})
+		} else {
+			resources = append(resources, storedConf{kind: kind, id: id, conf: conf})
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to read store: %w", err)
+	}
+
+	for _, res := range resources {
+		decodeFn := m.resourceDecodeFn(ctx, docs.Type(res.kind), res.id)
+		if decodeFn == nil {
+			return fmt.Errorf("persisted resource '%v' has an unsupported type: %v", res.id, res.kind)
+		}
+		var node yaml.Node
+		err := yaml.Unmarshal(config.ReplaceEnvVariables(res.conf), &node)
+		if err == nil {
+			var storeFn func() error
+			if storeFn, err = decodeFn(&node); err == nil {
+				err = storeFn()
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load persisted %v '%v': %w", res.kind, res.id, err)
+		}
+		m.setRawConf(res.kind, res.id, res.conf)
+	}
+
+	for _, strm := range streams {
+		conf, _, _, err := parseStreamConfig(config.ReplaceEnvVariables(strm.conf))
+		if err == nil {
+			if err = m.Update(strm.id, conf, timeout); errors.Is(err, ErrStreamDoesNotExist) {
+				err = m.Create(strm.id, conf)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load persisted stream '%v': %w", strm.id, err)
+		}
+		m.setRawConf(StoreKindStream, strm.id, strm.conf)
+	}
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 // Stop attempts to gracefully shut down all active streams and close the
@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/benthosdev/benthos/v4/internal/message"
+)
+
+// inputGate forwards transactions from the input layer of a stream to the next
+// layer, and can be paused in order to stop consuming from the input without
+// closing it. Transactions already forwarded are unaffected by a pause.
+//
+// Since every transaction passes through the gate it also counts the messages
+// consumed from the input and the outcome of their acknowledgements.
+//
+// Closing the gate whilst it is paused stops it without consuming any further
+// transactions from the input, which allows a paused stream to be shut down
+// without resuming it.
+type inputGate struct {
+	// Kept first in order to guarantee 64-bit alignment for atomic access.
+	counter messageCounter
+
+	tranChan chan message.Transaction
+
+	mut        sync.Mutex
+	paused     bool
+	pauseChan  chan struct{}
+	resumeChan chan struct{}
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+func newInputGate(paused bool) *inputGate {
+	g := &inputGate{
+		tranChan:   make(chan message.Transaction),
+		pauseChan:  make(chan struct{}),
+		resumeChan: make(chan struct{}),
+		closeChan:  make(chan struct{}),
+	}
+	if paused {
+		g.pause()
+	}
+	return g
+}
+
+func (g *inputGate) loop(in <-chan message.Transaction) {
+	defer close(g.tranChan)
+	for {
+		g.mut.Lock()
+		paused, pauseChan, resumeChan := g.paused, g.pauseChan, g.resumeChan
+		g.mut.Unlock()
+
+		if paused {
+			select {
+			case <-resumeChan:
+			case <-g.closeChan:
+				return
+			}
+			continue
+		}
+
+		select {
+		case tran, open := <-in:
+			if !open {
+				return
+			}
+			g.tranChan <- g.counter.track(tran)
+		case <-pauseChan:
+		}
+	}
+}
+
+func (g *inputGate) pause() {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	if g.paused {
+		return
+	}
+	g.paused = true
+	close(g.pauseChan)
+	g.resumeChan = make(chan struct{})
+}
+
+func (g *inputGate) resume() {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	if !g.paused {
+		return
+	}
+	g.paused = false
+	close(g.resumeChan)
+	g.pauseChan = make(chan struct{})
+}
+
+// close stops the gate if it is paused, or as soon as it is next paused.
+// Otherwise the gate continues forwarding transactions until the input is
+// closed.
+func (g *inputGate) close() {
+	g.closeOnce.Do(func() {
+		close(g.closeChan)
+	})
+}
+
+func (g *inputGate) isPaused() bool {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	return g.paused
+}
@@ -2,6 +2,7 @@ package stream
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"runtime/pprof"
 	"time"
@@ -26,6 +27,10 @@ type Type struct {
 	pipelineLayer pipeline.Type
 	outputLayer   ioutput.Streamed
 
+	gate        *inputGate
+	pausable    bool
+	startPaused bool
+
 	manager bundle.NewManagement
 
 	onClose func()
@@ -47,6 +52,11 @@ func New(conf Config, mgr bundle.NewManagement, opts ...func(*Type)) (*Type, err
 
 	healthCheck := func(w http.ResponseWriter, r *http.Request) {
 		connected := true
+		if t.IsPaused() {
+			connected = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("stream paused\n"))
+		}
 		if !t.inputLayer.Connected() {
 			connected = false
 			w.WriteHeader(http.StatusServiceUnavailable)
@@ -63,7 +73,7 @@ func New(conf Config, mgr bundle.NewManagement, opts ...func(*Type)) (*Type, err
 	}
 	t.manager.RegisterEndpoint(
 		"/ready",
-		"Returns 200 OK if all inputs and outputs are connected, otherwise a 503 is returned.",
+		"Returns 200 OK if all inputs and outputs are connected and the stream is not paused, otherwise a 503 is returned.",
 		healthCheck,
 	)
 	return t, nil
@@ -78,15 +88,92 @@ func OptOnClose(onClose func()) func(*Type) {
 	}
 }
 
+// OptPausable allows the stream to be paused and resumed. Streams created
+// without this option cannot be paused and do not count the messages that pass
+// through them, which avoids the overhead of gating the input.
+func OptPausable() func(*Type) {
+	return func(t *Type) {
+		t.pausable = true
+	}
+}
+
+// OptStartPaused sets whether a pausable stream should be paused from the
+// moment it is created, in which case nothing is consumed from the input until
+// Resume is called. Starting a stream paused requires OptPausable.
+func OptStartPaused(paused bool) func(*Type) {
+	return func(t *Type) {
+		t.startPaused = paused
+	}
+}
+
 //------------------------------------------------------------------------------
 
+// ErrNotPausable is returned when attempting to pause or resume a stream that
+// was created without OptPausable.
+var ErrNotPausable = errors.New("stream cannot be paused")
+
+// Pause stops the stream from consuming data from its input, without closing
+// the input or any other component. Messages that have already been consumed
+// continue to be processed and delivered. Returns ErrNotPausable if the stream
+// cannot be paused.
+func (t *Type) Pause() error {
+	if t.gate == nil {
+		return ErrNotPausable
+	}
+	t.gate.pause()
+	return nil
+}
+
+// Resume continues consuming data from the input of a paused stream. Returns
+// ErrNotPausable if the stream cannot be paused.
+func (t *Type) Resume() error {
+	if t.gate == nil {
+		return ErrNotPausable
+	}
+	t.gate.resume()
+	return nil
+}
+
+// IsPaused returns a boolean indicating whether the stream is paused.
+func (t *Type) IsPaused() bool {
+	if t.gate == nil {
+		return false
+	}
+	return t.gate.isPaused()
+}
+
 // IsReady returns a boolean indicating whether both the input and output layers
 // of the stream are connected.
 func (t *Type) IsReady() bool {
 	return t.inputLayer.Connected() && t.outputLayer.Connected()
 }
 
+// InputConnected returns a boolean indicating whether the input layer of the
+// stream is connected.
+func (t *Type) InputConnected() bool {
+	return t.inputLayer.Connected()
+}
+
+// OutputConnected returns a boolean indicating whether the output layer of the
+// stream is connected.
+func (t *Type) OutputConnected() bool {
+	return t.outputLayer.Connected()
+}
+
+// Counts returns the number of messages that have passed through the stream,
+// which are only counted for streams that can be paused.
+func (t *Type) Counts() Counts {
+	if t.gate == nil {
+		return Counts{}
+	}
+	return t.gate.counter.counts()
+}
+
 func (t *Type) start() (err error) {
+	if t.startPaused && !t.pausable {
+		return errors.New("a stream must be pausable in order to start paused")
+	}
+
 	// Constructors
 	iMgr := t.manager.IntoPath("input").(bundle.NewManagement)
 	if t.inputLayer, err = iMgr.NewInput(t.conf.Input); err != nil {
@@ -113,6 +200,11 @@ func (t *Type) start() (err error) {
 	var nextTranChan <-chan message.Transaction
 
 	nextTranChan = t.inputLayer.TransactionChan()
+	if t.pausable {
+		t.gate = newInputGate(t.startPaused)
+		go t.gate.loop(nextTranChan)
+		nextTranChan = t.gate.tranChan
+	}
 	if t.bufferLayer != nil {
 		if err = t.bufferLayer.Consume(nextTranChan); err != nil {
 			return
@@ -141,11 +233,20 @@ func (t *Type) start() (err error) {
 	return nil
 }
 
+// closeGate prevents a paused stream from consuming any further data from its
+// input whilst it is shutting down.
+func (t *Type) closeGate() {
+	if t.gate != nil {
+		t.gate.close()
+	}
+}
+
 // StopGracefully attempts to close the stream in the most graceful way by only
 // closing the input layer and waiting for all other layers to terminate by
 // proxy. This should guarantee that all in-flight and buffered data is resolved
 // before shutting down.
 func (t *Type) StopGracefully(timeout time.Duration) (err error) {
+	t.closeGate()
 	t.inputLayer.CloseAsync()
 	started := time.Now()
 	if err = t.inputLayer.WaitForClose(timeout); err != nil {
@@ -196,6 +297,7 @@ func (t *Type) StopGracefully(timeout time.Duration) (err error) {
 // the pipeline under certain circumstances but is less graceful than
 // stopGracefully, which should be attempted first.
 func (t *Type) StopOrdered(timeout time.Duration) (err error) {
+	t.closeGate()
 	t.inputLayer.CloseAsync()
 	started := time.Now()
 	if err = t.inputLayer.WaitForClose(timeout); err != nil {
@@ -242,6 +344,7 @@ func (t *Type) StopOrdered(timeout time.Duration) (err error) {
 // the stream to gracefully wind down in the order of component layers. This
 // should only be attempted if both stopGracefully and stopOrdered failed.
 func (t *Type) StopUnordered(timeout time.Duration) (err error) {
+	t.closeGate()
 	t.inputLayer.CloseAsync()
 	if t.bufferLayer != nil {
 		t.bufferLayer.CloseAsync()
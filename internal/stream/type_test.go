@@ -122,3 +122,60 @@ func TestTypeCloseUnordered(t *testing.T) {
 	require.NoError(t, err)
 	assert.NoError(t, strm.StopUnordered(time.Minute))
 }
+
+func TestTypeNotPausable(t *testing.T) {
+	conf := stream.NewConfig()
+	conf.Input.Type = input.TypeHTTPServer
+	conf.Output.Type = output.TypeHTTPServer
+
+	newMgr, err := manager.NewV2(manager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	strm, err := stream.New(conf, newMgr)
+	require.NoError(t, err)
+
+	assert.Equal(t, stream.ErrNotPausable, strm.Pause())
+	assert.Equal(t, stream.ErrNotPausable, strm.Resume())
+	assert.False(t, strm.IsPaused())
+	assert.Equal(t, stream.Counts{}, strm.Counts())
+	require.NoError(t, strm.Stop(time.Minute))
+
+	_, err = stream.New(conf, newMgr, stream.OptStartPaused(true))
+	require.Error(t, err)
+
+	strm, err = stream.New(conf, newMgr, stream.OptPausable(), stream.OptStartPaused(true))
+	require.NoError(t, err)
+
+	assert.True(t, strm.IsPaused())
+	require.NoError(t, strm.Resume())
+	assert.False(t, strm.IsPaused())
+	require.NoError(t, strm.Pause())
+	assert.True(t, strm.IsPaused())
+
+	require.NoError(t, strm.Stop(time.Minute))
+}
+
+func TestTypeStopPaused(t *testing.T) {
+	conf := stream.NewConfig()
+	conf.Input.Type = "generate"
+	conf.Input.Generate.Interval = "1ms"
+	conf.Input.Generate.Mapping = `root = "hello world"`
+	conf.Output.Type = output.TypeDrop
+
+	newMgr, err := manager.NewV2(manager.NewResourceConfig(), mock.NewManager(), log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	for name, stopFn := range map[string]func(*stream.Type) error{
+		"gracefully": func(s *stream.Type) error { return s.StopGracefully(time.Minute) },
+		"ordered":    func(s *stream.Type) error { return s.StopOrdered(time.Minute) },
+		"unordered":  func(s *stream.Type) error { return s.StopUnordered(time.Minute) },
+	} {
+		strm, err := stream.New(conf, newMgr, stream.OptPausable(), stream.OptStartPaused(true))
+		require.NoError(t, err, name)
+
+		<-time.After(time.Millisecond * 50)
+		require.NoError(t, stopFn(strm), name)
+		assert.True(t, strm.IsPaused(), name)
+		assert.Equal(t, stream.Counts{}, strm.Counts(), name)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	"github.com/benthosdev/benthos/v4/internal/docs"
 	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/stream"
 )
 
 // FieldConfig describes a configuration field used in the template.
@@ -116,6 +117,10 @@ func (c Config) compile() (*compiled, error) {
 	}
 	var metricsMapping *metrics.Mapping
 	if c.MetricsMapping != "" {
+		switch spec.Type {
+		case docs.TypeMetrics, docs.TypeTracer, TypeStream:
+			return nil, fmt.Errorf("metrics_mapping is not supported by %v templates", spec.Type)
+		}
 		if metricsMapping, err = metrics.NewMapping(c.MetricsMapping, log.Noop()); err != nil {
 			return nil, fmt.Errorf("parse metrics mapping: %w", err)
 		}
@@ -163,7 +168,13 @@ func (c Config) Test() ([]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("test '%v': %w", test.Name, err)
 		}
-		for _, lint := range docs.LintYAML(docs.NewLintContext(), docs.Type(c.Type), outConf) {
+		var lints []docs.Lint
+		if docs.Type(c.Type) == TypeStream {
+			lints = stream.Spec().LintYAML(docs.NewLintContext(), outConf)
+		} else {
+			lints = docs.LintYAML(docs.NewLintContext(), docs.Type(c.Type), outConf)
+		}
+		for _, lint := range lints {
 			failures = append(failures, fmt.Sprintf("test '%v': lint error in resulting config: line %v: %v", test.Name, lint.Line, lint.What))
 		}
 		if len(test.Expected.Content) > 0 {
@@ -227,9 +238,9 @@ func ConfigSpec() docs.FieldSpecs {
 	return docs.FieldSpecs{
 		docs.FieldString("name", "The name of the component this template will create."),
 		docs.FieldString(
-			"type", "The type of the component this template will create.",
+			"type", "The type of the component this template will create. The type `stream` creates a template that expands into the `input`, `buffer`, `pipeline` and `output` fields of a whole stream, and is used by setting a field of the same name at the root of a config.",
 		).HasOptions(
-			"cache", "input", "output", "processor", "rate_limit",
+			"buffer", "cache", "input", "metrics", "output", "processor", "rate_limit", "stream", "tracer",
 		),
 		docs.FieldString(
 			"status", "The stability of the template describing the likelihood that the configuration spec of the template, or it's behaviour, will change.",
@@ -9,13 +9,17 @@ import (
 
 	"github.com/benthosdev/benthos/v4/internal/bloblang/mapping"
 	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component/buffer"
 	"github.com/benthosdev/benthos/v4/internal/component/cache"
 	iinput "github.com/benthosdev/benthos/v4/internal/component/input"
 	"github.com/benthosdev/benthos/v4/internal/component/metrics"
 	ioutput "github.com/benthosdev/benthos/v4/internal/component/output"
 	iprocessor "github.com/benthosdev/benthos/v4/internal/component/processor"
 	"github.com/benthosdev/benthos/v4/internal/component/ratelimit"
+	"github.com/benthosdev/benthos/v4/internal/component/tracer"
+	"github.com/benthosdev/benthos/v4/internal/config"
 	"github.com/benthosdev/benthos/v4/internal/docs"
+	"github.com/benthosdev/benthos/v4/internal/log"
 	"github.com/benthosdev/benthos/v4/internal/manager"
 	"github.com/benthosdev/benthos/v4/internal/message"
 	"github.com/benthosdev/benthos/v4/internal/old/input"
@@ -128,10 +132,16 @@ func (c *compiled) ExpandToNode(node *yaml.Node) (*yaml.Node, error) {
 
 //------------------------------------------------------------------------------
 
+// TypeStream is the type of templates that expand into a whole stream config
+// rather than a single component.
+const TypeStream docs.Type = "stream"
+
 // RegisterTemplate attempts to add a template component to the global list of
 // component types.
 func registerTemplate(tmpl *compiled) error {
 	switch tmpl.spec.Type {
+	case docs.TypeBuffer:
+		return registerBufferTemplate(tmpl, bundle.AllBuffers)
 	case docs.TypeCache:
 		return registerCacheTemplate(tmpl, bundle.AllCaches)
 	case docs.TypeInput:
@@ -142,6 +152,12 @@ func registerTemplate(tmpl *compiled) error {
 		return registerProcessorTemplate(tmpl, bundle.AllProcessors)
 	case docs.TypeRateLimit:
 		return registerRateLimitTemplate(tmpl, bundle.AllRateLimits)
+	case docs.TypeMetrics:
+		return registerMetricsTemplate(tmpl, bundle.AllMetrics)
+	case docs.TypeTracer:
+		return registerTracerTemplate(tmpl, bundle.AllTracers)
+	case TypeStream:
+		return config.RegisterStreamTemplate(tmpl.spec.Name, tmpl.spec.Config, tmpl.ExpandToNode)
 	}
 	return fmt.Errorf("unable to register template for component type %v", tmpl.spec.Type)
 }
@@ -155,6 +171,25 @@ func WithMetricsMapping(nm bundle.NewManagement, m *metrics.Mapping) bundle.NewM
 	return nm
 }
 
+func registerBufferTemplate(tmpl *compiled, set *bundle.BufferSet) error {
+	return set.Add(func(c buffer.Config, nm bundle.NewManagement) (buffer.Streamed, error) {
+		newNode, err := tmpl.ExpandToNode(c.Plugin.(*yaml.Node))
+		if err != nil {
+			return nil, err
+		}
+
+		conf := buffer.NewConfig()
+		if err := newNode.Decode(&conf); err != nil {
+			return nil, err
+		}
+
+		if tmpl.metricsMapping != nil {
+			nm = WithMetricsMapping(nm, tmpl.metricsMapping)
+		}
+		return nm.NewBuffer(conf)
+	}, tmpl.spec)
+}
+
 func registerCacheTemplate(tmpl *compiled, set *bundle.CacheSet) error {
 	return set.Add(func(c cache.Config, nm bundle.NewManagement) (cache.V1, error) {
 		newNode, err := tmpl.ExpandToNode(c.Plugin.(*yaml.Node))
@@ -255,3 +290,34 @@ func registerRateLimitTemplate(tmpl *compiled, set *bundle.RateLimitSet) error {
 		return nm.NewRateLimit(conf)
 	}, tmpl.spec)
 }
+
+func registerMetricsTemplate(tmpl *compiled, set *bundle.MetricsSet) error {
+	return set.Add(func(c metrics.Config, l log.Modular) (metrics.Type, error) {
+		newNode, err := tmpl.ExpandToNode(c.Plugin.(*yaml.Node))
+		if err != nil {
+			return nil, err
+		}
+
+		conf := metrics.NewConfig()
+		if err := newNode.Decode(&conf); err != nil {
+			return nil, err
+		}
+
+		return set.Init(conf, l)
+	}, tmpl.spec)
+}
+
+func registerTracerTemplate(tmpl *compiled, set *bundle.TracerSet) error {
+	return set.Add(func(c tracer.Config) (tracer.Type, error) {
+		newNode, err := tmpl.ExpandToNode(c.Plugin.(*yaml.Node))
+		if err != nil {
+			return nil, err
+		}
+
+		conf := tracer.NewConfig()
+		if err := newNode.Decode(&conf); err != nil {
+			return nil, err
+		}
+		return set.Init(conf)
+	}, tmpl.spec)
+}
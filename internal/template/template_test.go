@@ -1,13 +1,21 @@
 package template_test
 
 import (
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
+	"github.com/benthosdev/benthos/v4/internal/bundle"
+	"github.com/benthosdev/benthos/v4/internal/component/buffer"
+	"github.com/benthosdev/benthos/v4/internal/component/metrics"
+	"github.com/benthosdev/benthos/v4/internal/component/tracer"
+	"github.com/benthosdev/benthos/v4/internal/log"
+	"github.com/benthosdev/benthos/v4/internal/manager"
 	"github.com/benthosdev/benthos/v4/internal/template"
 	_ "github.com/benthosdev/benthos/v4/public/components/all"
 )
@@ -29,3 +37,131 @@ func TestTemplateTesting(t *testing.T) {
 		})
 	}
 }
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+	tPath := filepath.Join(t.TempDir(), "template.yaml")
+	require.NoError(t, os.WriteFile(tPath, []byte(content), 0o644))
+	return tPath
+}
+
+func TestBufferTemplate(t *testing.T) {
+	lints, err := template.InitTemplates(writeTemplate(t, `
+name: test_bounded_memory
+type: buffer
+fields:
+  - name: limit_kb
+    type: int
+mapping: |
+  root.memory.limit = this.limit_kb * 1024
+`))
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	conf := buffer.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+test_bounded_memory:
+  limit_kb: 10
+`), &conf))
+
+	mgr, err := manager.NewV2(manager.NewResourceConfig(), nil, log.Noop(), metrics.Noop())
+	require.NoError(t, err)
+
+	buf, err := mgr.NewBuffer(conf)
+	require.NoError(t, err)
+	buf.CloseAsync()
+
+	conf = buffer.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+test_bounded_memory:
+  limit_kb: nope
+`), &conf))
+
+	_, err = mgr.NewBuffer(conf)
+	require.Error(t, err)
+}
+
+func TestMetricsTemplate(t *testing.T) {
+	lints, err := template.InitTemplates(writeTemplate(t, `
+name: test_prometheus_renamed
+type: metrics
+fields:
+  - name: prefix
+    type: string
+mapping: |
+  root.prometheus = {}
+  root.mapping = "root = %q + this".format(this.prefix + "_")
+`))
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	conf := metrics.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+test_prometheus_renamed:
+  prefix: foo
+`), &conf))
+
+	stats, err := bundle.AllMetrics.Init(conf, log.Noop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stats.Close()
+	})
+
+	stats.GetCounter("bar").Incr(1)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	stats.HandlerFunc()(w, req)
+
+	assert.Contains(t, w.Body.String(), "\nfoo_bar 1")
+}
+
+func TestMetricsTemplateMetricsMapping(t *testing.T) {
+	_, err := template.InitTemplates(writeTemplate(t, `
+name: test_prometheus_metrics_mapping
+type: metrics
+mapping: |
+  root.prometheus = {}
+metrics_mapping: 'root = "foo_" + this'
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics_mapping is not supported by metrics templates")
+}
+
+func TestTracerTemplate(t *testing.T) {
+	lints, err := template.InitTemplates(writeTemplate(t, `
+name: test_disabled_tracer
+type: tracer
+fields:
+  - name: enabled
+    type: bool
+mapping: |
+  root = if this.enabled {
+    throw("this tracer cannot be enabled")
+  } else {
+    { "none": {} }
+  }
+`))
+	require.NoError(t, err)
+	assert.Empty(t, lints)
+
+	conf := tracer.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+test_disabled_tracer:
+  enabled: false
+`), &conf))
+
+	trc, err := bundle.AllTracers.Init(conf)
+	require.NoError(t, err)
+	assert.NotNil(t, trc)
+
+	conf = tracer.NewConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(`
+test_disabled_tracer:
+  enabled: true
+`), &conf))
+
+	_, err = bundle.AllTracers.Init(conf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "this tracer cannot be enabled")
+}
@@ -40,6 +40,22 @@ func (m *MetadataFilter) Walk(msg *Message, fn func(key, value string) error) er
 	})
 }
 
+// WalkMut iterates the filtered metadata key/value pairs from a message, with
+// values in their original type, and executes a provided closure function for
+// each pair. An error returned by the closure will be returned by this function
+// and prevent subsequent pairs from being accessed.
+func (m *MetadataFilter) WalkMut(msg *Message, fn func(key string, value interface{}) error) error {
+	if m == nil {
+		return nil
+	}
+	return msg.MetaWalkMut(func(key string, value interface{}) error {
+		if !m.f.Match(key) {
+			return nil
+		}
+		return fn(key, value)
+	})
+}
+
 // FieldMetadataFilter accesses a field from a parsed config that was defined
 // with NewMetdataFilterField and returns a MetadataFilter, or an error if the
 // configuration was invalid.
@@ -187,6 +187,22 @@ func (m *Message) MetaDelete(key string) {
 	m.part.MetaDelete(key)
 }
 
+// MetaGetMut attempts to find a metadata key from the message and returns the
+// value in its original type and a boolean indicating whether it was found. The
+// returned value must not be mutated.
+func (m *Message) MetaGetMut(key string) (interface{}, bool) {
+	return m.part.MetaGetMut(key)
+}
+
+// MetaSetMut sets the value of a metadata key to a value of any type, such as
+// a number, boolean, timestamp or structured object. The type of the value is
+// preserved for components and mappings that support typed metadata, and
+// components that only support strings will receive a string representation.
+func (m *Message) MetaSetMut(key string, value interface{}) {
+	m.ensureCopied()
+	m.part.MetaSetMut(key, value)
+}
+
 // MetaWalk iterates each metadata key/value pair and executes a provided
 // closure on each iteration. To stop iterating, return an error from the
 // closure. An error returned by the closure will be returned by this function.
@@ -194,6 +210,14 @@ func (m *Message) MetaWalk(fn func(string, string) error) error {
 	return m.part.MetaIter(fn)
 }
 
+// MetaWalkMut iterates each metadata key/value pair with values in their
+// original type and executes a provided closure on each iteration. To stop
+// iterating, return an error from the closure. An error returned by the
+// closure will be returned by this function.
+func (m *Message) MetaWalkMut(fn func(key string, value interface{}) error) error {
+	return m.part.MetaIterMut(fn)
+}
+
 //------------------------------------------------------------------------------
 
 // BloblangQuery executes a parsed Bloblang mapping on a message and returns a
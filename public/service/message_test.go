@@ -76,6 +76,39 @@ func TestMessageCopyAirGap(t *testing.T) {
 	assert.Equal(t, "baz", v)
 }
 
+func TestMessageTypedMetadata(t *testing.T) {
+	g1 := NewMessage([]byte("hello world"))
+	g1.MetaSetMut("foo", int64(10))
+	g1.MetaSetMut("bar", map[string]interface{}{"baz": true})
+
+	g2 := g1.Copy()
+	g2.MetaSetMut("foo", "changed")
+
+	v, exists := g1.MetaGetMut("foo")
+	require.True(t, exists)
+	assert.Equal(t, int64(10), v)
+
+	s, exists := g1.MetaGet("foo")
+	require.True(t, exists)
+	assert.Equal(t, "10", s)
+
+	s, _ = g1.MetaGet("bar")
+	assert.Equal(t, `{"baz":true}`, s)
+
+	v, _ = g2.MetaGetMut("foo")
+	assert.Equal(t, "changed", v)
+
+	walked := map[string]interface{}{}
+	require.NoError(t, g1.MetaWalkMut(func(k string, v interface{}) error {
+		walked[k] = v
+		return nil
+	}))
+	assert.Equal(t, map[string]interface{}{
+		"foo": int64(10),
+		"bar": map[string]interface{}{"baz": true},
+	}, walked)
+}
+
 func TestMessageQuery(t *testing.T) {
 	p := message.NewPart([]byte(`{"foo":"bar"}`))
 	p.MetaSet("foo", "bar")